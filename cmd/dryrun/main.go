@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"raspyx/config"
+	"raspyx/internal/app"
+)
+
+// Dry run of schedule parser: fetches rasp.dmami.ru and reports which groups, dictionary entities and pairs
+// would be inserted or deleted, without writing to postgres and redis.
+//
+//	go run ./cmd/dryrun -out report.json [group...]
+func main() {
+	out := flag.String("out", "", "File to write JSON report to, stdout if empty")
+	flag.Parse()
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	err = app.DryRun(cfg, *out, flag.Args())
+	if err != nil {
+		log.Fatalf("Dry run error: %s", err)
+	}
+}
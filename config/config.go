@@ -4,18 +4,22 @@ import (
 	"fmt"
 	"github.com/caarlos0/env/v11"
 	"github.com/joho/godotenv"
+	"strings"
 )
 
 type (
 	Config struct {
-		App    App
-		Log    Log
-		HTTP   HTTP
-		PG     PG
-		JWT    JWT
-		Redis  Redis
-		Parser Parser
-		RL     RateLimiter
+		App     App
+		Log     Log
+		HTTP    HTTP
+		PG      PG
+		JWT     JWT
+		OIDC    OIDC
+		Redis   Redis
+		Cache   Cache
+		Parser  Parser
+		RL      RateLimiter
+		Webhook Webhook
 	}
 	App struct {
 		Name    string `env:"APP_NAME,required"`
@@ -28,6 +32,10 @@ type (
 
 	HTTP struct {
 		Port string `env:"HTTP_PORT,required"`
+		// Seconds clients and CDN may use schedule without revalidation
+		CacheMaxAge int `env:"HTTP_CACHE_MAX_AGE,required"`
+		// Comma separated addresses or CIDRs of proxies trusted to set X-Forwarded-For, none if empty
+		TrustedProxies string `env:"HTTP_TRUSTED_PROXIES,required"`
 	}
 
 	PG struct {
@@ -38,22 +46,83 @@ type (
 
 	JWT struct {
 		JWTSecret string `env:"JWT_SECRET,required"`
+		// Minutes
+		AccessTTL int `env:"JWT_ACCESS_TTL,required"`
+		// Hours
+		RefreshTTL int `env:"JWT_REFRESH_TTL,required"`
+	}
+
+	// OIDC login with university SSO, empty issuer disables it
+	OIDC struct {
+		Issuer       string `env:"OIDC_ISSUER,required"`
+		ClientID     string `env:"OIDC_CLIENT_ID,required"`
+		ClientSecret string `env:"OIDC_CLIENT_SECRET,required"`
+		RedirectURL  string `env:"OIDC_REDIRECT_URL,required"`
+		// Claim used as username of provisioned user, email is used if it is empty
+		UsernameClaim string `env:"OIDC_USERNAME_CLAIM,required"`
+		// Claim with groups of user, string or list of strings
+		RoleClaim string `env:"OIDC_ROLE_CLAIM,required"`
+		// Comma separated claim values to roles, e.g. "staff=editor,dispatchers=moderator"
+		RoleMapping string `env:"OIDC_ROLE_MAPPING,required"`
 	}
 
 	Redis struct {
 		REDIS_URL string `env:"REDIS_URL,required"`
 	}
 
+	// Seconds, cached queries are dropped on every change of their pairs, 0 disables caching
+	Cache struct {
+		// Schedule of group, teacher, room, subject or location
+		ScheduleTTL int `env:"CACHE_SCHEDULE_TTL,required"`
+		// Schedule of all groups
+		ScheduleAllTTL int `env:"CACHE_SCHEDULE_ALL_TTL,required"`
+	}
+
 	Parser struct {
 		Timeout int `env:"PARSER_TIMEOUT,required"`
+		// dmami, dir or table
+		Source string `env:"PARSER_SOURCE,required"`
+		// Directory of saved responses for dir source, .csv or .xlsx file for table source
+		SourcePath  string  `env:"PARSER_SOURCE_PATH,required"`
+		Concurrency int     `env:"PARSER_CONCURRENCY,required"`
+		RPS         float64 `env:"PARSER_RPS,required"`
+		Retries     int     `env:"PARSER_RETRIES,required"`
 	}
 
+	// Limits are requests per second, burst is requests allowed at once
 	RateLimiter struct {
+		// Anonymous clients, per ip
 		Limit float64 `env:"RL_LIMIT,required"`
 		Burst int     `env:"RL_BURST,required"`
+		// Users with valid access token, per user
+		UserLimit float64 `env:"RL_USER_LIMIT,required"`
+		UserBurst int     `env:"RL_USER_BURST,required"`
+		// Limit and burst of each API key
+		KeyLimit float64 `env:"RL_API_KEY_LIMIT,required"`
+		KeyBurst int     `env:"RL_API_KEY_BURST,required"`
+		// Login, registration and refresh per ip, limit is requests per minute
+		LoginLimit float64 `env:"RL_LOGIN_LIMIT,required"`
+		LoginBurst int     `env:"RL_LOGIN_BURST,required"`
+	}
+
+	Webhook struct {
+		Interval    int `env:"WEBHOOK_INTERVAL,required"`
+		Timeout     int `env:"WEBHOOK_TIMEOUT,required"`
+		MaxAttempts int `env:"WEBHOOK_MAX_ATTEMPTS,required"`
 	}
 )
 
+// Proxies returns trusted proxies, client ip is read from X-Forwarded-For only behind them
+func (h HTTP) Proxies() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(h.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 	err := godotenv.Load(".env")
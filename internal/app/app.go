@@ -8,6 +8,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	swaggerFiles "github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -17,7 +18,14 @@ import (
 	httpv1 "raspyx/internal/delivery/http"
 	mw "raspyx/internal/delivery/http/middleware"
 	v1 "raspyx/internal/delivery/http/v1"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/oidc"
 	"raspyx/internal/parser"
+	"raspyx/internal/repository/postgres"
+	myredis "raspyx/internal/repository/redis"
+	"raspyx/internal/usecase"
+	"raspyx/internal/webhook"
 	"strconv"
 	"strings"
 	"syscall"
@@ -31,7 +39,7 @@ func Run(cfg *config.Config) {
 	defer stop()
 
 	// Logger setup
-	log, err := setupLogger(cfg)
+	log, err := setupLogger(cfg, os.Stdout)
 	if err != nil {
 		log.Error(fmt.Sprintf("error setting up loger: %v", err))
 		return
@@ -40,6 +48,13 @@ func Run(cfg *config.Config) {
 	log.Info(fmt.Sprintf("starting %v v%v", cfg.App.Name, cfg.App.Version), slog.String("logLevel", cfg.Log.Level))
 	log.Debug("debug messages are enabled")
 
+	// Schedule source of parser
+	source, err := parser.NewSource(cfg.Parser, 10*time.Second)
+	if err != nil {
+		log.Error(fmt.Sprintf("error schedule source: %v", err))
+		return
+	}
+
 	// db connection
 	conn, err := InitDBPool(ctx, cfg, log)
 	if err != nil {
@@ -56,19 +71,49 @@ func Run(cfg *config.Config) {
 	}
 	defer redisClient.Close()
 
+	// OIDC provider of university SSO, login with it is disabled without issuer
+	var oidcProvider interfaces.OIDCProvider
+	if cfg.OIDC.Issuer != "" {
+		if _, err := services.NewUserService().ParseRoleMapping(cfg.OIDC.RoleMapping); err != nil {
+			log.Error(fmt.Sprintf("error oidc config: %v", err))
+			return
+		}
+		provider, err := oidc.NewProvider(ctx, cfg.OIDC)
+		if err != nil {
+			log.Error(fmt.Sprintf("error oidc provider: %v", err))
+			return
+		}
+		oidcProvider = provider
+	}
+
 	// Router
 	r := gin.New()
+	// Client ip of rate limiter is read from X-Forwarded-For only behind trusted proxies
+	if err := r.SetTrustedProxies(cfg.HTTP.Proxies()); err != nil {
+		log.Error(fmt.Sprintf("error trusted proxies: %v", err))
+		return
+	}
 
 	// Middlewares
 	r.Use(mw.Logger(log))
 	r.Use(mw.PrometheusMiddleware())
 	r.Use(mw.RequestIDMiddleware())
-	RLStorage := mw.NewRateLimiterStorage()
-	r.Use(mw.RateLimiter(ctx, cfg.RL, RLStorage))
+	// API keys of machine clients, rate limiter gives them their own buckets
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(postgres.NewAPIKeyRepository(conn), *services.NewAPIKeyService())
+	r.Use(mw.RateLimiter(
+		myredis.NewRateLimiter(redisClient),
+		mw.NewRateLimitPolicies(cfg.RL),
+		cfg.JWT,
+		apiKeyUseCase,
+		log,
+	))
 	r.Use(gin.Recovery())
 
+	// Schedule parser, admin routes start its runs on demand
+	scheduleParser := parser.NewScheduleParser(source, conn, redisClient, log, cfg.Parser, cfg.Cache)
+
 	// All routes
-	httpv1.NewRouter(r, log, conn, redisClient, cfg)
+	httpv1.NewRouter(r, log, conn, redisClient, cfg, scheduleParser, apiKeyUseCase, oidcProvider)
 
 	// Prometheus metrics
 	r.GET("/metrics", mw.PrometheusHandler())
@@ -95,8 +140,17 @@ func Run(cfg *config.Config) {
 		}
 	}()
 
+	// Webhook deliveries
+	go webhook.NewDispatcher(
+		time.Duration(cfg.Webhook.Timeout)*time.Second,
+		postgres.NewWebhookDeliveryRepository(conn),
+		*services.NewWebhookService(),
+		log,
+		cfg.Webhook,
+	).Run(ctx)
+
 	// Schedule parser
-	parser.NewScheduleParser(10*time.Second, conn, redisClient, log, cfg.Parser).New(ctx)
+	scheduleParser.New(ctx)
 
 	// shutdown
 	<-ctx.Done()
@@ -170,7 +224,7 @@ func cacheClient(ctx context.Context, cfg *config.Config) (*redis.Client, error)
 	return redisClient, nil
 }
 
-func setupLogger(cfg *config.Config) (*slog.Logger, error) {
+func setupLogger(cfg *config.Config, w io.Writer) (*slog.Logger, error) {
 	var log *slog.Logger
 	var err error
 
@@ -183,9 +237,9 @@ func setupLogger(cfg *config.Config) (*slog.Logger, error) {
 
 	switch strings.TrimSpace(cfg.Log.Type) {
 	case "text":
-		handler = slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: *level})
+		handler = slog.NewTextHandler(w, &slog.HandlerOptions{Level: *level})
 	case "json":
-		handler = slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: *level})
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{Level: *level})
 	default:
 		return nil, fmt.Errorf("invalid LOG_TYPE=%v", cfg.Log.Type)
 	}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"raspyx/config"
+	"raspyx/internal/parser"
+	"syscall"
+	"time"
+)
+
+// DryRun runs schedule parser without writing anything and saves its JSON report to out (stdout if empty).
+// Only given groups are parsed if any
+func DryRun(cfg *config.Config, out string, groups []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Logs go to stderr to keep stdout for report
+	log, err := setupLogger(cfg, os.Stderr)
+	if err != nil {
+		return fmt.Errorf("error setting up loger: %w", err)
+	}
+
+	source, err := parser.NewSource(cfg.Parser, 10*time.Second)
+	if err != nil {
+		return fmt.Errorf("error schedule source: %w", err)
+	}
+
+	// Every transaction of dry run is read only, so parser cannot change db even by mistake
+	pgURL, err := url.Parse(cfg.PG.PGURL)
+	if err != nil {
+		return fmt.Errorf("error parsing PG_URL: %w", err)
+	}
+	query := pgURL.Query()
+	query.Set("default_transaction_read_only", "on")
+	pgURL.RawQuery = query.Encode()
+
+	readOnlyCfg := *cfg
+	readOnlyCfg.PG.PGURL = pgURL.String()
+
+	conn, err := InitDBPool(ctx, &readOnlyCfg, log)
+	if err != nil {
+		return fmt.Errorf("error db connection: %w", err)
+	}
+	defer conn.Close()
+
+	report, err := parser.NewScheduleParser(source, conn, nil, log, cfg.Parser, cfg.Cache).DryRun(ctx, groups...)
+	if err != nil {
+		return fmt.Errorf("error parsing schedule: %w", err)
+	}
+
+	// Writing report
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(append(data, '\n'))
+	} else {
+		err = os.WriteFile(out, data, 0o644)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing report: %w", err)
+	}
+
+	fmt.Fprintln(os.Stderr, report.Summary)
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+const apiKeyAuthKey = "api_key_auth"
+
+type apiKeyAuth struct {
+	key *models.APIKey
+	err error
+}
+
+// apiKeyFromRequest returns key sent in X-API-Key or "Authorization: ApiKey <key>" header
+func apiKeyFromRequest(c *gin.Context) string {
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "ApiKey ") {
+		return strings.TrimPrefix(authHeader, "ApiKey ")
+	}
+	return ""
+}
+
+// authenticateAPIKey returns key of request, result is kept in context so rate limiter and auth look key up once
+func authenticateAPIKey(c *gin.Context, keys interfaces.APIKeyAuthenticator, secret string) (*models.APIKey, error) {
+	if v, ok := c.Get(apiKeyAuthKey); ok {
+		auth := v.(*apiKeyAuth)
+		return auth.key, auth.err
+	}
+
+	key, err := keys.Authenticate(c, secret)
+	c.Set(apiKeyAuthKey, &apiKeyAuth{key: key, err: err})
+	return key, err
+}
@@ -1,44 +1,112 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"net/http"
 	"raspyx/config"
 	v1 "raspyx/internal/delivery/http/v1"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/usecase"
 	"strings"
 )
 
-func AuthMiddleware(JWT config.JWT) gin.HandlerFunc {
+// AuthMiddleware accepts access token of user or API key of machine client
+func AuthMiddleware(JWT config.JWT, denylist interfaces.TokenDenylist, keys interfaces.APIKeyAuthenticator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		authHeader := c.GetHeader("Authorization")
-		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError("authorization header required"))
+		if secret := apiKeyFromRequest(c); secret != "" {
+			key, err := authenticateAPIKey(c, keys, secret)
+			if errors.Is(err, usecase.ErrInvalidAPIKey) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError("invalid api key"))
+				return
+			}
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusInternalServerError, v1.RespError("internal server error"))
+				return
+			}
+
+			// Keys are not users, scopes of key are its permissions
+			c.Set("username", "apikey:"+key.Name)
+			c.Set("api_key", key.UUID.String())
+			c.Set("permissions", key.Scopes)
+			c.Next()
 			return
 		}
 
-		tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
-
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrInvalidKeyType
-			}
-			return []byte(JWT.JWTSecret), nil
-		}, jwt.WithValidMethods([]string{"HS256"}))
-
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError("invalid token"))
+		claims, err := parseAccessToken(c, JWT)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError(err.Error()))
 			return
 		}
 
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
+		// Tokens without jti can not be revoked, so they are not accepted
+		jti, _ := claims["jti"].(string)
+		exp, err := claims.GetExpirationTime()
+		if jti == "" || err != nil || exp == nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError("invalid claims"))
 			return
 		}
 
+		denied, err := denylist.IsDenied(c, jti)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, v1.RespError("internal server error"))
+			return
+		}
+		if denied {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, v1.RespError("token revoked"))
+			return
+		}
+
+		// Permissions are decoded as []any
+		raw, _ := claims["permissions"].([]any)
+		permissions := make([]string, 0, len(raw))
+		for _, p := range raw {
+			if permission, ok := p.(string); ok {
+				permissions = append(permissions, permission)
+			}
+		}
+
 		c.Set("username", claims["sub"])
-		c.Set("access_level", claims["access_level"])
+		c.Set("user_uuid", claims["uid"])
+		c.Set("role", claims["role"])
+		c.Set("permissions", permissions)
+		c.Set("jti", jti)
+		c.Set("exp", exp.Time)
 		c.Next()
 	}
 }
+
+var (
+	errNoToken       = errors.New("authorization header required")
+	errInvalidToken  = errors.New("invalid token")
+	errInvalidClaims = errors.New("invalid claims")
+)
+
+// parseAccessToken returns claims of bearer token of request once its signature and expiry are checked
+func parseAccessToken(c *gin.Context, JWT config.JWT) (jwt.MapClaims, error) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+		return nil, errNoToken
+	}
+
+	tokenStr := strings.TrimPrefix(authHeader, "Bearer ")
+
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrInvalidKeyType
+		}
+		return []byte(JWT.JWTSecret), nil
+	}, jwt.WithValidMethods([]string{"HS256"}))
+
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errInvalidClaims
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	v1 "raspyx/internal/delivery/http/v1"
+	"slices"
+)
+
+// PermissionMiddleware allows request if role of user has given permission
+func PermissionMiddleware(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		permissions := c.GetStringSlice("permissions")
+		if !slices.Contains(permissions, permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, v1.RespError(fmt.Sprintf("forbidden, %v permission required", permission)))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"raspyx/internal/domain/interfaces"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time when ETag of resource last changed is kept this long
+const lastModifiedTTL = 7 * 24 * time.Hour
+
+// bufferedWriter keeps response of handler, so it is sent only if client has no copy of it
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body   bytes.Buffer
+	status int
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+// ConditionalGet adds ETag, Last-Modified and Cache-Control to successful GET responses
+// and answers 304 Not Modified if client sends ETag or date of its copy.
+// ETag is hash of response, Last-Modified is time when ETag of resource last changed.
+// Resource is route with its path params and given query params, other query params do not make new resource.
+// Public responses may be kept by shared caches (CDN), private ones only by client
+func ConditionalGet(cache interfaces.Cache, maxAge time.Duration, public bool, params ...string) gin.HandlerFunc {
+	cacheControl := "private"
+	if public {
+		cacheControl = "public"
+	}
+	cacheControl += ", max-age=" + strconv.Itoa(int(maxAge.Seconds())) + ", must-revalidate"
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		writer := c.Writer
+		buffered := &bufferedWriter{ResponseWriter: writer, status: http.StatusOK}
+		c.Writer = buffered
+		c.Next()
+		c.Writer = writer
+
+		if buffered.status != http.StatusOK {
+			writer.WriteHeader(buffered.status)
+			_, _ = writer.Write(buffered.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buffered.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		lastModified := changedAt(c, cache, resourceKey(c, params), etag)
+
+		header := writer.Header()
+		header.Set("ETag", etag)
+		header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+		header.Set("Cache-Control", cacheControl)
+
+		if notModified(c.Request, etag, lastModified) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			writer.WriteHeader(http.StatusNotModified)
+			writer.WriteHeaderNow()
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+		_, _ = writer.Write(buffered.body.Bytes())
+	}
+}
+
+// resourceKey returns key of resource of request: route, values of path params and values of given query params.
+// Only successful responses are keyed, so number of keys is bound by number of existing resources
+func resourceKey(c *gin.Context, params []string) string {
+	var key strings.Builder
+	key.WriteString(c.FullPath())
+	for _, param := range c.Params {
+		key.WriteString("\x00" + param.Value)
+	}
+	for _, param := range params {
+		key.WriteString("\x00" + c.Query(param))
+	}
+
+	sum := sha256.Sum256([]byte(key.String()))
+	return "http:modified:" + hex.EncodeToString(sum[:16])
+}
+
+// changedAt returns time when ETag of resource last changed. Only the last ETag is kept and new time is always
+// after the previous one, so client with copy of previous version does not get 304 for the new one.
+// Current time is returned if cache is unavailable
+func changedAt(c *gin.Context, cache interfaces.Cache, key, etag string) time.Time {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	if value, err := cache.Get(c, key); err == nil {
+		if cached, unix, ok := strings.Cut(value, " "); ok {
+			if unix, err := strconv.ParseInt(unix, 10, 64); err == nil {
+				changed := time.Unix(unix, 0).UTC()
+				if cached == etag {
+					return changed
+				}
+				if !now.After(changed) {
+					now = changed.Add(time.Second)
+				}
+			}
+		}
+	}
+
+	_ = cache.Set(c, key, etag+" "+strconv.FormatInt(now.Unix(), 10), lastModifiedTTL)
+
+	return now
+}
+
+// notModified tells client already has response, If-Modified-Since is checked only without If-None-Match
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		if err == nil && !lastModified.After(since) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// mapCache keeps values in memory, expiration is ignored
+type mapCache map[string]string
+
+func (c mapCache) Set(_ context.Context, key string, value string, _ time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c mapCache) Get(_ context.Context, key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", errors.New("cache miss")
+	}
+	return value, nil
+}
+
+func (c mapCache) Delete(_ context.Context, key string) error {
+	delete(c, key)
+	return nil
+}
+
+func TestConditionalGet(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	week := "monday"
+	r := gin.New()
+	r.Use(ConditionalGet(mapCache{}, time.Minute, true))
+	r.GET("/schedule", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"week": week}) })
+	r.GET("/missing", func(c *gin.Context) { c.JSON(http.StatusNotFound, gin.H{"error": "not found"}) })
+
+	do := func(path string, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/schedule", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expected schedule with ETag, got %v %q %q", w.Code, etag, w.Body.String())
+	}
+	if w.Header().Get("Last-Modified") == "" || w.Header().Get("Cache-Control") != "public, max-age=60, must-revalidate" {
+		t.Fatalf("unexpected cache headers: %v", w.Header())
+	}
+
+	w = do("/schedule", http.Header{"If-None-Match": {`"other", ` + etag}})
+	if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != etag {
+		t.Fatalf("expected 304 for current ETag, got %v %q", w.Code, w.Body.String())
+	}
+
+	w = do("/schedule", http.Header{"If-Modified-Since": {time.Now().UTC().Format(http.TimeFormat)}})
+	if w.Code != http.StatusNotModified {
+		t.Fatalf("expected 304 for current date, got %v", w.Code)
+	}
+
+	// Changed schedule has other ETag
+	week = "tuesday"
+	w = do("/schedule", http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Fatalf("expected changed schedule, got %v %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	// Errors are passed as is
+	w = do("/missing", nil)
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" || w.Body.Len() == 0 {
+		t.Fatalf("expected 404 without ETag, got %v %v", w.Code, w.Header())
+	}
+}
+
+func TestConditionalGet_resourceKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	week := "monday"
+	cache := mapCache{}
+	r := gin.New()
+	r.Use(ConditionalGet(cache, time.Minute, true, "week"))
+	r.GET("/schedule/:group", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"week": week}) })
+
+	do := func(path string, header http.Header) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		for key, values := range header {
+			req.Header[key] = values
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Unknown query params and changes of response do not add keys
+	for i := range 5 {
+		week = []string{"monday", "tuesday"}[i%2]
+		do("/schedule/221-352?cache-buster="+strconv.Itoa(i), nil)
+	}
+	do("/schedule/221-352?week=2", nil)
+	do("/schedule/221-351", nil)
+	if len(cache) != 3 {
+		t.Fatalf("expected key per group and week, got %v keys", len(cache))
+	}
+
+	// Return to previous response moves Last-Modified forward
+	week = "monday"
+	a := do("/schedule/221-350", nil)
+	week = "tuesday"
+	b := do("/schedule/221-350", nil)
+	week = "monday"
+	w := do("/schedule/221-350", http.Header{"If-Modified-Since": {b.Header().Get("Last-Modified")}})
+	if w.Code != http.StatusOK || w.Header().Get("ETag") != a.Header().Get("ETag") {
+		t.Fatalf("expected previous response for copy of changed one, got %v", w.Code)
+	}
+	modified, _ := http.ParseTime(w.Header().Get("Last-Modified"))
+	changed, _ := http.ParseTime(b.Header().Get("Last-Modified"))
+	if !modified.After(changed) {
+		t.Fatalf("expected Last-Modified after %v, got %v", changed, modified)
+	}
+}
@@ -1,46 +1,106 @@
 package middleware
 
 import (
-	"context"
+	"fmt"
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"log/slog"
+	"math"
 	"net/http"
 	"raspyx/config"
 	v1 "raspyx/internal/delivery/http/v1"
-	"sync"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"strconv"
+	"time"
 )
 
-type RateLimiterStorage struct {
-	limits map[string]*rate.Limiter
-	mu     sync.Mutex
+// RateLimitPolicies are policies of rate limiter by identity of client and of stricter route groups
+type RateLimitPolicies struct {
+	IP     models.RateLimitPolicy
+	User   models.RateLimitPolicy
+	APIKey models.RateLimitPolicy
+	// Requests with API key per ip, checked before key is looked up in db
+	APIKeyIP models.RateLimitPolicy
+	// Login, registration and refresh of tokens, limited per ip against brute force
+	Login models.RateLimitPolicy
 }
 
-func NewRateLimiterStorage() *RateLimiterStorage {
-	return &RateLimiterStorage{
-		limits: make(map[string]*rate.Limiter),
+func NewRateLimitPolicies(rl config.RateLimiter) RateLimitPolicies {
+	return RateLimitPolicies{
+		IP:     models.RateLimitPolicy{Name: "ip", Limit: rl.Limit, Burst: rl.Burst},
+		User:   models.RateLimitPolicy{Name: "user", Limit: rl.UserLimit, Burst: rl.UserBurst},
+		APIKey: models.RateLimitPolicy{Name: "apikey", Limit: rl.KeyLimit, Burst: rl.KeyBurst},
+		// Client of one key is not limited more than by key itself
+		APIKeyIP: models.RateLimitPolicy{Name: "apikey_ip", Limit: rl.KeyLimit, Burst: rl.KeyBurst},
+		Login:    models.RateLimitPolicy{Name: "login", Limit: rl.LoginLimit / 60, Burst: rl.LoginBurst},
 	}
 }
 
-func (s *RateLimiterStorage) GetOrCreate(key string, r rate.Limit, b int) *rate.Limiter {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// RateLimiter limits requests per identity of client: API key, user of valid access token or ip.
+// Invalid keys and tokens fall back to ip, so random ones do not bypass limit.
+// Requests with API key are limited per ip first, so random keys do not cost a query to db each
+func RateLimiter(limiter interfaces.RateLimiter, policies RateLimitPolicies, JWT config.JWT, keys interfaces.APIKeyAuthenticator, log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policy, identity := policies.IP, c.ClientIP()
+		if secret := apiKeyFromRequest(c); secret != "" {
+			if !allow(c, limiter, policies.APIKeyIP, identity, log) {
+				return
+			}
+			if key, err := authenticateAPIKey(c, keys, secret); err == nil {
+				policy, identity = policies.APIKey, key.UUID.String()
+			}
+		} else if claims, err := parseAccessToken(c, JWT); err == nil {
+			if uid, _ := claims["uid"].(string); uid != "" {
+				policy, identity = policies.User, uid
+			}
+		}
 
-	if limiter, exists := s.limits[key]; exists {
-		return limiter
+		limit(c, limiter, policy, identity, log)
 	}
-
-	limiter := rate.NewLimiter(r, b)
-	s.limits[key] = limiter
-	return limiter
 }
 
-func RateLimiter(ctx context.Context, rl config.RateLimiter, storage *RateLimiterStorage) gin.HandlerFunc {
+// RateLimit limits requests of route group per ip with its own policy
+func RateLimit(limiter interfaces.RateLimiter, policy models.RateLimitPolicy, log *slog.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		//storage.GetOrCreate(c.ClientIP(), rate.Limit(rl.Limit), rl.Burst).Wait(ctx)
-		if !storage.GetOrCreate(c.ClientIP(), rate.Limit(rl.Limit), rl.Burst).Allow() {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, v1.RespError("too many requests, please try again later"))
-			return
-		}
+		limit(c, limiter, policy, c.ClientIP(), log)
+	}
+}
+
+func limit(c *gin.Context, limiter interfaces.RateLimiter, policy models.RateLimitPolicy, identity string, log *slog.Logger) {
+	if allow(c, limiter, policy, identity, log) {
 		c.Next()
 	}
 }
+
+// allow takes request from bucket of identity and sets RateLimit headers, request is aborted with 429 if bucket is empty
+func allow(c *gin.Context, limiter interfaces.RateLimiter, policy models.RateLimitPolicy, identity string, log *slog.Logger) bool {
+	// Policy with zero limit or burst is disabled
+	if policy.Limit <= 0 || policy.Burst <= 0 {
+		return true
+	}
+
+	result, err := limiter.Allow(c, policy, identity)
+	if err != nil {
+		// API stays available without redis, requests are not limited then
+		log.Warn("rate limiter is unavailable", slog.String("policy", policy.Name), slog.String("error", err.Error()))
+		return true
+	}
+
+	// Headers of IETF RateLimit fields draft, window is time to refill burst
+	window := math.Ceil(float64(policy.Burst) / policy.Limit)
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%.0f", policy.Burst, window))
+	c.Header("RateLimit-Limit", strconv.Itoa(policy.Burst))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+	if !result.Allowed {
+		c.Header("Retry-After", strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, v1.RespError("too many requests, please try again later"))
+		return false
+	}
+	return true
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
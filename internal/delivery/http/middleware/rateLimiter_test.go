@@ -0,0 +1,164 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"raspyx/config"
+	"raspyx/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// fakeLimiter allows burst of policy per identity and records buckets used
+type fakeLimiter struct {
+	used map[string]int
+}
+
+func (l *fakeLimiter) Allow(_ context.Context, policy models.RateLimitPolicy, identity string) (*models.RateLimitResult, error) {
+	key := policy.Name + ":" + identity
+	l.used[key]++
+	if l.used[key] > policy.Burst {
+		return &models.RateLimitResult{ResetAfter: 2 * time.Second, RetryAfter: 1500 * time.Millisecond}, nil
+	}
+	return &models.RateLimitResult{Allowed: true, Remaining: policy.Burst - l.used[key], ResetAfter: time.Second}, nil
+}
+
+func TestRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	JWT := config.JWT{JWTSecret: "secret"}
+	policies := NewRateLimitPolicies(config.RateLimiter{
+		Limit: 1, Burst: 2, UserLimit: 1, UserBurst: 3, KeyLimit: 1, KeyBurst: 5, LoginLimit: 60, LoginBurst: 1,
+	})
+	limiter := &fakeLimiter{used: map[string]int{}}
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	r := gin.New()
+	r.Use(RateLimiter(limiter, policies, JWT, nil, log))
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/login", RateLimit(limiter, policies.Login, log), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"uid": "c555b9e8-0d7a-11f0-adcd-20114d2008d9",
+		"exp": time.Now().Add(time.Minute).Unix(),
+	}).SignedString([]byte(JWT.JWTSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	do := func(method, path, auth string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous client gets burst of ip policy
+	for i := 0; i < 2; i++ {
+		if w := do(http.MethodGet, "/", ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d status = %v, want 200", i, w.Code)
+		}
+	}
+	w := do(http.MethodGet, "/", "")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("limited request status = %v, Retry-After = %q", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	// User is limited by own bucket, not by ip
+	w = do(http.MethodGet, "/", "Bearer "+token)
+	if w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "3" || w.Header().Get("RateLimit-Remaining") != "2" {
+		t.Errorf("user request status = %v, headers = %v", w.Code, w.Header())
+	}
+
+	// Forged token falls back to ip
+	if w := do(http.MethodGet, "/", "Bearer forged"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("forged token status = %v, want 429", w.Code)
+	}
+
+	// Login has its own stricter policy
+	if w := do(http.MethodPost, "/login", "Bearer "+token); w.Code != http.StatusOK || w.Header().Get("RateLimit-Policy") != "1;w=1" {
+		t.Errorf("login status = %v, headers = %v", w.Code, w.Header())
+	}
+	if w := do(http.MethodPost, "/login", "Bearer "+token); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second login status = %v, want 429", w.Code)
+	}
+}
+
+// countingKeys rejects every key and counts lookups
+type countingKeys struct {
+	lookups int
+}
+
+func (k *countingKeys) Authenticate(_ context.Context, _ string) (*models.APIKey, error) {
+	k.lookups++
+	return nil, errors.New("api key is invalid")
+}
+
+func TestRateLimiter_clientIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	policies := NewRateLimitPolicies(config.RateLimiter{
+		Limit: 1, Burst: 100, KeyLimit: 1, KeyBurst: 2, LoginLimit: 60, LoginBurst: 1,
+	})
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	newRouter := func(proxies string) (*gin.Engine, *fakeLimiter, *countingKeys) {
+		limiter, keys := &fakeLimiter{used: map[string]int{}}, &countingKeys{}
+		r := gin.New()
+		if err := r.SetTrustedProxies(config.HTTP{TrustedProxies: proxies}.Proxies()); err != nil {
+			t.Fatal(err)
+		}
+		r.Use(RateLimiter(limiter, policies, config.JWT{}, keys, log))
+		r.POST("/login", RateLimit(limiter, policies.Login, log), func(c *gin.Context) { c.Status(http.StatusOK) })
+		return r, limiter, keys
+	}
+
+	do := func(r *gin.Engine, forwardedFor, apiKey string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/login", nil)
+		req.RemoteAddr = "10.0.0.1:4321"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Spoofed X-Forwarded-For does not give new login bucket without trusted proxies
+	r, _, _ := newRouter("")
+	if code := do(r, "203.0.113.1", ""); code != http.StatusOK {
+		t.Fatalf("first login status = %v, want 200", code)
+	}
+	if code := do(r, "203.0.113.2", ""); code != http.StatusTooManyRequests {
+		t.Errorf("login with spoofed X-Forwarded-For status = %v, want 429", code)
+	}
+
+	// Behind trusted proxy every client has own bucket
+	r, _, _ = newRouter("10.0.0.0/8, 192.168.0.1")
+	if code := do(r, "203.0.113.1", ""); code != http.StatusOK {
+		t.Fatalf("first login status = %v, want 200", code)
+	}
+	if code := do(r, "203.0.113.2", ""); code != http.StatusOK {
+		t.Errorf("login of other client behind proxy status = %v, want 200", code)
+	}
+
+	// Random API keys are limited per ip before they are looked up
+	r, _, keys := newRouter("")
+	for i := 0; i < 5; i++ {
+		do(r, "", fmt.Sprintf("rpx_random%d", i))
+	}
+	if keys.lookups != 2 {
+		t.Errorf("keys looked up = %v, want 2", keys.lookups)
+	}
+}
@@ -8,93 +8,165 @@ import (
 	"raspyx/config"
 	mw "raspyx/internal/delivery/http/middleware"
 	v1 "raspyx/internal/delivery/http/v1"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
 	"raspyx/internal/domain/services"
 	"raspyx/internal/repository/postgres"
 	myredis "raspyx/internal/repository/redis"
 	"raspyx/internal/usecase"
+	"time"
 )
 
-func NewRouter(r *gin.Engine, log *slog.Logger, conn *pgxpool.Pool, redisClient *redis.Client, cfg *config.Config) {
+func NewRouter(r *gin.Engine, log *slog.Logger, conn *pgxpool.Pool, redisClient *redis.Client, cfg *config.Config, parser interfaces.ParserRunner, apiKeyUseCase *usecase.APIKeyUseCase, oidcProvider interfaces.OIDCProvider) {
+	denylist := myredis.NewTokenDenylist(redisClient)
+
 	apiV1GroupUser := r.Group("/raspyx/api/v1")
-	apiV1GroupModerator := r.Group("/raspyx/api/v1")
-	apiV1GroupModerator.Use(mw.AuthMiddleware(cfg.JWT), mw.AccessLevelMiddleware(50))
-	apiV1GroupAdmin := r.Group("/raspyx/api/v1")
-	apiV1GroupAdmin.Use(mw.AuthMiddleware(cfg.JWT), mw.AccessLevelMiddleware(99))
+	// Login routes have stricter limit against brute force
+	apiV1GroupLogin := apiV1GroupUser.Group("", mw.RateLimit(
+		myredis.NewRateLimiter(redisClient),
+		mw.NewRateLimitPolicies(cfg.RL).Login,
+		log,
+	))
+	apiV1GroupAuthorized := r.Group("/raspyx/api/v1")
+	apiV1GroupAuthorized.Use(mw.AuthMiddleware(cfg.JWT, denylist, apiKeyUseCase))
+
+	// Routes of authorized users which role has permission
+	withPermission := func(permission string) *gin.RouterGroup {
+		return apiV1GroupAuthorized.Group("", mw.PermissionMiddleware(permission))
+	}
+	apiV1GroupScheduleRead := withPermission(models.PermScheduleRead)
+	apiV1GroupScheduleWrite := withPermission(models.PermScheduleWrite)
+	apiV1GroupCatalogRead := withPermission(models.PermCatalogRead)
+	apiV1GroupCatalogWrite := withPermission(models.PermCatalogWrite)
+	apiV1GroupUsersRead := withPermission(models.PermUsersRead)
+	apiV1GroupUsersManage := withPermission(models.PermUsersManage)
+	apiV1GroupParserRead := withPermission(models.PermParserRead)
+	apiV1GroupParserRun := withPermission(models.PermParserRun)
+	apiV1GroupWebhooks := withPermission(models.PermWebhooksManage)
+
+	// Every schedule query is cached, schedule and catalog use cases drop changed ones after writes
+	scheduleCache := myredis.NewRedisCache(redisClient)
+	scheduleRepository := postgres.NewScheduleRepository(conn)
+	cachedScheduleRepository := myredis.NewCachedScheduleRepository(
+		scheduleRepository,
+		scheduleCache,
+		postgres.NewGroupRepository(conn),
+		postgres.NewTeacherRepository(conn),
+		postgres.NewRoomRepository(conn),
+		postgres.NewSubjectRepository(conn),
+		postgres.NewLocationRepository(conn),
+		time.Duration(cfg.Cache.ScheduleTTL)*time.Second,
+		time.Duration(cfg.Cache.ScheduleAllTTL)*time.Second,
+	)
+	// Bells are read by every schedule query, bell writes drop them
+	bellScheduleRepository := myredis.NewCachedBellScheduleRepository(
+		postgres.NewBellScheduleRepository(conn),
+		scheduleCache,
+		time.Duration(cfg.Cache.ScheduleTTL)*time.Second,
+	)
 
 	groupUseCase := usecase.NewGroupUseCase(
 		postgres.NewGroupRepository(conn),
 		*services.NewGroupService(),
+		cachedScheduleRepository,
 	)
 
-	v1.NewGroupRouteCreate(apiV1GroupModerator, groupUseCase, log)
-	v1.NewGroupRouteGet(apiV1GroupModerator, groupUseCase, log)
-	v1.NewGroupRouteGetByUUID(apiV1GroupModerator, groupUseCase, log)
-	v1.NewGroupRouteGetByNumber(apiV1GroupModerator, groupUseCase, log)
-	v1.NewGroupRouteUpdate(apiV1GroupModerator, groupUseCase, log)
-	v1.NewGroupRouteDelete(apiV1GroupModerator, groupUseCase, log)
+	v1.NewGroupRouteCreate(apiV1GroupCatalogWrite, groupUseCase, log)
+	v1.NewGroupRouteGet(apiV1GroupCatalogRead, groupUseCase, log)
+	v1.NewGroupRouteGetByUUID(apiV1GroupCatalogRead, groupUseCase, log)
+	v1.NewGroupRouteGetByNumber(apiV1GroupCatalogRead, groupUseCase, log)
+	v1.NewGroupRouteUpdate(apiV1GroupCatalogWrite, groupUseCase, log)
+	v1.NewGroupRouteDelete(apiV1GroupCatalogWrite, groupUseCase, log)
 
 	locationUseCase := usecase.NewLocationUseCase(
 		postgres.NewLocationRepository(conn),
 		*services.NewLocationService(),
+		cachedScheduleRepository,
 	)
 
-	v1.NewLocationRouteCreate(apiV1GroupModerator, locationUseCase, log)
-	v1.NewLocationRouteGet(apiV1GroupModerator, locationUseCase, log)
-	v1.NewLocationRouteGetByUUID(apiV1GroupModerator, locationUseCase, log)
-	v1.NewLocationRouteGetByName(apiV1GroupModerator, locationUseCase, log)
-	v1.NewLocationRouteUpdate(apiV1GroupModerator, locationUseCase, log)
-	v1.NewLocationRouteDelete(apiV1GroupModerator, locationUseCase, log)
+	v1.NewLocationRouteCreate(apiV1GroupCatalogWrite, locationUseCase, log)
+	v1.NewLocationRouteGet(apiV1GroupCatalogRead, locationUseCase, log)
+	v1.NewLocationRouteGetByUUID(apiV1GroupCatalogRead, locationUseCase, log)
+	v1.NewLocationRouteGetByName(apiV1GroupCatalogRead, locationUseCase, log)
+	v1.NewLocationRouteUpdate(apiV1GroupCatalogWrite, locationUseCase, log)
+	v1.NewLocationRouteDelete(apiV1GroupCatalogWrite, locationUseCase, log)
 
 	roomUseCase := usecase.NewRoomUseCase(
 		postgres.NewRoomRepository(conn),
+		bellScheduleRepository,
 		*services.NewRoomService(),
+		*services.NewBellScheduleService(),
+		cachedScheduleRepository,
 	)
 
-	v1.NewRoomRouteCreate(apiV1GroupModerator, roomUseCase, log)
-	v1.NewRoomRouteGet(apiV1GroupModerator, roomUseCase, log)
-	v1.NewRoomRouteGetByUUID(apiV1GroupModerator, roomUseCase, log)
-	v1.NewRoomRouteGetByNumber(apiV1GroupModerator, roomUseCase, log)
-	v1.NewRoomRouteUpdate(apiV1GroupModerator, roomUseCase, log)
-	v1.NewRoomRouteDelete(apiV1GroupModerator, roomUseCase, log)
+	v1.NewRoomRouteCreate(apiV1GroupCatalogWrite, roomUseCase, log)
+	v1.NewRoomRouteGet(apiV1GroupCatalogRead, roomUseCase, log)
+	v1.NewRoomRouteGetByUUID(apiV1GroupCatalogRead, roomUseCase, log)
+	v1.NewRoomRouteGetByNumber(apiV1GroupCatalogRead, roomUseCase, log)
+	v1.NewRoomRouteGetFree(apiV1GroupUser, roomUseCase, log)
+	v1.NewRoomRouteUpdate(apiV1GroupCatalogWrite, roomUseCase, log)
+	v1.NewRoomRouteDelete(apiV1GroupCatalogWrite, roomUseCase, log)
 
 	subjectUseCase := usecase.NewSubjectUseCase(
 		postgres.NewSubjectRepository(conn),
 		*services.NewSubjectService(),
+		cachedScheduleRepository,
 	)
 
-	v1.NewSubjectRouteCreate(apiV1GroupModerator, subjectUseCase, log)
-	v1.NewSubjectRouteGet(apiV1GroupModerator, subjectUseCase, log)
-	v1.NewSubjectRouteGetByUUID(apiV1GroupModerator, subjectUseCase, log)
-	v1.NewSubjectRouteGetByName(apiV1GroupModerator, subjectUseCase, log)
-	v1.NewSubjectRouteUpdate(apiV1GroupModerator, subjectUseCase, log)
-	v1.NewSubjectRouteDelete(apiV1GroupModerator, subjectUseCase, log)
+	v1.NewSubjectRouteCreate(apiV1GroupCatalogWrite, subjectUseCase, log)
+	v1.NewSubjectRouteGet(apiV1GroupCatalogRead, subjectUseCase, log)
+	v1.NewSubjectRouteGetByUUID(apiV1GroupCatalogRead, subjectUseCase, log)
+	v1.NewSubjectRouteGetByName(apiV1GroupCatalogRead, subjectUseCase, log)
+	v1.NewSubjectRouteUpdate(apiV1GroupCatalogWrite, subjectUseCase, log)
+	v1.NewSubjectRouteDelete(apiV1GroupCatalogWrite, subjectUseCase, log)
 
 	subjectTypeUseCase := usecase.NewSubjectTypeUseCase(
 		postgres.NewSubjectTypeRepository(conn),
 		*services.NewSubjectTypeService(),
 	)
 
-	v1.NewSubjectTypeRouteCreate(apiV1GroupModerator, subjectTypeUseCase, log)
-	v1.NewSubjectTypeRouteGet(apiV1GroupModerator, subjectTypeUseCase, log)
-	v1.NewSubjectTypeRouteGetByUUID(apiV1GroupModerator, subjectTypeUseCase, log)
-	v1.NewSubjectTypeRouteGetByType(apiV1GroupModerator, subjectTypeUseCase, log)
-	v1.NewSubjectTypeRouteUpdate(apiV1GroupModerator, subjectTypeUseCase, log)
-	v1.NewSubjectTypeRouteDelete(apiV1GroupModerator, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteCreate(apiV1GroupCatalogWrite, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteGet(apiV1GroupCatalogRead, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteGetByUUID(apiV1GroupCatalogRead, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteGetByType(apiV1GroupCatalogRead, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteUpdate(apiV1GroupCatalogWrite, subjectTypeUseCase, log)
+	v1.NewSubjectTypeRouteDelete(apiV1GroupCatalogWrite, subjectTypeUseCase, log)
 
 	teacherUseCase := usecase.NewTeacherUseCase(
 		postgres.NewTeacherRepository(conn),
 		*services.NewTeacherService(),
+		cachedScheduleRepository,
 	)
 
-	v1.NewTeacherRouteCreate(apiV1GroupModerator, teacherUseCase, log)
-	v1.NewTeacherRouteGet(apiV1GroupModerator, teacherUseCase, log)
-	v1.NewTeacherRouteGetByUUID(apiV1GroupModerator, teacherUseCase, log)
-	v1.NewTeacherRouteGetByFullName(apiV1GroupModerator, teacherUseCase, log)
-	v1.NewTeacherRouteUpdate(apiV1GroupModerator, teacherUseCase, log)
-	v1.NewTeacherRouteDelete(apiV1GroupModerator, teacherUseCase, log)
+	v1.NewTeacherRouteCreate(apiV1GroupCatalogWrite, teacherUseCase, log)
+	v1.NewTeacherRouteGet(apiV1GroupCatalogRead, teacherUseCase, log)
+	v1.NewTeacherRouteGetByUUID(apiV1GroupCatalogRead, teacherUseCase, log)
+	v1.NewTeacherRouteGetByFullName(apiV1GroupCatalogRead, teacherUseCase, log)
+	v1.NewTeacherRouteUpdate(apiV1GroupCatalogWrite, teacherUseCase, log)
+	v1.NewTeacherRouteDelete(apiV1GroupCatalogWrite, teacherUseCase, log)
+
+	searchUseCase := usecase.NewSearchUseCase(
+		postgres.NewSearchRepository(conn),
+		*services.NewSearchService(),
+	)
+
+	v1.NewSearchRoute(apiV1GroupUser, searchUseCase, log)
+
+	bellScheduleUseCase := usecase.NewBellScheduleUseCase(
+		bellScheduleRepository,
+		postgres.NewLocationRepository(conn),
+		*services.NewBellScheduleService(),
+	)
+
+	v1.NewBellScheduleRouteCreate(apiV1GroupScheduleWrite, bellScheduleUseCase, log)
+	v1.NewBellScheduleRouteGet(apiV1GroupUser, bellScheduleUseCase, log)
+	v1.NewBellScheduleRouteGetByUUID(apiV1GroupUser, bellScheduleUseCase, log)
+	v1.NewBellScheduleRouteUpdate(apiV1GroupScheduleWrite, bellScheduleUseCase, log)
+	v1.NewBellScheduleRouteDelete(apiV1GroupScheduleWrite, bellScheduleUseCase, log)
 
 	scheduleUseCase := usecase.NewScheduleUseCase(
-		postgres.NewScheduleRepository(conn),
+		scheduleRepository,
+		cachedScheduleRepository,
 		postgres.NewGroupRepository(conn),
 		postgres.NewSubjectRepository(conn),
 		postgres.NewSubjectTypeRepository(conn),
@@ -103,37 +175,127 @@ func NewRouter(r *gin.Engine, log *slog.Logger, conn *pgxpool.Pool, redisClient
 		postgres.NewRoomRepository(conn),
 		postgres.NewTeachersToScheduleRepository(conn),
 		postgres.NewRoomsToScheduleRepository(conn),
+		bellScheduleRepository,
+		postgres.NewScheduleChangeRepository(conn),
+		postgres.NewWebhookRepository(conn),
+		postgres.NewWebhookDeliveryRepository(conn),
+		postgres.NewUserScopeRepository(conn),
 		*services.NewScheduleService(),
-		myredis.NewRedisCache(redisClient),
+		*services.NewBellScheduleService(),
+		*services.NewWebhookService(),
+		postgres.NewTxManager(conn),
+	)
+
+	// Schedule is answered with 304 if client has its current version, schedule depends only on these query params
+	scheduleMaxAge := time.Duration(cfg.HTTP.CacheMaxAge) * time.Second
+	scheduleParams := []string{"session", "date", "week"}
+	apiV1GroupUserSchedule := apiV1GroupUser.Group("", mw.ConditionalGet(scheduleCache, scheduleMaxAge, true, scheduleParams...))
+	apiV1GroupScheduleReadCached := apiV1GroupScheduleRead.Group("", mw.ConditionalGet(scheduleCache, scheduleMaxAge, false, scheduleParams...))
+
+	v1.NewScheduleRouteCreate(apiV1GroupScheduleWrite, scheduleUseCase, log)
+	v1.NewScheduleRouteGet(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByTeacher(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByTeacherUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByGroup(apiV1GroupUserSchedule, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByGroupUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByRoom(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByRoomUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetBySubject(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetBySubjectUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByLocation(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetByLocationUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByGroup(apiV1GroupUserSchedule, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByGroupUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByTeacher(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByTeacherUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByRoom(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetCalendarByRoomUUID(apiV1GroupScheduleReadCached, scheduleUseCase, log)
+	v1.NewScheduleRouteGetConflicts(apiV1GroupScheduleRead, scheduleUseCase, log)
+	v1.NewScheduleRouteGetChanges(apiV1GroupUser, scheduleUseCase, log)
+	v1.NewScheduleRouteGetChangesWithSource(apiV1GroupScheduleRead, scheduleUseCase, log)
+	v1.NewScheduleRouteGetTeacherAvailability(apiV1GroupScheduleRead, scheduleUseCase, log)
+	v1.NewScheduleRouteGetTeachersCommonAvailability(apiV1GroupScheduleRead, scheduleUseCase, log)
+	v1.NewScheduleRouteUpdate(apiV1GroupScheduleWrite, scheduleUseCase, log)
+	v1.NewScheduleRouteDelete(apiV1GroupScheduleWrite, scheduleUseCase, log)
+
+	webhookUseCase := usecase.NewWebhookUseCase(
+		postgres.NewWebhookRepository(conn),
+		postgres.NewWebhookDeliveryRepository(conn),
+		postgres.NewGroupRepository(conn),
+		postgres.NewTeacherRepository(conn),
+		postgres.NewRoomRepository(conn),
+		*services.NewWebhookService(),
 	)
 
-	v1.NewScheduleRouteCreate(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGet(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByTeacher(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByTeacherUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByGroup(apiV1GroupUser, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByGroupUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByRoom(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByRoomUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetBySubject(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetBySubjectUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByLocation(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteGetByLocationUUID(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteUpdate(apiV1GroupModerator, scheduleUseCase, log)
-	v1.NewScheduleRouteDelete(apiV1GroupModerator, scheduleUseCase, log)
+	v1.NewWebhookRouteCreate(apiV1GroupWebhooks, webhookUseCase, log)
+	v1.NewWebhookRouteGet(apiV1GroupWebhooks, webhookUseCase, log)
+	v1.NewWebhookRouteGetDeliveries(apiV1GroupWebhooks, webhookUseCase, log)
+	v1.NewWebhookRouteDelete(apiV1GroupWebhooks, webhookUseCase, log)
+
+	parserRunUseCase := usecase.NewParserRunUseCase(
+		postgres.NewParserRunRepository(conn),
+		postgres.NewGroupRepository(conn),
+		parser,
+	)
+
+	v1.NewParserRouteGetRuns(apiV1GroupParserRead, parserRunUseCase, log)
+	v1.NewParserRouteGetRunByUUID(apiV1GroupParserRead, parserRunUseCase, log)
+	v1.NewParserRouteRun(apiV1GroupParserRun, parserRunUseCase, log)
 
 	userUseCase := usecase.NewUserUseCase(
 		postgres.NewUserRepository(conn),
+		postgres.NewRoleRepository(conn),
+		postgres.NewRefreshTokenRepository(conn),
+		denylist,
 		*services.NewUserService(),
+		cfg.JWT,
+	)
+
+	v1.NewUserRouteRegister(apiV1GroupLogin, userUseCase, log)
+	v1.NewUserRouteLogin(apiV1GroupLogin, userUseCase, log)
+	v1.NewUserRouteRefresh(apiV1GroupLogin, userUseCase, log)
+	v1.NewUserRouteLogout(apiV1GroupAuthorized, userUseCase, log)
+
+	// OIDC login is optional, provider is nil without issuer
+	if oidcProvider != nil {
+		// Mapping is validated at startup
+		roleMapping, _ := services.NewUserService().ParseRoleMapping(cfg.OIDC.RoleMapping)
+		oidcUseCase := usecase.NewOIDCUseCase(
+			oidcProvider,
+			myredis.NewOIDCStateStore(redisClient),
+			postgres.NewUserIdentityRepository(conn),
+			postgres.NewUserRepository(conn),
+			userUseCase,
+			*services.NewUserService(),
+			roleMapping,
+			postgres.NewTxManager(conn),
+		)
+
+		v1.NewUserRouteOIDCLogin(apiV1GroupLogin, oidcUseCase, log)
+		v1.NewUserRouteOIDCCallback(apiV1GroupLogin, oidcUseCase, log)
+	}
+
+	v1.NewUserRouteGet(apiV1GroupUsersRead, userUseCase, log)
+	v1.NewUserRouteGetByUUID(apiV1GroupUsersRead, userUseCase, log)
+	v1.NewUserRouteGetByUsername(apiV1GroupUsersRead, userUseCase, log)
+	v1.NewUserRouteGetByRole(apiV1GroupUsersRead, userUseCase, log)
+	v1.NewUserRouteGetRoles(apiV1GroupUsersRead, userUseCase, log)
+	v1.NewUserRouteUpdate(apiV1GroupUsersManage, userUseCase, log)
+	v1.NewUserRouteDelete(apiV1GroupUsersManage, userUseCase, log)
+
+	v1.NewAPIKeyRouteCreate(apiV1GroupUsersManage, apiKeyUseCase, log)
+	v1.NewAPIKeyRouteGet(apiV1GroupUsersManage, apiKeyUseCase, log)
+	v1.NewAPIKeyRouteRevoke(apiV1GroupUsersManage, apiKeyUseCase, log)
+
+	userScopeUseCase := usecase.NewUserScopeUseCase(
+		postgres.NewUserScopeRepository(conn),
+		postgres.NewUserRepository(conn),
+		postgres.NewGroupRepository(conn),
+		postgres.NewLocationRepository(conn),
+		postgres.NewTxManager(conn),
 	)
 
-	v1.NewUserRouteRegister(apiV1GroupUser, userUseCase, log)
-	v1.NewUserRouteLogin(apiV1GroupUser, userUseCase, log, cfg.JWT)
-	v1.NewUserRouteGet(apiV1GroupModerator, userUseCase, log)
-	v1.NewUserRouteGetByUUID(apiV1GroupModerator, userUseCase, log)
-	v1.NewUserRouteGetByUsername(apiV1GroupModerator, userUseCase, log)
-	v1.NewUserRouteGetByAccessLevel(apiV1GroupModerator, userUseCase, log)
-	v1.NewUserRouteUpdate(apiV1GroupModerator, userUseCase, log)
-	v1.NewUserRouteDelete(apiV1GroupModerator, userUseCase, log)
+	v1.NewUserScopeRouteGet(apiV1GroupUsersRead, userScopeUseCase, log)
+	v1.NewUserScopeRouteUpdate(apiV1GroupUsersManage, userScopeUseCase, log)
 }
@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type apiKeyRoutes struct {
+	uc  *usecase.APIKeyUseCase
+	log *slog.Logger
+}
+
+// NewAPIKeyRouteCreate
+// @Summary Creating a new API key
+// @Description Issues key for machine client, scopes are permissions granted to key.
+// @Description Key is sent in X-API-Key header or as "Authorization: ApiKey <key>" and is shown only in this response
+// @Security ApiKeyAuth
+// @Tags api key
+// @Accept json
+// @Produce json
+// @Param key body dto.CreateAPIKeyRequest true "API key"
+// @Success 200 {object} ResponseOK{response=dto.CreateAPIKeyResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/api-keys [post]
+func NewAPIKeyRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.APIKeyUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewAPIKeyRouteCreate"
+	log = log.With(slog.String("op", op))
+
+	r := &apiKeyRoutes{uc, log}
+
+	keyGroup := apiV1Group.Group("/api-keys")
+
+	keyGroup.POST("/", func(c *gin.Context) {
+		var keyDTO dto.CreateAPIKeyRequest
+		if err := c.ShouldBindJSON(&keyDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		resp, err := r.uc.Create(c, &keyDTO)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "api_key_name",
+				logValue: keyDTO.Name,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewAPIKeyRouteGet
+// @Summary Getting API keys
+// @Description Get all API keys with their usage, secrets of keys are not stored
+// @Security ApiKeyAuth
+// @Tags api key
+// @Accept */*
+// @Produce json
+// @Success 200 {object} ResponseOK{response=[]models.APIKey}
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/api-keys/ [get]
+func NewAPIKeyRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.APIKeyUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewAPIKeyRouteGet"
+	log = log.With(slog.String("op", op))
+
+	r := &apiKeyRoutes{uc, log}
+
+	keyGroup := apiV1Group.Group("/api-keys")
+
+	keyGroup.GET("/", func(c *gin.Context) {
+		resp, err := r.uc.Get(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewAPIKeyRouteRevoke
+// @Summary Revoking API key
+// @Description Revokes API key with given uuid, requests with it are rejected at once
+// @Security ApiKeyAuth
+// @Tags api key
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "API key uuid"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/api-keys/{uuid} [delete]
+func NewAPIKeyRouteRevoke(apiV1Group *gin.RouterGroup, uc *usecase.APIKeyUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewAPIKeyRouteRevoke"
+	log = log.With(slog.String("op", op))
+
+	r := &apiKeyRoutes{uc, log}
+
+	keyGroup := apiV1Group.Group("/api-keys")
+
+	keyGroup.DELETE("/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		err := r.uc.Revoke(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "api_key_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
@@ -0,0 +1,209 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type bellScheduleRoutes struct {
+	uc  *usecase.BellScheduleUseCase
+	log *slog.Logger
+}
+
+// NewBellScheduleRouteCreate
+// @Summary Creating a new bell
+// @Description Creates a new pair slot in the bell schedule and returns its uuid. Bell without location and dates is used by default
+// @Security ApiKeyAuth
+// @Tags bell
+// @Accept json
+// @Produce json
+// @Param bell body dto.BellScheduleRequest true "Bell"
+// @Success 200 {object} ResponseOK{response=dto.CreateBellScheduleResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/bells [post]
+func NewBellScheduleRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.BellScheduleUseCase, log *slog.Logger) {
+	r := &bellScheduleRoutes{uc, log}
+
+	bellGroup := apiV1Group.Group("/bells")
+
+	bellGroup.POST("/", func(c *gin.Context) {
+		var bellDTO dto.BellScheduleRequest
+		if err := c.ShouldBindJSON(&bellDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		resp, err := r.uc.Create(c, &bellDTO)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "bell_dto",
+				logValue: bellDTO,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewBellScheduleRouteGet
+// @Summary Getting bell schedule
+// @Description Get all pair slots of the bell schedule from database
+// @Tags bell
+// @Accept */*
+// @Produce json
+// @Success 200 {object} ResponseOK{response=[]models.BellSchedule}
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/bells/ [get]
+func NewBellScheduleRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.BellScheduleUseCase, log *slog.Logger) {
+	r := &bellScheduleRoutes{uc, log}
+
+	bellGroup := apiV1Group.Group("/bells")
+
+	bellGroup.GET("/", func(c *gin.Context) {
+		resp, err := r.uc.Get(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewBellScheduleRouteGetByUUID
+// @Summary Getting bell by uuid
+// @Description Get pair slot of the bell schedule from database with given uuid
+// @Tags bell
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Bell uuid"
+// @Success 200 {object} ResponseOK{response=models.BellSchedule}
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/bells/uuid/{uuid} [get]
+func NewBellScheduleRouteGetByUUID(apiV1Group *gin.RouterGroup, uc *usecase.BellScheduleUseCase, log *slog.Logger) {
+	r := &bellScheduleRoutes{uc, log}
+
+	bellGroup := apiV1Group.Group("/bells")
+
+	bellGroup.GET("/uuid/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetByUUID(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "bell_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewBellScheduleRouteUpdate
+// @Summary Updating bell
+// @Description Update pair slot of the bell schedule in database
+// @Security ApiKeyAuth
+// @Tags bell
+// @Accept json
+// @Produce json
+// @Param uuid path string true "Bell uuid"
+// @Param bell body dto.BellScheduleRequest true "Bell"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/bells/{uuid} [put]
+func NewBellScheduleRouteUpdate(apiV1Group *gin.RouterGroup, uc *usecase.BellScheduleUseCase, log *slog.Logger) {
+	r := &bellScheduleRoutes{uc, log}
+
+	bellGroup := apiV1Group.Group("/bells")
+
+	bellGroup.PUT("/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+
+		var bellDTO dto.BellScheduleRequest
+		if err := c.ShouldBindJSON(&bellDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		err := r.uc.Update(c, reqUUID, &bellDTO)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "bell",
+				logValue: map[string]any{"uuid": reqUUID, "bell_dto": bellDTO},
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
+
+// NewBellScheduleRouteDelete
+// @Summary Deleting existing bell
+// @Description Deleting existing pair slot of the bell schedule from the database
+// @Security ApiKeyAuth
+// @Tags bell
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Bell uuid"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/bells/{uuid} [delete]
+func NewBellScheduleRouteDelete(apiV1Group *gin.RouterGroup, uc *usecase.BellScheduleUseCase, log *slog.Logger) {
+	r := &bellScheduleRoutes{uc, log}
+
+	bellGroup := apiV1Group.Group("/bells")
+
+	bellGroup.DELETE("/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		err := r.uc.Delete(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "bell_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
+	"raspyx/internal/usecase"
 	"strings"
 )
 
@@ -29,10 +30,23 @@ func mapError(err error) string {
 		{"invalid start date", "Invalid start date"},
 		{"invalid end date", "Invalid end date"},
 		{"invalid weekday", "Invalid weekday"},
+		{"invalid date", "Invalid date"},
+		{"invalid week", "Invalid week"},
+		{"invalid period", "Only one of date or week can be given"},
+		{"invalid range", "Invalid date range"},
 		{"invalid fullname", "Invalid fullname"},
+		{"invalid pair num", "Invalid pair number"},
 		{"fk error", "Object with given uuid does not exist"},
 		{"failed to generate uuid", "Failed to generate uuid"},
 		{"invalid user", "Invalid user"},
+		{"invalid role", "Invalid role"},
+		{"invalid scope", "Invalid scope, group prefix is empty"},
+		{"invalid limit", "Invalid limit"},
+		{"invalid cursor", "Invalid cursor, it belongs to other sort"},
+		{"invalid sort", "Invalid sort field"},
+		{"invalid filter", "Invalid filter"},
+		{"invalid search query", "Search query is empty or too long"},
+		{"invalid search type", "Invalid search type"},
 	}
 
 	for _, we := range withErr {
@@ -46,7 +60,6 @@ func mapError(err error) string {
 		message string
 	}{
 		{"invalid creds", "Wrong username or password"},
-		{"AccessLevel: strconv.Atoi", "Access level must be int"},
 	}
 
 	for _, woe := range withoutErr {
@@ -59,6 +72,8 @@ func mapError(err error) string {
 		repo    string
 		message string
 	}{
+		{"ParserRun", "Parser run"},
+		{"BellSchedule", "Bell schedule"},
 		{"Schedule", "Schedule"},
 		{"Group", "Group"},
 		{"Location", "Location"},
@@ -66,7 +81,10 @@ func mapError(err error) string {
 		{"Subject", "Subject"},
 		{"SubjectType", "Subject type"},
 		{"Teacher", "Teacher"},
+		{"Webhook", "Webhook"},
 		{"UserRepository", "User"},
+		{"RoleRepository", "Role"},
+		{"APIKey", "API key"},
 	}
 
 	for _, r := range repos {
@@ -108,3 +126,26 @@ func makeErrResponse(c *gin.Context, er *ErrResp) {
 	}))
 	return
 }
+
+// makeConflictResponse rejects schedule write that clashes with existing pairs
+func makeConflictResponse(c *gin.Context, log *slog.Logger, conflicts any) {
+	log.Info("Schedule conflict", slog.Any("conflicts", conflicts))
+	c.JSON(http.StatusConflict, RespError(map[string]any{
+		"error":     "Schedule clashes with existing pairs, use force=true to save it anyway",
+		"conflicts": conflicts,
+	}))
+}
+
+// makeScopeResponse rejects schedule write out of editing scopes of user, telling which scope is missing
+func makeScopeResponse(c *gin.Context, log *slog.Logger, err *usecase.ScopeError) {
+	log.Info("Schedule is out of editing scopes", slog.String("group", err.Group), slog.String("location", err.Location))
+	c.JSON(http.StatusForbidden, RespError(map[string]any{
+		"error": fmt.Sprintf(
+			"Schedule of group %v at location %v is out of your editing scopes, "+
+				"scope of the group, the location or prefix of the group number is required",
+			err.Group, err.Location,
+		),
+		"group":    err.Group,
+		"location": err.Location,
+	}))
+}
@@ -59,13 +59,18 @@ func NewGroupRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.GroupUseCase,
 
 // NewGroupRouteGet
 // @Summary Getting groups
-// @Description Get all groups from database
+// @Description Get page of groups from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Security ApiKeyAuth
 // @Tags group
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, number by default"
+// @Param filter query []string false "Filters like number~=221-, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetGroupsResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -76,14 +81,15 @@ func NewGroupRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.GroupUseCase, log
 	groupGroup := apiV1Group.Group("/groups")
 
 	groupGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -0,0 +1,16 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"raspyx/internal/dto"
+)
+
+// listParams returns limit, cursor, sort and filters of list route
+func listParams(c *gin.Context) *dto.ListParams {
+	return &dto.ListParams{
+		Limit:   c.Query("limit"),
+		Cursor:  c.Query("cursor"),
+		Sort:    c.Query("sort"),
+		Filters: c.QueryArray("filter"),
+	}
+}
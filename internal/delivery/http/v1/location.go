@@ -58,12 +58,17 @@ func NewLocationRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.LocationUse
 
 // NewLocationRouteGet
 // @Summary Getting locations
-// @Description Get all locations from database
+// @Description Get page of locations from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags location
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, name by default"
+// @Param filter query []string false "Filters like name~=Авто, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetLocationsResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -74,14 +79,15 @@ func NewLocationRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.LocationUseCas
 	locationGroup := apiV1Group.Group("/locations")
 
 	locationGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/usecase"
+)
+
+type oidcRoutes struct {
+	uc  *usecase.OIDCUseCase
+	log *slog.Logger
+}
+
+// NewUserRouteOIDCLogin
+// @Summary Logging in with university SSO
+// @Description Redirects to login page of OpenID Connect provider, provider redirects back to /users/oidc/callback
+// @Tags user
+// @Accept */*
+// @Produce json
+// @Success 302
+// @Failure 429 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/oidc/login [get]
+func NewUserRouteOIDCLogin(apiV1Group *gin.RouterGroup, uc *usecase.OIDCUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserRouteOIDCLogin"
+	log = log.With(slog.String("op", op))
+
+	r := &oidcRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.GET("/oidc/login", func(c *gin.Context) {
+		url, err := r.uc.Start(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.Redirect(http.StatusFound, url)
+	})
+}
+
+// NewUserRouteOIDCCallback
+// @Summary Finishing login with university SSO
+// @Description Exchanges code of OpenID Connect provider for the usual access token and refresh token.
+// @Description User is created on first login, role is mapped from claim of provider
+// @Tags user
+// @Accept */*
+// @Produce json
+// @Param state query string true "State of login"
+// @Param code query string true "Code of provider"
+// @Success 200 {object} ResponseOK{response=dto.LoginUserResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 429 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/oidc/callback [get]
+func NewUserRouteOIDCCallback(apiV1Group *gin.RouterGroup, uc *usecase.OIDCUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserRouteOIDCCallback"
+	log = log.With(slog.String("op", op))
+
+	r := &oidcRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.GET("/oidc/callback", func(c *gin.Context) {
+		// Provider redirects with error if user did not log in
+		if providerErr := c.Query("error"); providerErr != "" {
+			log.Info("OIDC login failed", slog.String("error", providerErr))
+			c.JSON(http.StatusUnauthorized, RespError("Login failed: "+providerErr))
+			return
+		}
+
+		state, code := c.Query("state"), c.Query("code")
+		if state == "" || code == "" {
+			c.JSON(http.StatusBadRequest, RespError("State and code are required"))
+			return
+		}
+
+		resp, err := r.uc.Callback(c, state, code)
+		if errors.Is(err, usecase.ErrInvalidOIDCLogin) {
+			log.Info("Invalid OIDC login", slog.String("error", err.Error()))
+			c.JSON(http.StatusUnauthorized, RespError("Invalid login"))
+			return
+		}
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
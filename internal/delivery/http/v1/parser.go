@@ -0,0 +1,149 @@
+package v1
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type parserRoutes struct {
+	uc  *usecase.ParserRunUseCase
+	log *slog.Logger
+}
+
+// NewParserRouteGetRuns
+// @Summary Getting parser runs
+// @Description Get last runs of schedule parser, newest first
+// @Security ApiKeyAuth
+// @Tags parser
+// @Accept */*
+// @Produce json
+// @Param limit query int false "Number of runs, 20 by default, 100 at most"
+// @Success 200 {object} ResponseOK{response=[]models.ParserRun}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/parser/runs [get]
+func NewParserRouteGetRuns(apiV1Group *gin.RouterGroup, uc *usecase.ParserRunUseCase, log *slog.Logger) {
+	r := &parserRoutes{uc, log}
+
+	parserGroup := apiV1Group.Group("/parser")
+
+	parserGroup.GET("/runs", func(c *gin.Context) {
+		limit := c.Query("limit")
+		resp, err := r.uc.Get(c, limit)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "limit",
+				logValue: limit,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewParserRouteGetRunByUUID
+// @Summary Getting parser run by uuid
+// @Description Get run of schedule parser with its counters and errors by group
+// @Security ApiKeyAuth
+// @Tags parser
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Run uuid"
+// @Success 200 {object} ResponseOK{response=models.ParserRun}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/parser/runs/{uuid} [get]
+func NewParserRouteGetRunByUUID(apiV1Group *gin.RouterGroup, uc *usecase.ParserRunUseCase, log *slog.Logger) {
+	r := &parserRoutes{uc, log}
+
+	parserGroup := apiV1Group.Group("/parser")
+
+	parserGroup.GET("/runs/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetByUUID(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "run_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewParserRouteRun
+// @Summary Running parser
+// @Description Starts schedule parser for given group or for every group if it is empty.
+// @Description Parser runs in background, its progress is got by uuid of run. Run is rejected while another one is not finished
+// @Security ApiKeyAuth
+// @Tags parser
+// @Accept json
+// @Produce json
+// @Param run body dto.ParserRunRequest false "Run"
+// @Success 202 {object} ResponseOK{response=dto.ParserRunResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 409 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Failure 503 {object} ResponseError
+// @Router /api/v1/parser/run [post]
+func NewParserRouteRun(apiV1Group *gin.RouterGroup, uc *usecase.ParserRunUseCase, log *slog.Logger) {
+	r := &parserRoutes{uc, log}
+
+	parserGroup := apiV1Group.Group("/parser")
+
+	parserGroup.POST("/run", func(c *gin.Context) {
+		var runDTO dto.ParserRunRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&runDTO); err != nil {
+				log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+				c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+				return
+			}
+		}
+
+		resp, err := r.uc.Run(c, &runDTO)
+		if errors.Is(err, usecase.ErrParserBusy) {
+			log.Info("Parser is already running")
+			c.JSON(http.StatusConflict, RespError("Parser is already running"))
+			return
+		}
+		if errors.Is(err, usecase.ErrParserNotStarted) {
+			log.Info("Parser is not started yet")
+			c.JSON(http.StatusServiceUnavailable, RespError("Parser is not started yet"))
+			return
+		}
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "group",
+				logValue: runDTO.Group,
+			})
+			return
+		}
+
+		c.JSON(http.StatusAccepted, RespOK(resp))
+	})
+}
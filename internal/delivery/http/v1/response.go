@@ -15,6 +15,10 @@ const (
 	StatusError = "Error"
 )
 
+const (
+	ContentTypeCalendar = "text/calendar; charset=utf-8"
+)
+
 func RespOK(response interface{}) ResponseOK {
 	return ResponseOK{
 		Status:   StatusOK,
@@ -62,12 +62,17 @@ func NewRoomRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.RoomUseCase, lo
 
 // NewRoomRouteGet
 // @Summary Getting rooms
-// @Description Get all rooms from database
+// @Description Get page of rooms from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags room
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, number by default"
+// @Param filter query []string false "Filters like number~=ав48, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetRoomsResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -81,14 +86,15 @@ func NewRoomRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.RoomUseCase, log *
 	roomGroup := apiV1Group.Group("/rooms")
 
 	roomGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -172,6 +178,52 @@ func NewRoomRouteGetByNumber(apiV1Group *gin.RouterGroup, uc *usecase.RoomUseCas
 	})
 }
 
+// NewRoomRouteGetFree
+// @Summary Getting free rooms
+// @Description Get rooms that are not occupied on given date in given pair or time range
+// @Tags room
+// @Accept */*
+// @Produce json
+// @Param date query string true "Date" example(2025-03-12)
+// @Param location query string false "Location name, only rooms used at the location are returned"
+// @Param pair query int false "Pair number from bell schedule"
+// @Param from query string false "Start of time range if pair is not given" example(12:20)
+// @Param to query string false "End of time range if pair is not given" example(13:50)
+// @Param prefix query string false "Room number prefix" example(ав48)
+// @Success 200 {object} ResponseOK{response=[]models.Room}
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/rooms/free [get]
+func NewRoomRouteGetFree(apiV1Group *gin.RouterGroup, uc *usecase.RoomUseCase, log *slog.Logger) {
+	r := &roomRoutes{uc, log}
+
+	roomGroup := apiV1Group.Group("/rooms")
+
+	roomGroup.GET("/free", func(c *gin.Context) {
+		filter := &dto.FreeRoomsRequest{
+			Location: c.Query("location"),
+			Date:     c.Query("date"),
+			Pair:     c.Query("pair"),
+			From:     c.Query("from"),
+			To:       c.Query("to"),
+			Prefix:   c.Query("prefix"),
+		}
+		resp, err := r.uc.GetFree(c, filter)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "free_rooms_filter",
+				logValue: filter,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
 // NewRoomRouteUpdate
 // @Summary Updating room
 // @Description Update room in database
@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"log/slog"
 	"net/http"
@@ -16,16 +17,19 @@ type scheduleRoutes struct {
 // NewScheduleRouteCreate
 // @Summary Creating a new schedule
 // @Description Creates a new schedule in the database and returns its uuid
+// @Description Users with editing scopes create pairs only of their groups or locations, 403 tells missing scope
 // @Security ApiKeyAuth
 // @Tags schedule
 // @Accept json
 // @Produce json
 // @Param schedule body dto.ScheduleRequest true "Schedule"
-// @Success 200 {object} ResponseOK{response=dto.ScheduleRequest}
+// @Param force query bool false "Create schedule even if it clashes with existing pairs"
+// @Success 200 {object} ResponseOK{response=dto.CreateScheduleResponse}
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 404 {object} ResponseError
+// @Failure 409 {object} ResponseError
 // @Failure 500 {object} ResponseError
 // @Router /api/v1/schedules [post]
 func NewScheduleRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
@@ -44,7 +48,17 @@ func NewScheduleRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 			return
 		}
 
-		resp, err := r.uc.Create(c, &scheduleDTO)
+		force := c.Query("force") == "true"
+		resp, err := r.uc.Create(c, &scheduleDTO, force)
+		if errors.Is(err, usecase.ErrScheduleConflict) {
+			makeConflictResponse(c, log, resp.Conflicts)
+			return
+		}
+		var scopeErr *usecase.ScopeError
+		if errors.As(err, &scopeErr) {
+			makeScopeResponse(c, log, scopeErr)
+			return
+		}
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -63,12 +77,19 @@ func NewScheduleRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 
 // NewScheduleRouteGet
 // @Summary Getting schedules
-// @Description Get all schedules from database
+// @Description Get page of pairs from database grouped by days, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags schedule
 // @Accept */*
 // @Produce json
-// @Success 200 {object} ResponseOK{response=dto.Week}
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, group by default"
+// @Param filter query []string false "Filters like weekday>=3, operators are = != ~= > >= < <=" collectionFormat(multi)
+// @Success 200 {object} ResponseOK{response=dto.GetSchedulesResponse}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -84,14 +105,15 @@ func NewScheduleRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCas
 	scheduleGroup := apiV1Group.Group("/schedules")
 
 	scheduleGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -108,7 +130,10 @@ func NewScheduleRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCas
 // @Accept */*
 // @Produce json
 // @Param uuid path string true "Schedule uuid"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -150,7 +175,12 @@ func NewScheduleRouteGetByUUID(apiV1Group *gin.RouterGroup, uc *usecase.Schedule
 // @Produce json
 // @Param fn path string true "Teacher fullname"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -173,7 +203,8 @@ func NewScheduleRouteGetByTeacher(apiV1Group *gin.RouterGroup, uc *usecase.Sched
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByTeacher(c, reqfn, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByTeacher(c, reqfn, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -198,7 +229,12 @@ func NewScheduleRouteGetByTeacher(apiV1Group *gin.RouterGroup, uc *usecase.Sched
 // @Produce json
 // @Param uuid path string true "Teacher uuid"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -221,7 +257,8 @@ func NewScheduleRouteGetByTeacherUUID(apiV1Group *gin.RouterGroup, uc *usecase.S
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByTeacherUUID(c, reqUUID, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByTeacherUUID(c, reqUUID, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -245,7 +282,12 @@ func NewScheduleRouteGetByTeacherUUID(apiV1Group *gin.RouterGroup, uc *usecase.S
 // @Produce json
 // @Param number path string true "Group number"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 404 {object} ResponseError
@@ -267,7 +309,8 @@ func NewScheduleRouteGetByGroup(apiV1Group *gin.RouterGroup, uc *usecase.Schedul
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByGroup(c, reqNumber, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByGroup(c, reqNumber, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -292,7 +335,12 @@ func NewScheduleRouteGetByGroup(apiV1Group *gin.RouterGroup, uc *usecase.Schedul
 // @Produce json
 // @Param uuid path string true "Group uuid"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -315,7 +363,8 @@ func NewScheduleRouteGetByGroupUUID(apiV1Group *gin.RouterGroup, uc *usecase.Sch
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByGroupUUID(c, reqUUID, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByGroupUUID(c, reqUUID, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -340,7 +389,12 @@ func NewScheduleRouteGetByGroupUUID(apiV1Group *gin.RouterGroup, uc *usecase.Sch
 // @Produce json
 // @Param number path string true "Room number"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -363,7 +417,8 @@ func NewScheduleRouteGetByRoom(apiV1Group *gin.RouterGroup, uc *usecase.Schedule
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByRoom(c, reqNumber, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByRoom(c, reqNumber, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -388,7 +443,12 @@ func NewScheduleRouteGetByRoom(apiV1Group *gin.RouterGroup, uc *usecase.Schedule
 // @Produce json
 // @Param uuid path string true "Room uuid"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -411,7 +471,8 @@ func NewScheduleRouteGetByRoomUUID(apiV1Group *gin.RouterGroup, uc *usecase.Sche
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByRoomUUID(c, reqUUID, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByRoomUUID(c, reqUUID, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -436,7 +497,12 @@ func NewScheduleRouteGetByRoomUUID(apiV1Group *gin.RouterGroup, uc *usecase.Sche
 // @Produce json
 // @Param name path string true "Subject name"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -459,7 +525,8 @@ func NewScheduleRouteGetBySubject(apiV1Group *gin.RouterGroup, uc *usecase.Sched
 			isSession = true
 		}
 
-		resp, err := r.uc.GetBySubject(c, reqName, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetBySubject(c, reqName, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -484,7 +551,12 @@ func NewScheduleRouteGetBySubject(apiV1Group *gin.RouterGroup, uc *usecase.Sched
 // @Produce json
 // @Param uuid path string true "Subject uuid"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -507,7 +579,8 @@ func NewScheduleRouteGetBySubjectUUID(apiV1Group *gin.RouterGroup, uc *usecase.S
 			isSession = true
 		}
 
-		resp, err := r.uc.GetBySubjectUUID(c, reqUUID, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetBySubjectUUID(c, reqUUID, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -532,7 +605,12 @@ func NewScheduleRouteGetBySubjectUUID(apiV1Group *gin.RouterGroup, uc *usecase.S
 // @Produce json
 // @Param name path string true "Location name"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -555,7 +633,8 @@ func NewScheduleRouteGetByLocation(apiV1Group *gin.RouterGroup, uc *usecase.Sche
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByLocation(c, reqName, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByLocation(c, reqName, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -580,7 +659,12 @@ func NewScheduleRouteGetByLocation(apiV1Group *gin.RouterGroup, uc *usecase.Sche
 // @Produce json
 // @Param uuid path string true "Location uuid"
 // @Param session query int false "Is session"
+// @Param date query string false "Only pairs on given date (2025-03-12)"
+// @Param week query string false "Only pairs in given ISO week (2025-W11)"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
 // @Success 200 {object} ResponseOK{response=dto.Week}
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
@@ -603,7 +687,8 @@ func NewScheduleRouteGetByLocationUUID(apiV1Group *gin.RouterGroup, uc *usecase.
 			isSession = true
 		}
 
-		resp, err := r.uc.GetByLocationUUID(c, reqUUID, isSession)
+		filter := &dto.DateFilter{Date: c.Query("date"), Week: c.Query("week")}
+		resp, err := r.uc.GetByLocationUUID(c, reqUUID, isSession, filter)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -628,11 +713,13 @@ func NewScheduleRouteGetByLocationUUID(apiV1Group *gin.RouterGroup, uc *usecase.
 // @Produce json
 // @Param uuid path string true "Schedule uuid"
 // @Param room body dto.ScheduleRequest true "Schedule"
-// @Success 200 {object} ResponseOK
+// @Param force query bool false "Update schedule even if it clashes with existing pairs"
+// @Success 200 {object} ResponseOK{response=dto.ScheduleConflictsResponse}
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 404 {object} ResponseError
+// @Failure 409 {object} ResponseError
 // @Failure 500 {object} ResponseError
 // @Router /api/v1/schedules/{uuid} [put]
 func NewScheduleRouteUpdate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
@@ -650,7 +737,17 @@ func NewScheduleRouteUpdate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 			return
 		}
 
-		err := r.uc.Update(c, reqUUID, &scheduleDTO)
+		force := c.Query("force") == "true"
+		resp, err := r.uc.Update(c, reqUUID, &scheduleDTO, force)
+		if errors.Is(err, usecase.ErrScheduleConflict) {
+			makeConflictResponse(c, log, resp.Conflicts)
+			return
+		}
+		var scopeErr *usecase.ScopeError
+		if errors.As(err, &scopeErr) {
+			makeScopeResponse(c, log, scopeErr)
+			return
+		}
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -662,7 +759,200 @@ func NewScheduleRouteUpdate(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 			return
 		}
 
-		c.JSON(http.StatusOK, RespOK(nil))
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewScheduleRouteGetConflicts
+// @Summary Getting schedule conflicts
+// @Description Get every group, teacher and room that is booked for several pairs at the same time
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce json
+// @Success 200 {object} ResponseOK{response=dto.ScheduleConflictsResponse}
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/conflicts [get]
+func NewScheduleRouteGetConflicts(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetConflicts"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/conflicts", func(c *gin.Context) {
+		resp, err := r.uc.GetConflicts(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewScheduleRouteGetChanges
+// @Summary Getting schedule changes
+// @Description Get pairs created, updated or deleted by parser or users since given date or time, last 24 hours by default. Date is start of day in Moscow time. Source tells only whether user or parser made change
+// @Tags schedule
+// @Accept */*
+// @Produce json
+// @Param group query string false "Group number, changes of every group if empty" example(221-352)
+// @Param since query string false "Date or RFC 3339 time" example(2025-03-11)
+// @Success 200 {object} ResponseOK{response=dto.ScheduleChangesResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/changes [get]
+func NewScheduleRouteGetChanges(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetChanges"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/changes", r.getChanges(false))
+}
+
+// NewScheduleRouteGetChangesWithSource
+// @Summary Getting schedule changes with their sources
+// @Description Get pairs created, updated or deleted since given date or time, last 24 hours by default, with username of user or parser run that made change. Date is start of day in Moscow time
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce json
+// @Param group query string false "Group number, changes of every group if empty" example(221-352)
+// @Param since query string false "Date or RFC 3339 time" example(2025-03-11)
+// @Success 200 {object} ResponseOK{response=dto.ScheduleChangesResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/changes/full [get]
+func NewScheduleRouteGetChangesWithSource(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetChangesWithSource"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/changes/full", r.getChanges(true))
+}
+
+// getChanges answers with schedule changes, usernames in their sources are shown only with withSource
+func (r *scheduleRoutes) getChanges(withSource bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		resp, err := r.uc.GetChanges(c, c.Query("group"), c.Query("since"), withSource)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      r.log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	}
+}
+
+// NewScheduleRouteGetTeacherAvailability
+// @Summary Getting teacher availability
+// @Description Get pair slots in which teacher with given uuid has no regular or session pairs, range defaults to a week from today. Slots are given by default bells and by bells of every location that has own ones on date
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Teacher uuid"
+// @Param from query string false "First date of range" example(2025-03-10)
+// @Param to query string false "Last date of range" example(2025-03-16)
+// @Success 200 {object} ResponseOK{response=dto.AvailabilityResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/teachers/{uuid}/availability [get]
+func NewScheduleRouteGetTeacherAvailability(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetTeacherAvailability"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	teacherGroup := apiV1Group.Group("/teachers")
+
+	teacherGroup.GET("/:uuid/availability", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetTeachersAvailability(c, []string{reqUUID}, c.Query("from"), c.Query("to"))
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "teacher_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewScheduleRouteGetTeachersCommonAvailability
+// @Summary Getting common free slots of teachers
+// @Description Get pair slots in which none of teachers with given uuids has regular or session pairs, range defaults to a week from today. Slots are given by default bells and by bells of every location that has own ones on date
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce json
+// @Param uuid query []string true "Teacher uuids" collectionFormat(multi)
+// @Param from query string false "First date of range" example(2025-03-10)
+// @Param to query string false "Last date of range" example(2025-03-16)
+// @Success 200 {object} ResponseOK{response=dto.AvailabilityResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/teachers/availability [get]
+func NewScheduleRouteGetTeachersCommonAvailability(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetTeachersCommonAvailability"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	teacherGroup := apiV1Group.Group("/teachers")
+
+	teacherGroup.GET("/availability", func(c *gin.Context) {
+		reqUUIDs := c.QueryArray("uuid")
+		resp, err := r.uc.GetTeachersAvailability(c, reqUUIDs, c.Query("from"), c.Query("to"))
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "teacher_uuids",
+				logValue: reqUUIDs,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
 	})
 }
 
@@ -689,6 +979,11 @@ func NewScheduleRouteDelete(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 	scheduleGroup.DELETE("/:uuid", func(c *gin.Context) {
 		reqUUID := c.Param("uuid")
 		err := r.uc.Delete(c, reqUUID)
+		var scopeErr *usecase.ScopeError
+		if errors.As(err, &scopeErr) {
+			makeScopeResponse(c, log, scopeErr)
+			return
+		}
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -703,3 +998,264 @@ func NewScheduleRouteDelete(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUse
 		c.JSON(http.StatusOK, RespOK(nil))
 	})
 }
+
+// NewScheduleRouteGetCalendarByGroup
+// @Summary Getting schedule calendar by group number
+// @Description Get regular and session schedule with given group number as iCalendar feed
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param number path string true "Group number"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/group/number/{number} [get]
+func NewScheduleRouteGetCalendarByGroup(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByGroup"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/ics/group/number/:number", func(c *gin.Context) {
+		reqNumber := c.Param("number")
+		resp, err := r.uc.GetCalendarByGroup(c, reqNumber)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "group_number",
+				logValue: reqNumber,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
+
+// NewScheduleRouteGetCalendarByGroupUUID
+// @Summary Getting schedule calendar by group uuid
+// @Description Get regular and session schedule with given group uuid as iCalendar feed
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param uuid path string true "Group uuid"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/group/uuid/{uuid} [get]
+func NewScheduleRouteGetCalendarByGroupUUID(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByGroupUUID"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/ics/group/uuid/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetCalendarByGroupUUID(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "group_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
+
+// NewScheduleRouteGetCalendarByTeacher
+// @Summary Getting schedule calendar by teacher fullname
+// @Description Get regular and session schedule with given teacher fullname as iCalendar feed
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param fn path string true "Teacher fullname"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/teacher/fn/{fn} [get]
+func NewScheduleRouteGetCalendarByTeacher(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByTeacher"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/ics/teacher/fn/:fn", func(c *gin.Context) {
+		reqfn := c.Param("fn")
+		resp, err := r.uc.GetCalendarByTeacher(c, reqfn)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "teacher_fullname",
+				logValue: reqfn,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
+
+// NewScheduleRouteGetCalendarByTeacherUUID
+// @Summary Getting schedule calendar by teacher uuid
+// @Description Get regular and session schedule with given teacher uuid as iCalendar feed
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param uuid path string true "Teacher uuid"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/teacher/uuid/{uuid} [get]
+func NewScheduleRouteGetCalendarByTeacherUUID(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByTeacherUUID"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleGroup := apiV1Group.Group("/schedules")
+
+	scheduleGroup.GET("/ics/teacher/uuid/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetCalendarByTeacherUUID(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "teacher_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
+
+// NewScheduleRouteGetCalendarByRoom
+// @Summary Getting schedule calendar by room number
+// @Description Get regular and session schedule with given room number as iCalendar feed
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param number path string true "Room number"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/room/number/{number} [get]
+func NewScheduleRouteGetCalendarByRoom(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByRoom"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleRoom := apiV1Group.Group("/schedules")
+
+	scheduleRoom.GET("/ics/room/number/:number", func(c *gin.Context) {
+		reqNumber := c.Param("number")
+		resp, err := r.uc.GetCalendarByRoom(c, reqNumber)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "room_number",
+				logValue: reqNumber,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
+
+// NewScheduleRouteGetCalendarByRoomUUID
+// @Summary Getting schedule calendar by room uuid
+// @Description Get regular and session schedule with given room uuid as iCalendar feed
+// @Security ApiKeyAuth
+// @Tags schedule
+// @Accept */*
+// @Produce text/calendar
+// @Param uuid path string true "Room uuid"
+// @Param If-None-Match header string false "ETag of schedule client has"
+// @Param If-Modified-Since header string false "Last-Modified of schedule client has"
+// @Success 200 {string} string "iCalendar feed"
+// @Success 304 "Schedule was not modified since version in If-None-Match or If-Modified-Since"
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/schedules/ics/room/uuid/{uuid} [get]
+func NewScheduleRouteGetCalendarByRoomUUID(apiV1Group *gin.RouterGroup, uc *usecase.ScheduleUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewScheduleRouteGetCalendarByRoomUUID"
+	log = log.With(slog.String("op", op))
+
+	r := &scheduleRoutes{uc, log}
+
+	scheduleRoom := apiV1Group.Group("/schedules")
+
+	scheduleRoom.GET("/ics/room/uuid/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetCalendarByRoomUUID(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "room_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.Data(http.StatusOK, ContentTypeCalendar, resp)
+	})
+}
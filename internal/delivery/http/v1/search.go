@@ -0,0 +1,55 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type searchRoutes struct {
+	uc  *usecase.SearchUseCase
+	log *slog.Logger
+}
+
+// NewSearchRoute
+// @Summary Searching groups, teachers, rooms and subjects
+// @Description Get entities ranked by similarity to query, best first. Query may be mistyped, incomplete, transliterated, typed in QWERTY layout or contain initials like "Иванов И.И."
+// @Tags search
+// @Accept */*
+// @Produce json
+// @Param q query string true "Search query, 100 characters at most" example(Иванов И.И.)
+// @Param type query []string false "Types of entities: group, teacher, room, subject, every type by default" collectionFormat(multi)
+// @Param limit query int false "Number of hits, 10 by default, 50 at most"
+// @Success 200 {object} ResponseOK{response=dto.SearchResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/search [get]
+func NewSearchRoute(apiV1Group *gin.RouterGroup, uc *usecase.SearchUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewSearchRoute"
+	log = log.With(slog.String("op", op))
+
+	r := &searchRoutes{uc, log}
+
+	apiV1Group.GET("/search", func(c *gin.Context) {
+		params := &dto.SearchParams{
+			Query: c.Query("q"),
+			Types: c.QueryArray("type"),
+			Limit: c.Query("limit"),
+		}
+		resp, err := r.uc.Search(c, params)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "search_params",
+				logValue: params,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
@@ -62,12 +62,17 @@ func NewSubjectRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.SubjectUseCa
 
 // NewSubjectRouteGet
 // @Summary Getting subjects
-// @Description Get all subjects from database
+// @Description Get page of subjects from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags subject
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, name by default"
+// @Param filter query []string false "Filters like name~=язык, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetSubjectsResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -81,14 +86,15 @@ func NewSubjectRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.SubjectUseCase,
 	subjectGroup := apiV1Group.Group("/subjects")
 
 	subjectGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -62,12 +62,17 @@ func NewSubjectTypeRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.SubjectT
 
 // NewSubjectTypeRouteGet
 // @Summary Getting subjectTypes
-// @Description Get all subjectTypes from database
+// @Description Get page of subjectTypes from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags subjectType
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, type by default"
+// @Param filter query []string false "Filters like type=Практика, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetSubjectTypesResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -81,14 +86,15 @@ func NewSubjectTypeRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.SubjectType
 	subjectTypeGroup := apiV1Group.Group("/subjecttypes")
 
 	subjectTypeGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -1,10 +1,11 @@
 package v1
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
+	"io"
 	"log/slog"
 	"net/http"
-	"raspyx/config"
 	"raspyx/internal/dto"
 	"raspyx/internal/usecase"
 )
@@ -23,6 +24,7 @@ type userRoutes struct {
 // @Param user body dto.RegisterUserRequest true "User"
 // @Success 200 {object} ResponseOK{response=dto.RegisterUserRequest}
 // @Failure 400 {object} ResponseError
+// @Failure 429 {object} ResponseError
 // @Failure 500 {object} ResponseError
 // @Router /api/v1/users/register [post]
 func NewUserRouteRegister(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
@@ -61,16 +63,17 @@ func NewUserRouteRegister(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase,
 
 // NewUserRouteLogin
 // @Summary User authentication
-// @Description Authenticate user and return access token
+// @Description Authenticate user and return short-lived access token and refresh token
 // @Tags user
 // @Accept json
 // @Produce json
 // @Param user body dto.LoginUserRequest true "User"
-// @Success 200 {object} ResponseOK{response=dto.LoginUserRequest}
+// @Success 200 {object} ResponseOK{response=dto.LoginUserResponse}
 // @Failure 400 {object} ResponseError
+// @Failure 429 {object} ResponseError
 // @Failure 500 {object} ResponseError
 // @Router /api/v1/users/login [post]
-func NewUserRouteLogin(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger, jwt config.JWT) {
+func NewUserRouteLogin(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
 	const op = "delivery.http.v1.NewUserRouteLogin"
 	log = log.With(slog.String("op", op))
 
@@ -86,7 +89,7 @@ func NewUserRouteLogin(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log
 			return
 		}
 
-		resp, err := r.uc.Login(c, jwt, &userDTO)
+		resp, err := r.uc.Login(c, &userDTO)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
@@ -103,14 +106,128 @@ func NewUserRouteLogin(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log
 
 }
 
+// NewUserRouteRefresh
+// @Summary Refreshing access token
+// @Description Exchange refresh token for new access token and refresh token, given refresh token is revoked
+// @Description Reuse of revoked refresh token revokes the whole session
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param token body dto.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} ResponseOK{response=dto.LoginUserResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 429 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/refresh [post]
+func NewUserRouteRefresh(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserRouteRefresh"
+	log = log.With(slog.String("op", op))
+
+	r := &userRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.POST("/refresh", func(c *gin.Context) {
+		var refreshDTO dto.RefreshTokenRequest
+		if err := c.ShouldBindJSON(&refreshDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		resp, err := r.uc.Refresh(c, &refreshDTO)
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			log.Info("Invalid refresh token")
+			c.JSON(http.StatusUnauthorized, RespError("Invalid refresh token"))
+			return
+		}
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewUserRouteLogout
+// @Summary Logging out
+// @Description Revoke access token of request and session of given refresh token
+// @Security ApiKeyAuth
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param token body dto.LogoutRequest false "Refresh token"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/logout [post]
+func NewUserRouteLogout(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserRouteLogout"
+	log = log.With(slog.String("op", op))
+
+	r := &userRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.POST("/logout", func(c *gin.Context) {
+		// API keys have no session, they are revoked by admins
+		if c.GetString("api_key") != "" {
+			log.Info("Logout with API key")
+			c.JSON(http.StatusBadRequest, RespError("API key can not log out"))
+			return
+		}
+
+		// Body is optional, without it only access token is revoked
+		var logoutDTO dto.LogoutRequest
+		if err := c.ShouldBindJSON(&logoutDTO); err != nil && !errors.Is(err, io.EOF) {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		err := r.uc.Logout(c, c.GetString("user_uuid"), c.GetString("jti"), c.GetTime("exp"), &logoutDTO)
+		if errors.Is(err, usecase.ErrInvalidRefreshToken) {
+			log.Info("Invalid refresh token")
+			c.JSON(http.StatusBadRequest, RespError("Invalid refresh token"))
+			return
+		}
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "username",
+				logValue: c.GetString("username"),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
+
 // NewUserRouteGet
 // @Summary Getting users
-// @Description Get all users from database
+// @Description Get page of users from database, next page is requested with next_cursor of pagination
 // @Security ApiKeyAuth
 // @Tags user
 // @Accept */*
 // @Produce json
+// @Param limit query int false "Items per page, 50 by default, 500 at most"
+// @Param cursor query string false "next_cursor of previous page"
+// @Param sort query string false "Field to sort by, - prefix sorts descending, username by default"
+// @Param filter query []string false "Filters like role=editor, operators are = != ~= > >= < <=" collectionFormat(multi)
 // @Success 200 {object} ResponseOK{response=dto.GetUsersResponse}
+// @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 500 {object} ResponseError
@@ -124,14 +241,15 @@ func NewUserRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *
 	userGroup := apiV1Group.Group("/users")
 
 	userGroup.GET("/", func(c *gin.Context) {
-		resp, err := r.uc.Get(c)
+		params := listParams(c)
+		resp, err := r.uc.Get(c, params)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "error",
-				logValue: err.Error(),
+				logKey:   "list_params",
+				logValue: params,
 			})
 			return
 		}
@@ -216,36 +334,68 @@ func NewUserRouteGetByUsername(apiV1Group *gin.RouterGroup, uc *usecase.UserUseC
 	})
 }
 
-// NewUserRouteGetByAccessLevel
-// @Summary Getting user by access level
-// @Description Get user from database with access level less than or equal to given AccessLevel
+// NewUserRouteGetByRole
+// @Summary Getting users by role
+// @Description Get users from database with given role
 // @Security ApiKeyAuth
 // @Tags user
 // @Accept */*
 // @Produce json
-// @Param al path string true "Access level"
-// @Success 200 {object} ResponseOK{response=[]models.User}
+// @Param role path string true "Role"
+// @Success 200 {object} ResponseOK{response=[]dto.UserDTO}
 // @Failure 400 {object} ResponseError
 // @Failure 401 {object} ResponseError
 // @Failure 403 {object} ResponseError
 // @Failure 404 {object} ResponseError
 // @Failure 500 {object} ResponseError
-// @Router /api/v1/users/al/{al} [get]
-func NewUserRouteGetByAccessLevel(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
+// @Router /api/v1/users/role/{role} [get]
+func NewUserRouteGetByRole(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
 	r := &userRoutes{uc, log}
 
 	userGroup := apiV1Group.Group("/users")
 
-	userGroup.GET("/al/:al", func(c *gin.Context) {
-		reqAccessLevel := c.Param("al")
-		resp, err := r.uc.GetByAccessLevel(c, reqAccessLevel)
+	userGroup.GET("/role/:role", func(c *gin.Context) {
+		reqRole := c.Param("role")
+		resp, err := r.uc.GetByRole(c, reqRole)
 		if err != nil {
 			makeErrResponse(c, &ErrResp{
 				err:      err,
 				c:        c,
 				log:      log,
-				logKey:   "user_access_level",
-				logValue: reqAccessLevel,
+				logKey:   "user_role",
+				logValue: reqRole,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewUserRouteGetRoles
+// @Summary Getting roles
+// @Description Get roles with their permissions
+// @Security ApiKeyAuth
+// @Tags user
+// @Accept */*
+// @Produce json
+// @Success 200 {object} ResponseOK{response=[]models.Role}
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/roles [get]
+func NewUserRouteGetRoles(apiV1Group *gin.RouterGroup, uc *usecase.UserUseCase, log *slog.Logger) {
+	r := &userRoutes{uc, log}
+
+	apiV1Group.GET("/roles", func(c *gin.Context) {
+		resp, err := r.uc.GetRoles(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
 			})
 			return
 		}
@@ -256,8 +406,8 @@ func NewUserRouteGetByAccessLevel(apiV1Group *gin.RouterGroup, uc *usecase.UserU
 
 // NewUserRouteUpdate
 // @Summary Updating user
-// @Description Update user in database
-// @Description 0 - user, 50 - moderator, 99 - admin
+// @Description Update role of user in database, access tokens of user are revoked
+// @Description viewer, editor, moderator or admin, permissions of roles are listed by /roles
 // @Security ApiKeyAuth
 // @Tags user
 // @Accept json
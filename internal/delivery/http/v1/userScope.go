@@ -0,0 +1,107 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type userScopeRoutes struct {
+	uc  *usecase.UserScopeUseCase
+	log *slog.Logger
+}
+
+// NewUserScopeRouteGet
+// @Summary Getting editing scopes of user
+// @Description Get groups, locations and group number prefixes which schedules user can edit
+// @Description User without scopes edits every schedule
+// @Security ApiKeyAuth
+// @Tags user
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "User uuid"
+// @Success 200 {object} ResponseOK{response=dto.UserScopes}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/{uuid}/scopes [get]
+func NewUserScopeRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.UserScopeUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserScopeRouteGet"
+	log = log.With(slog.String("op", op))
+
+	r := &userScopeRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.GET("/:uuid/scopes", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetByUser(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "user_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewUserScopeRouteUpdate
+// @Summary Setting editing scopes of user
+// @Description Replace groups, locations and group number prefixes which schedules user can edit
+// @Description Empty scopes let user edit every schedule
+// @Security ApiKeyAuth
+// @Tags user
+// @Accept json
+// @Produce json
+// @Param uuid path string true "User uuid"
+// @Param scopes body dto.UserScopes true "Scopes"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/users/{uuid}/scopes [put]
+func NewUserScopeRouteUpdate(apiV1Group *gin.RouterGroup, uc *usecase.UserScopeUseCase, log *slog.Logger) {
+	const op = "delivery.http.v1.NewUserScopeRouteUpdate"
+	log = log.With(slog.String("op", op))
+
+	r := &userScopeRoutes{uc, log}
+
+	userGroup := apiV1Group.Group("/users")
+
+	userGroup.PUT("/:uuid/scopes", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+
+		var scopesDTO dto.UserScopes
+		if err := c.ShouldBindJSON(&scopesDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		err := r.uc.Replace(c, reqUUID, &scopesDTO)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "scopes",
+				logValue: map[string]any{"uuid": reqUUID, "scopes_dto": scopesDTO},
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
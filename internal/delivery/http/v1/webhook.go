@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+	"raspyx/internal/dto"
+	"raspyx/internal/usecase"
+)
+
+type webhookRoutes struct {
+	uc  *usecase.WebhookUseCase
+	log *slog.Logger
+}
+
+// NewWebhookRouteCreate
+// @Summary Creating a new webhook
+// @Description Subscribes to changes of pairs of group (by number), teacher (by uuid) or room (by number).
+// @Description Every change is sent as POST request with JSON dto.WebhookPayload, signed in X-Raspyx-Signature header as "sha256=" + hex HMAC-SHA256 of body with the secret.
+// @Description Request is retried with exponential backoff until webhook responds with 2xx, redirects are not followed.
+// @Description URL must point to public address, loopback, private and link-local addresses are refused
+// @Security ApiKeyAuth
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhook body dto.WebhookRequest true "Webhook"
+// @Success 200 {object} ResponseOK{response=dto.CreateWebhookResponse}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/webhooks [post]
+func NewWebhookRouteCreate(apiV1Group *gin.RouterGroup, uc *usecase.WebhookUseCase, log *slog.Logger) {
+	r := &webhookRoutes{uc, log}
+
+	webhookGroup := apiV1Group.Group("/webhooks")
+
+	webhookGroup.POST("/", func(c *gin.Context) {
+		var webhookDTO dto.WebhookRequest
+		if err := c.ShouldBindJSON(&webhookDTO); err != nil {
+			log.Warn(ErrWrongDataStructure, slog.String("error", err.Error()))
+			c.JSON(http.StatusBadRequest, RespError(ErrWrongDataStructure))
+			return
+		}
+
+		resp, err := r.uc.Create(c, &webhookDTO)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "webhook_url",
+				logValue: webhookDTO.URL,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewWebhookRouteGet
+// @Summary Getting webhooks
+// @Description Get webhooks of current user
+// @Security ApiKeyAuth
+// @Tags webhook
+// @Accept */*
+// @Produce json
+// @Success 200 {object} ResponseOK{response=[]models.Webhook}
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/webhooks/ [get]
+func NewWebhookRouteGet(apiV1Group *gin.RouterGroup, uc *usecase.WebhookUseCase, log *slog.Logger) {
+	r := &webhookRoutes{uc, log}
+
+	webhookGroup := apiV1Group.Group("/webhooks")
+
+	webhookGroup.GET("/", func(c *gin.Context) {
+		resp, err := r.uc.Get(c)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "error",
+				logValue: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewWebhookRouteGetDeliveries
+// @Summary Getting webhook deliveries
+// @Description Get last deliveries of webhook of current user with every attempt to send them
+// @Security ApiKeyAuth
+// @Tags webhook
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Webhook uuid"
+// @Success 200 {object} ResponseOK{response=[]models.WebhookDelivery}
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/webhooks/{uuid}/deliveries [get]
+func NewWebhookRouteGetDeliveries(apiV1Group *gin.RouterGroup, uc *usecase.WebhookUseCase, log *slog.Logger) {
+	r := &webhookRoutes{uc, log}
+
+	webhookGroup := apiV1Group.Group("/webhooks")
+
+	webhookGroup.GET("/:uuid/deliveries", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		resp, err := r.uc.GetDeliveries(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "webhook_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(resp))
+	})
+}
+
+// NewWebhookRouteDelete
+// @Summary Deleting webhook
+// @Description Deletes webhook of current user with given uuid
+// @Security ApiKeyAuth
+// @Tags webhook
+// @Accept */*
+// @Produce json
+// @Param uuid path string true "Webhook uuid"
+// @Success 200 {object} ResponseOK
+// @Failure 400 {object} ResponseError
+// @Failure 401 {object} ResponseError
+// @Failure 403 {object} ResponseError
+// @Failure 404 {object} ResponseError
+// @Failure 500 {object} ResponseError
+// @Router /api/v1/webhooks/{uuid} [delete]
+func NewWebhookRouteDelete(apiV1Group *gin.RouterGroup, uc *usecase.WebhookUseCase, log *slog.Logger) {
+	r := &webhookRoutes{uc, log}
+
+	webhookGroup := apiV1Group.Group("/webhooks")
+
+	webhookGroup.DELETE("/:uuid", func(c *gin.Context) {
+		reqUUID := c.Param("uuid")
+		err := r.uc.Delete(c, reqUUID)
+		if err != nil {
+			makeErrResponse(c, &ErrResp{
+				err:      err,
+				c:        c,
+				log:      log,
+				logKey:   "webhook_uuid",
+				logValue: reqUUID,
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, RespOK(nil))
+	})
+}
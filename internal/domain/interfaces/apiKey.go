@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *models.APIKey) error
+	Get(ctx context.Context) ([]*models.APIKey, error)
+	GetByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	// Revoke revokes key, it fails with not found if key is already revoked
+	Revoke(ctx context.Context, uuid uuid.UUID) error
+	TouchLastUsed(ctx context.Context, uuid uuid.UUID) error
+}
+
+// APIKeyAuthenticator returns active key by its secret, used by middlewares
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
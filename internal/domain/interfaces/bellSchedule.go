@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+)
+
+type BellScheduleRepository interface {
+	Create(ctx context.Context, bell *models.BellSchedule) error
+	Get(ctx context.Context) ([]*models.BellSchedule, error)
+	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.BellSchedule, error)
+	Update(ctx context.Context, bell *models.BellSchedule) error
+	Delete(ctx context.Context, uuid uuid.UUID) error
+}
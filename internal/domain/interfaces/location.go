@@ -9,6 +9,7 @@ import (
 type LocationRepository interface {
 	Create(ctx context.Context, location *models.Location) error
 	Get(ctx context.Context) ([]*models.Location, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Location], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Location, error)
 	GetByName(ctx context.Context, name string) (*models.Location, error)
 	Update(ctx context.Context, location *models.Location) error
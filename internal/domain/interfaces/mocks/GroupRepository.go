@@ -143,6 +143,36 @@ func (_m *GroupRepository) GetByUUID(ctx context.Context, _a1 uuid.UUID) (*model
 	return r0, r1
 }
 
+// List provides a mock function with given fields: ctx, query
+func (_m *GroupRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Group], error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *models.ListPage[*models.Group]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ListQuery) (*models.ListPage[*models.Group], error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ListQuery) *models.ListPage[*models.Group]); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*models.ListPage[*models.Group])
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *models.ListQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Update provides a mock function with given fields: ctx, group
 func (_m *GroupRepository) Update(ctx context.Context, group *models.Group) error {
 	ret := _m.Called(ctx, group)
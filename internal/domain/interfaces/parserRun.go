@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+)
+
+type ParserRunRepository interface {
+	Create(ctx context.Context, run *models.ParserRun) error
+	Update(ctx context.Context, run *models.ParserRun) error
+	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.ParserRun, error)
+	// Get returns last runs, newest first
+	Get(ctx context.Context, limit int) ([]*models.ParserRun, error)
+	// FailRunning marks runs left running by stopped app as failed
+	FailRunning(ctx context.Context, reason string) error
+}
+
+// ParserRunner starts schedule parser on demand
+type ParserRunner interface {
+	// Start starts run of parser for given groups (every group if none) in background.
+	// Run never overlaps with another one
+	Start(ctx context.Context, groups []string) (uuid.UUID, error)
+}
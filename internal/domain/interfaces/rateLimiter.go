@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"raspyx/internal/domain/models"
+)
+
+type RateLimiter interface {
+	// Allow takes request from bucket of identity in policy
+	Allow(ctx context.Context, policy models.RateLimitPolicy, identity string) (*models.RateLimitResult, error)
+}
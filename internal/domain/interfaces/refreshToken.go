@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type RefreshTokenRepository interface {
+	Create(ctx context.Context, token *models.RefreshToken) error
+	GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	// GetCreatedSince returns tokens of user created after since, revoked ones too
+	GetCreatedSince(ctx context.Context, userUUID uuid.UUID, since time.Time) ([]*models.RefreshToken, error)
+	// Revoke revokes token, it fails with not found if token is already revoked
+	Revoke(ctx context.Context, uuid uuid.UUID) error
+	// RevokeFamily revokes every token of family and returns jti of access tokens issued with them
+	RevokeFamily(ctx context.Context, family uuid.UUID) ([]uuid.UUID, error)
+}
+
+// TokenDenylist holds jti of access tokens revoked before they expire
+type TokenDenylist interface {
+	Deny(ctx context.Context, jti string, ttl time.Duration) error
+	IsDenied(ctx context.Context, jti string) (bool, error)
+}
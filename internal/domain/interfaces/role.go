@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"raspyx/internal/domain/models"
+)
+
+type RoleRepository interface {
+	Get(ctx context.Context) ([]*models.Role, error)
+	GetByName(ctx context.Context, name string) (*models.Role, error)
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"raspyx/internal/domain/models"
+	"time"
 )
 
 type RoomRepository interface {
 	Create(ctx context.Context, room *models.Room) error
 	Get(ctx context.Context) ([]*models.Room, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Room], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Room, error)
 	GetByNumber(ctx context.Context, number string) (*models.Room, error)
+	GetFree(ctx context.Context, location, prefix string, date, st, et time.Time) ([]*models.Room, error)
 	Update(ctx context.Context, room *models.Room) error
 	Delete(ctx context.Context, uuid uuid.UUID) error
 }
@@ -4,12 +4,12 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"raspyx/internal/domain/models"
-	"time"
 )
 
 type ScheduleRepository interface {
 	Create(ctx context.Context, schedule *models.Schedule) error
 	Get(ctx context.Context) ([]*models.ScheduleData, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.ScheduleData], error)
 	GetForUpdate(ctx context.Context, uuid uuid.UUID) (*models.Schedule, error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.ScheduleData, error)
 	GetByTeacher(ctx context.Context, firstName, secondName, middleName string, isSession bool) ([]*models.ScheduleData, error)
@@ -24,6 +24,21 @@ type ScheduleRepository interface {
 	GetByLocationUUID(ctx context.Context, locationUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error)
 	Update(ctx context.Context, schedule *models.Schedule) error
 	Delete(ctx context.Context, uuid uuid.UUID) error
-	DeletePairsByGroupWeekdayTime(ctx context.Context, group uuid.UUID, weekday int, st, sd time.Time, isSession bool) error
-	DeleteByParams(ctx context.Context, params *models.ScheduleData) error
+	DeleteByParams(ctx context.Context, params *models.ScheduleData) ([]uuid.UUID, error)
+}
+
+// CachedScheduleRepository serves queries of ScheduleRepository from cache until pairs in them are changed
+type CachedScheduleRepository interface {
+	ScheduleRepository
+	// Invalidate drops cached queries that may contain given pairs, it is called after changes are committed
+	Invalidate(ctx context.Context, schedules ...*models.ScheduleData) error
+}
+
+// ScheduleCacheInvalidator drops cached schedule of group, teacher, room, subject or location when it is renamed or deleted
+type ScheduleCacheInvalidator interface {
+	// SchedulesOf returns pairs of resource of kind with given uuid, they are taken before change of resource
+	// as pairs of deleted teacher or room lose it
+	SchedulesOf(ctx context.Context, kind string, uuid uuid.UUID) ([]*models.ScheduleData, error)
+	// InvalidateResource drops cached queries of resource with given names and queries containing given pairs
+	InvalidateResource(ctx context.Context, kind string, names []string, schedules []*models.ScheduleData) error
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type ScheduleChangeRepository interface {
+	Create(ctx context.Context, change *models.ScheduleChange) error
+	GetByGroup(ctx context.Context, groupNumber string, since time.Time) ([]*models.ScheduleChange, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import (
+	"context"
+	"raspyx/internal/domain/models"
+)
+
+type SearchRepository interface {
+	Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error)
+}
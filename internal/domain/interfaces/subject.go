@@ -9,6 +9,7 @@ import (
 type SubjectRepository interface {
 	Create(ctx context.Context, subject *models.Subject) error
 	Get(ctx context.Context) ([]*models.Subject, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Subject], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Subject, error)
 	GetByName(ctx context.Context, name string) ([]*models.Subject, error)
 	Update(ctx context.Context, subject *models.Subject) error
@@ -9,6 +9,7 @@ import (
 type SubjectTypeRepository interface {
 	Create(ctx context.Context, subjectType *models.SubjectType) error
 	Get(ctx context.Context) ([]*models.SubjectType, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.SubjectType], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.SubjectType, error)
 	GetByType(ctx context.Context, subjectType string) (*models.SubjectType, error)
 	Update(ctx context.Context, subjectType *models.SubjectType) error
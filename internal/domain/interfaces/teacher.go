@@ -9,6 +9,7 @@ import (
 type TeacherRepository interface {
 	Create(ctx context.Context, teacher *models.Teacher) error
 	Get(ctx context.Context) ([]*models.Teacher, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Teacher], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Teacher, error)
 	GetByFullName(ctx context.Context, fn string) ([]*models.Teacher, error)
 	Update(ctx context.Context, teacher *models.Teacher) error
@@ -0,0 +1,12 @@
+package interfaces
+
+import "context"
+
+type TxManager interface {
+	// WithinTx runs fn in transaction which is committed if fn returns nil and rolled back otherwise.
+	// Repositories called with ctx passed to fn take part in the transaction, nested calls join the outer one
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+	// Lock takes lock with given key until the end of transaction of ctx, so concurrent transactions
+	// with the same key run one after another. It must be called within WithinTx
+	Lock(ctx context.Context, key string) error
+}
@@ -9,9 +9,10 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *models.User) error
 	Get(ctx context.Context) ([]*models.User, error)
+	List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.User], error)
 	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.User, error)
 	GetByUsername(ctx context.Context, username string) (*models.User, error)
-	GetByAccessLevel(ctx context.Context, accessLevel int) ([]*models.User, error)
+	GetByRole(ctx context.Context, role string) ([]*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, uuid uuid.UUID) error
 }
@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *models.UserIdentity) error
+	GetByIssuerSubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error)
+}
+
+type OIDCProvider interface {
+	// AuthCodeURL returns url of provider login page, provider redirects back with state and code
+	AuthCodeURL(state, nonce string) string
+	// Exchange trades code for id token and returns its claims once token and nonce are verified
+	Exchange(ctx context.Context, code, nonce string) (*models.OIDCClaims, error)
+}
+
+// OIDCStateStore holds state of started logins with their nonce
+type OIDCStateStore interface {
+	Save(ctx context.Context, state, nonce string, ttl time.Duration) error
+	// Pop returns nonce of state and deletes it so state is used once, empty nonce if state is unknown
+	Pop(ctx context.Context, state string) (string, error)
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+)
+
+type UserScopeRepository interface {
+	GetByUser(ctx context.Context, userUUID uuid.UUID) ([]*models.UserScope, error)
+	// Replace sets scopes of user to given ones
+	Replace(ctx context.Context, userUUID uuid.UUID, scopes []*models.UserScope) error
+}
@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Webhook, error)
+	GetByOwner(ctx context.Context, owner string) ([]*models.Webhook, error)
+	GetByResources(ctx context.Context, groups, teachers, rooms []string) ([]*models.Webhook, error)
+	Delete(ctx context.Context, uuid uuid.UUID, owner string) error
+}
+
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetByWebhookUUID(ctx context.Context, webhookUUID uuid.UUID, limit int) ([]*models.WebhookDelivery, error)
+	// ClaimDue returns pending deliveries which time has come and postpones them by lease,
+	// so that delivery is not sent twice while it is in flight
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *models.WebhookDelivery) error
+	CreateAttempt(ctx context.Context, attempt *models.WebhookAttempt) error
+}
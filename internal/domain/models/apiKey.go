@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// APIKey authenticates machine clients, scopes are permissions granted to key.
+// Only hash of key is stored, prefix lets admins tell keys apart
+type APIKey struct {
+	UUID       uuid.UUID  `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Name       string     `json:"name" example:"portal"`
+	Prefix     string     `json:"prefix" example:"rpx_3fA9kQ2b"`
+	KeyHash    string     `json:"-"`
+	Scopes     []string   `json:"scopes" example:"schedule:read"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	CreatedAt  time.Time  `json:"created_at" example:"2025-03-12T09:00:00Z"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" example:"2026-03-12T09:00:00Z"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" example:"2025-03-12T09:00:00Z"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" example:"2025-03-12T09:00:00Z"`
+}
+
+// Active reports whether key can be used at given time
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// BellSchedule is time of one pair slot, location and dates are empty for default bells
+type BellSchedule struct {
+	UUID         uuid.UUID  `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	LocationUUID *uuid.UUID `json:"location_uuid,omitempty" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Location     string     `json:"location,omitempty" example:"Автозаводская"`
+	StartDate    *time.Time `json:"start_date,omitempty" example:"2025-02-01"`
+	EndDate      *time.Time `json:"end_date,omitempty" example:"2025-06-30"`
+	PairNum      int        `json:"pair_num" example:"1"`
+	StartTime    time.Time  `json:"start_time" example:"09:00:00"`
+	EndTime      time.Time  `json:"end_time" example:"10:30:00"`
+}
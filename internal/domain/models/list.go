@@ -0,0 +1,80 @@
+package models
+
+import "github.com/google/uuid"
+
+// Types of fields lists are filtered and sorted by
+const (
+	ListFieldString = "string"
+	ListFieldInt    = "int"
+	ListFieldBool   = "bool"
+	ListFieldDate   = "date"
+	ListFieldTime   = "time"
+	// List of strings, it is only filtered with = and ~=
+	ListFieldArray = "array"
+)
+
+// Operators of list filters
+const (
+	FilterEq       = "="
+	FilterNe       = "!="
+	FilterContains = "~="
+	FilterGt       = ">"
+	FilterGe       = ">="
+	FilterLt       = "<"
+	FilterLe       = "<="
+)
+
+// ListFields are fields of entity allowed in filters and sort, by name in query to their type
+type ListFields map[string]string
+
+type ListFilter struct {
+	Field string
+	Op    string
+	Value string
+}
+
+// ListCursor is position after last item of page, items are ordered by sort field and then by uuid
+type ListCursor struct {
+	// Sort of query cursor was made for, e.g. "-number"
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	UUID  uuid.UUID `json:"u"`
+}
+
+type ListQuery struct {
+	Limit     int
+	SortField string
+	SortDesc  bool
+	Filters   []ListFilter
+	// Items are listed after cursor, from the start if it is nil
+	After *ListCursor
+}
+
+type ListPage[T any] struct {
+	Items []T
+	// Cursor of next page, nil on the last page
+	Next *ListCursor
+}
+
+var (
+	GroupListFields       = ListFields{"number": ListFieldString}
+	TeacherListFields     = ListFields{"first_name": ListFieldString, "second_name": ListFieldString, "middle_name": ListFieldString}
+	RoomListFields        = ListFields{"number": ListFieldString}
+	SubjectListFields     = ListFields{"name": ListFieldString}
+	LocationListFields    = ListFields{"name": ListFieldString}
+	SubjectTypeListFields = ListFields{"type": ListFieldString}
+	UserListFields        = ListFields{"username": ListFieldString, "role": ListFieldString}
+	ScheduleListFields    = ListFields{
+		"group":      ListFieldString,
+		"teacher":    ListFieldArray,
+		"room":       ListFieldArray,
+		"subject":    ListFieldString,
+		"type":       ListFieldString,
+		"location":   ListFieldString,
+		"weekday":    ListFieldInt,
+		"start_time": ListFieldTime,
+		"start_date": ListFieldDate,
+		"end_date":   ListFieldDate,
+		"is_session": ListFieldBool,
+	}
+)
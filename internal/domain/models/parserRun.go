@@ -0,0 +1,42 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	RunTriggerSchedule = "schedule"
+	RunTriggerManual   = "manual"
+)
+
+const (
+	RunRunning = "running"
+	// Every group was parsed
+	RunSuccess = "success"
+	// Some groups failed
+	RunPartial = "partial"
+	RunFailed  = "failed"
+)
+
+// ParserRun is a single run of schedule parser
+type ParserRun struct {
+	UUID    uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Trigger string    `json:"trigger" example:"manual"`
+	// Groups run was limited to, every group is parsed if empty
+	Groups     []string   `json:"groups" example:"221-352"`
+	Status     string     `json:"status" example:"success"`
+	StartedAt  time.Time  `json:"started_at" example:"2025-03-12T09:00:00Z"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" example:"2025-03-12T09:00:30Z"`
+	Duration   int64      `json:"duration_ms" example:"30000"`
+	// Groups which schedules were parsed
+	GroupsProcessed int `json:"groups_processed" example:"1"`
+	// Timetables that did not change since last run
+	Skipped int `json:"skipped" example:"1"`
+	// Entities added by run
+	Added map[string]int `json:"added"`
+	// Errors of parsing schedule by group
+	Errors map[string][]string `json:"errors,omitempty"`
+	// Error that stopped run
+	Error string `json:"error,omitempty" example:"context canceled"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// RateLimitPolicy lets Burst requests at once, then Limit requests per second.
+// Every identity (ip, user or API key) has its own bucket of policy
+type RateLimitPolicy struct {
+	Name  string
+	Limit float64
+	Burst int
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Remaining int
+	// Time until bucket is full again
+	ResetAfter time.Duration
+	// Time until next request is allowed, zero for allowed requests
+	RetryAfter time.Duration
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// RefreshToken is server-side session of user, it is rotated on every refresh.
+// Tokens issued by rotation share family, so reuse of rotated token revokes the whole session
+type RefreshToken struct {
+	UUID      uuid.UUID
+	UserUUID  uuid.UUID
+	Family    uuid.UUID
+	TokenHash string
+	// jti of the last access token issued with refresh token
+	AccessJTI uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	RevokedAt *time.Time
+}
@@ -0,0 +1,27 @@
+package models
+
+const (
+	RoleViewer    = "viewer"
+	RoleEditor    = "editor"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// Permissions checked by routes, roles are mapped to them in db
+const (
+	PermScheduleRead   = "schedule:read"
+	PermScheduleWrite  = "schedule:write"
+	PermCatalogRead    = "catalog:read"
+	PermCatalogWrite   = "catalog:write"
+	PermUsersRead      = "users:read"
+	PermUsersManage    = "users:manage"
+	PermParserRead     = "parser:read"
+	PermParserRun      = "parser:run"
+	PermWebhooksManage = "webhooks:manage"
+)
+
+type Role struct {
+	Name        string   `json:"name" example:"moderator"`
+	Description string   `json:"description" example:"Edits schedule and catalogs, sees users and parser runs"`
+	Permissions []string `json:"permissions" example:"schedule:read,schedule:write"`
+}
@@ -36,3 +36,23 @@ type ScheduleData struct {
 	Link      string    `db:"link" json:"link" example:"https://rasp.dmami.ru"`
 	IsSession bool      `db:"is_session" json:"isSession,omitempty" example:"false"`
 }
+
+// Kinds of resources that are booked by pairs
+const (
+	ResourceGroup   = "group"
+	ResourceTeacher = "teacher"
+	ResourceRoom    = "room"
+)
+
+// Kinds of other resources of pairs, schedule is requested by them too
+const (
+	ResourceSubject  = "subject"
+	ResourceLocation = "location"
+)
+
+// ScheduleConflict is group, teacher or room booked for several pairs at the same time
+type ScheduleConflict struct {
+	Kind      string          `json:"kind" example:"teacher"`
+	Resource  string          `json:"resource" example:"Фамилия Имя Отчество"`
+	Schedules []*ScheduleData `json:"schedules"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// ScheduleChange is a record of pair being created, updated or deleted by parser or user
+type ScheduleChange struct {
+	UUID         uuid.UUID     `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	ScheduleUUID uuid.UUID     `json:"schedule_uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Group        string        `json:"group" example:"221-352"`
+	Action       string        `json:"action" example:"update"`
+	Before       *ScheduleData `json:"before,omitempty"`
+	After        *ScheduleData `json:"after,omitempty"`
+	Source       string        `json:"source" example:"parser:c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	CreatedAt    time.Time     `json:"created_at" example:"2025-03-12T09:00:00Z"`
+}
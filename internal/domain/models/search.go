@@ -0,0 +1,38 @@
+package models
+
+import "github.com/google/uuid"
+
+// Types of entities found by search
+const (
+	SearchTypeGroup   = "group"
+	SearchTypeTeacher = "teacher"
+	SearchTypeRoom    = "room"
+	SearchTypeSubject = "subject"
+)
+
+var SearchTypes = []string{SearchTypeGroup, SearchTypeTeacher, SearchTypeRoom, SearchTypeSubject}
+
+// SearchInitials are surname and first letters of name and patronymic of teacher, e.g. "Иванов И.И."
+type SearchInitials struct {
+	SecondName string
+	FirstName  string
+	// Empty if only first name is given
+	MiddleName string
+}
+
+type SearchQuery struct {
+	// Spellings of query in lower case: as typed, transliterated and typed in other keyboard layout
+	Terms []string
+	// Initials read from terms, teachers are also matched by them
+	Initials []SearchInitials
+	Types    []string
+	Limit    int
+}
+
+type SearchHit struct {
+	Type  string    `json:"type" example:"teacher"`
+	UUID  uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Title string    `json:"title" example:"Иванов Иван Иванович"`
+	// Higher score is better match, prefix matches go first for autocomplete
+	Score float64 `json:"score" example:"1.5"`
+}
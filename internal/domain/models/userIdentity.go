@@ -0,0 +1,23 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// UserIdentity links user to account of OpenID Connect provider
+type UserIdentity struct {
+	Issuer    string
+	Subject   string
+	UserUUID  uuid.UUID
+	CreatedAt time.Time
+}
+
+// OIDCClaims are claims of verified id token used to find or provision user
+type OIDCClaims struct {
+	Issuer   string
+	Subject  string
+	Username string
+	// Values of role claim, mapped to role of provisioned user
+	Groups []string
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/google/uuid"
+
+// UserScope limits schedules user can edit to group, location or groups with number prefix.
+// Exactly one of fields is set, user without scopes edits every schedule
+type UserScope struct {
+	UUID         uuid.UUID
+	UserUUID     uuid.UUID
+	GroupUUID    *uuid.UUID
+	LocationUUID *uuid.UUID
+	GroupPrefix  string
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+const EventScheduleChanged = "schedule.changed"
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is subscription to changes of pairs of group, teacher or room
+type Webhook struct {
+	UUID      uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	URL       string    `json:"url" example:"https://example.com/raspyx"`
+	Secret    string    `json:"-"`
+	Kind      string    `json:"kind" example:"group"`
+	Resource  string    `json:"resource" example:"221-352"`
+	Owner     string    `json:"owner" example:"username"`
+	CreatedAt time.Time `json:"created_at" example:"2025-03-12T09:00:00Z"`
+}
+
+// WebhookDelivery is payload queued for sending to webhook
+type WebhookDelivery struct {
+	UUID           uuid.UUID         `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	WebhookUUID    uuid.UUID         `json:"webhook_uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	URL            string            `json:"-"`
+	Secret         string            `json:"-"`
+	Event          string            `json:"event" example:"schedule.changed"`
+	Payload        json.RawMessage   `json:"payload" swaggertype:"object"`
+	Status         string            `json:"status" example:"pending"`
+	Attempts       int               `json:"attempts" example:"1"`
+	NextAttemptAt  time.Time         `json:"next_attempt_at" example:"2025-03-12T09:00:30Z"`
+	LastStatusCode int               `json:"last_status_code,omitempty" example:"500"`
+	LastError      string            `json:"last_error,omitempty" example:"unexpected status 500"`
+	CreatedAt      time.Time         `json:"created_at" example:"2025-03-12T09:00:00Z"`
+	DeliveredAt    *time.Time        `json:"delivered_at,omitempty" example:"2025-03-12T09:00:30Z"`
+	History        []*WebhookAttempt `json:"history,omitempty"`
+}
+
+type WebhookAttempt struct {
+	UUID         uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	DeliveryUUID uuid.UUID `json:"delivery_uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	StatusCode   int       `json:"status_code,omitempty" example:"500"`
+	Error        string    `json:"error,omitempty" example:"unexpected status 500"`
+	AttemptedAt  time.Time `json:"attempted_at" example:"2025-03-12T09:00:00Z"`
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"raspyx/internal/domain/models"
+	"slices"
+	"strings"
+)
+
+const (
+	apiKeyPrefix    = "rpx_"
+	apiKeyShownLen  = len(apiKeyPrefix) + 8
+	apiKeyMaxName   = 64
+	apiKeyRandBytes = 32
+)
+
+// apiKeyScopes are permissions which can be granted to keys
+var apiKeyScopes = []string{
+	models.PermScheduleRead,
+	models.PermScheduleWrite,
+	models.PermCatalogRead,
+	models.PermCatalogWrite,
+	models.PermUsersRead,
+	models.PermUsersManage,
+	models.PermParserRead,
+	models.PermParserRun,
+}
+
+type APIKeyService struct{}
+
+func NewAPIKeyService() *APIKeyService {
+	return &APIKeyService{}
+}
+
+func (s *APIKeyService) Validate(key *models.APIKey) bool {
+	name := strings.TrimSpace(key.Name)
+	if name == "" || len(name) > apiKeyMaxName {
+		return false
+	}
+	if len(key.Scopes) == 0 {
+		return false
+	}
+	for _, scope := range key.Scopes {
+		if !slices.Contains(apiKeyScopes, scope) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GenerateKey returns secret of new key and its prefix shown in lists of keys
+func (s *APIKeyService) GenerateKey() (string, string, error) {
+	b := make([]byte, apiKeyRandBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, key[:apiKeyShownLen], nil
+}
+
+func (s *APIKeyService) HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
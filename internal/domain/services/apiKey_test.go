@@ -0,0 +1,46 @@
+package services
+
+import (
+	"raspyx/internal/domain/models"
+	"strings"
+	"testing"
+)
+
+func TestAPIKeyService_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		key  *models.APIKey
+		want bool
+	}{
+		{"valid key", &models.APIKey{Name: "portal", Scopes: []string{models.PermScheduleRead}}, true},
+		{"empty name", &models.APIKey{Name: " ", Scopes: []string{models.PermScheduleRead}}, false},
+		{"long name", &models.APIKey{Name: strings.Repeat("a", 65), Scopes: []string{models.PermScheduleRead}}, false},
+		{"without scopes", &models.APIKey{Name: "portal"}, false},
+		{"unknown scope", &models.APIKey{Name: "portal", Scopes: []string{models.PermScheduleRead, "schedule:delete"}}, false},
+	}
+
+	apiKeyService := NewAPIKeyService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apiKeyService.Validate(tt.key); got != tt.want {
+				t.Errorf("APIKeyService.Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeyService_GenerateKey(t *testing.T) {
+	apiKeyService := NewAPIKeyService()
+
+	key, prefix, err := apiKeyService.GenerateKey()
+	if err != nil {
+		t.Fatalf("APIKeyService.GenerateKey() error = %v", err)
+	}
+	if !strings.HasPrefix(key, prefix) || !strings.HasPrefix(prefix, "rpx_") || len(prefix) != 12 {
+		t.Errorf("APIKeyService.GenerateKey() = %v, %v", key, prefix)
+	}
+	if apiKeyService.HashKey(key) == apiKeyService.HashKey(prefix) {
+		t.Errorf("APIKeyService.HashKey() is equal for different keys")
+	}
+}
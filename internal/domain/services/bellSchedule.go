@@ -0,0 +1,111 @@
+package services
+
+import (
+	"raspyx/internal/domain/models"
+	"sort"
+	"time"
+)
+
+type BellScheduleService struct{}
+
+func NewBellScheduleService() *BellScheduleService {
+	return &BellScheduleService{}
+}
+
+func (s *BellScheduleService) Validate(bell *models.BellSchedule) bool {
+	if bell.PairNum < 1 {
+		return false
+	}
+	if !bell.StartTime.Before(bell.EndTime) {
+		return false
+	}
+	if bell.StartDate != nil && bell.EndDate != nil && bell.StartDate.After(*bell.EndDate) {
+		return false
+	}
+
+	return true
+}
+
+// Slot returns bell of given pair number for location on date.
+// Bells of location win over default ones, bells limited by dates win over unlimited ones.
+func (s *BellScheduleService) Slot(bells []*models.BellSchedule, location string, date time.Time, pairNum int) (*models.BellSchedule, bool) {
+	var slot *models.BellSchedule
+	best := -1
+	for _, bell := range bells {
+		if bell.PairNum != pairNum {
+			continue
+		}
+
+		rank, ok := bellRank(bell, location, date)
+		if ok && rank > best {
+			slot, best = bell, rank
+		}
+	}
+
+	return slot, slot != nil
+}
+
+// Slots returns bells of every pair number for location on date ordered by pair number
+func (s *BellScheduleService) Slots(bells []*models.BellSchedule, location string, date time.Time) []*models.BellSchedule {
+	var pairNums []int
+	checked := make(map[int]bool)
+	for _, bell := range bells {
+		if !checked[bell.PairNum] {
+			checked[bell.PairNum] = true
+			pairNums = append(pairNums, bell.PairNum)
+		}
+	}
+	sort.Ints(pairNums)
+
+	var slots []*models.BellSchedule
+	for _, pairNum := range pairNums {
+		if slot, ok := s.Slot(bells, location, date, pairNum); ok {
+			slots = append(slots, slot)
+		}
+	}
+
+	return slots
+}
+
+// PairNum returns number of pair starting at given time for location on date, 0 if no bell matches
+func (s *BellScheduleService) PairNum(bells []*models.BellSchedule, location string, date, startTime time.Time) int {
+	checked := make(map[int]bool)
+	for _, bell := range bells {
+		if checked[bell.PairNum] {
+			continue
+		}
+		checked[bell.PairNum] = true
+
+		slot, ok := s.Slot(bells, location, date, bell.PairNum)
+		if ok && slot.StartTime.Format(time.TimeOnly) == startTime.Format(time.TimeOnly) {
+			return slot.PairNum
+		}
+	}
+
+	return 0
+}
+
+// bellRank reports whether bell applies to location on date and how specific it is
+func bellRank(bell *models.BellSchedule, location string, date time.Time) (int, bool) {
+	rank := 0
+
+	if bell.LocationUUID != nil {
+		if bell.Location != location {
+			return 0, false
+		}
+		rank += 2
+	}
+
+	if bell.StartDate != nil || bell.EndDate != nil {
+		day := date.Format(time.DateOnly)
+		if bell.StartDate != nil && day < bell.StartDate.Format(time.DateOnly) {
+			return 0, false
+		}
+		if bell.EndDate != nil && day > bell.EndDate.Format(time.DateOnly) {
+			return 0, false
+		}
+		rank++
+	}
+
+	return rank, true
+}
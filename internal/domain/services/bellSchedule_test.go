@@ -0,0 +1,98 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestBellScheduleService_Validate(t *testing.T) {
+	clock := func(s string) time.Time {
+		c, _ := time.Parse(time.TimeOnly, s)
+		return c
+	}
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &d
+	}
+
+	tests := []struct {
+		name string
+		bell *models.BellSchedule
+		want bool
+	}{
+		{"valid bell", &models.BellSchedule{PairNum: 1, StartTime: clock("09:00:00"), EndTime: clock("10:30:00")}, true},
+		{"zero pair num", &models.BellSchedule{PairNum: 0, StartTime: clock("09:00:00"), EndTime: clock("10:30:00")}, false},
+		{"end before start", &models.BellSchedule{PairNum: 1, StartTime: clock("10:30:00"), EndTime: clock("09:00:00")}, false},
+		{"end date before start date", &models.BellSchedule{
+			PairNum: 1, StartTime: clock("09:00:00"), EndTime: clock("10:30:00"),
+			StartDate: date("2025-06-30"), EndDate: date("2025-02-01"),
+		}, false},
+	}
+
+	bellService := NewBellScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bellService.Validate(tt.bell); got != tt.want {
+				t.Errorf("BellScheduleService.Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBellScheduleService_PairNum(t *testing.T) {
+	clock := func(s string) time.Time {
+		c, _ := time.Parse(time.TimeOnly, s)
+		return c
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	datePtr := func(s string) *time.Time {
+		d := date(s)
+		return &d
+	}
+
+	locationUUID := uuid.MustParse("c555b9e8-0d7a-11f0-adcd-20114d2008d9")
+	bells := []*models.BellSchedule{
+		{PairNum: 1, StartTime: clock("09:00:00"), EndTime: clock("10:30:00")},
+		{PairNum: 2, StartTime: clock("10:40:00"), EndTime: clock("12:10:00")},
+		{
+			PairNum: 1, StartTime: clock("08:30:00"), EndTime: clock("10:00:00"),
+			LocationUUID: &locationUUID, Location: "Павла Корчагина",
+		},
+		{
+			PairNum: 2, StartTime: clock("11:00:00"), EndTime: clock("12:30:00"),
+			StartDate: datePtr("2025-09-01"), EndDate: datePtr("2025-12-31"),
+		},
+	}
+
+	tests := []struct {
+		name      string
+		location  string
+		date      time.Time
+		startTime time.Time
+		want      int
+	}{
+		{"default bell", "Автозаводская", date("2025-03-12"), clock("09:00:00"), 1},
+		{"second default bell", "Автозаводская", date("2025-03-12"), clock("10:40:00"), 2},
+		{"location bell wins over default", "Павла Корчагина", date("2025-03-12"), clock("08:30:00"), 1},
+		{"default time is overridden for location", "Павла Корчагина", date("2025-03-12"), clock("09:00:00"), 0},
+		{"semester bell wins over default", "Автозаводская", date("2025-10-01"), clock("11:00:00"), 2},
+		{"semester bell outside of its dates", "Автозаводская", date("2025-03-12"), clock("11:00:00"), 0},
+		{"odd start time", "Автозаводская", date("2025-03-12"), clock("13:00:00"), 0},
+	}
+
+	bellService := NewBellScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bellService.PairNum(bells, tt.location, tt.date, tt.startTime); got != tt.want {
+				t.Errorf("BellScheduleService.PairNum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,307 @@
 package services
 
+import (
+	"errors"
+	"fmt"
+	"raspyx/internal/domain/models"
+	"strings"
+	"time"
+)
+
 type ScheduleService struct{}
 
 func NewScheduleService() *ScheduleService {
 	return &ScheduleService{}
 }
+
+var (
+	InvalidWeek = errors.New("invalid week")
+)
+
+// TakesPlace reports whether schedule has a pair on given date
+func (s *ScheduleService) TakesPlace(schedule *models.ScheduleData, date time.Time) bool {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	if date.Before(schedule.StartDate) || date.After(schedule.EndDate) {
+		return false
+	}
+
+	// Session pairs are bound to dates, regular pairs repeat every week
+	if schedule.IsSession {
+		return true
+	}
+	return int(date.Weekday()) == schedule.Weekday
+}
+
+// IsBusy reports whether any of schedules takes place on date between st and et
+func (s *ScheduleService) IsBusy(schedules []*models.ScheduleData, date, st, et time.Time) bool {
+	for _, schedule := range schedules {
+		if !s.TakesPlace(schedule, date) {
+			continue
+		}
+		if schedule.StartTime.Format(time.TimeOnly) < et.Format(time.TimeOnly) &&
+			st.Format(time.TimeOnly) < schedule.EndTime.Format(time.TimeOnly) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ISOWeekStart returns monday of given ISO 8601 week
+func (s *ScheduleService) ISOWeekStart(year, week int) (time.Time, error) {
+	if week < 1 || week > 53 {
+		return time.Time{}, InvalidWeek
+	}
+
+	// January 4th is always in the first ISO week
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+
+	// Not every year has 53 weeks
+	if y, w := monday.ISOWeek(); y != year || w != week {
+		return time.Time{}, InvalidWeek
+	}
+
+	return monday, nil
+}
+
+// Overlaps reports whether two pairs take place at the same time on the same weekday within common dates.
+// Common dates must include that weekday, otherwise pairs never meet
+func (s *ScheduleService) Overlaps(a, b *models.ScheduleData) bool {
+	if a.Weekday != b.Weekday {
+		return false
+	}
+
+	if a.StartTime.Format(time.TimeOnly) >= b.EndTime.Format(time.TimeOnly) ||
+		b.StartTime.Format(time.TimeOnly) >= a.EndTime.Format(time.TimeOnly) {
+		return false
+	}
+
+	start := dateOnly(a.StartDate)
+	if b.StartDate.After(a.StartDate) {
+		start = dateOnly(b.StartDate)
+	}
+	end := dateOnly(a.EndDate)
+	if b.EndDate.Before(a.EndDate) {
+		end = dateOnly(b.EndDate)
+	}
+
+	return !firstWeekday(start, a.Weekday).After(end)
+}
+
+// SameLesson reports whether two pairs are one lesson of several groups, like stream lecture.
+// Schedule is stored per group, so such lesson is several pairs with the same teacher or room
+func (s *ScheduleService) SameLesson(a, b *models.ScheduleData) bool {
+	return a.Subject == b.Subject && a.Type == b.Type && a.Weekday == b.Weekday && a.IsSession == b.IsSession &&
+		a.StartTime.Format(time.TimeOnly) == b.StartTime.Format(time.TimeOnly) &&
+		a.EndTime.Format(time.TimeOnly) == b.EndTime.Format(time.TimeOnly) &&
+		a.StartDate.Format(time.DateOnly) == b.StartDate.Format(time.DateOnly) &&
+		a.EndDate.Format(time.DateOnly) == b.EndDate.Format(time.DateOnly)
+}
+
+type conflictKey struct {
+	kind     string
+	resource string
+}
+
+// Conflicts finds every pair of schedules that share group, teacher or room at the same time.
+// Teacher or room of one lesson of several groups is booked once
+func (s *ScheduleService) Conflicts(schedules []*models.ScheduleData) []*models.ScheduleConflict {
+	// Grouping schedules by booked resources, keys are kept in order of appearance
+	var keys []conflictKey
+	booked := make(map[conflictKey][]*models.ScheduleData)
+	book := func(key conflictKey, schedule *models.ScheduleData) {
+		if _, ok := booked[key]; !ok {
+			keys = append(keys, key)
+		}
+		booked[key] = append(booked[key], schedule)
+	}
+
+	for _, schedule := range schedules {
+		if schedule.Group != "" {
+			book(conflictKey{models.ResourceGroup, schedule.Group}, schedule)
+		}
+		for _, teacher := range nonEmpty(schedule.Teachers) {
+			book(conflictKey{models.ResourceTeacher, teacher}, schedule)
+		}
+		for _, room := range nonEmpty(schedule.Rooms) {
+			book(conflictKey{models.ResourceRoom, room}, schedule)
+		}
+	}
+
+	var conflicts []*models.ScheduleConflict
+	for _, key := range keys {
+		bookings := booked[key]
+		for i := 0; i < len(bookings); i++ {
+			for j := i + 1; j < len(bookings); j++ {
+				if key.kind != models.ResourceGroup && s.SameLesson(bookings[i], bookings[j]) {
+					continue
+				}
+				if s.Overlaps(bookings[i], bookings[j]) {
+					conflicts = append(conflicts, &models.ScheduleConflict{
+						Kind:      key.kind,
+						Resource:  key.resource,
+						Schedules: []*models.ScheduleData{bookings[i], bookings[j]},
+					})
+				}
+			}
+		}
+	}
+
+	return conflicts
+}
+
+const (
+	calendarTZID     = "Europe/Moscow"
+	calendarLineSize = 75
+)
+
+// ScheduleZone is time zone of pairs and dates of schedule.
+// Moscow has no DST, so a fixed zone is enough to convert local pair times to UTC
+var ScheduleZone = time.FixedZone("MSK", 3*60*60)
+
+// MakeCalendar builds RFC 5545 calendar with given name from schedules.
+// Regular pairs become weekly recurring events between start and end dates,
+// session pairs become single events.
+func (s *ScheduleService) MakeCalendar(name string, schedules []*models.ScheduleData, now time.Time) []byte {
+	var b strings.Builder
+
+	writeCalendarLine(&b, "BEGIN:VCALENDAR")
+	writeCalendarLine(&b, "VERSION:2.0")
+	writeCalendarLine(&b, "PRODID:-//raspyx//schedule//RU")
+	writeCalendarLine(&b, "CALSCALE:GREGORIAN")
+	writeCalendarLine(&b, "METHOD:PUBLISH")
+	writeCalendarLine(&b, "X-WR-CALNAME:"+escapeCalendarText(name))
+	writeCalendarLine(&b, "X-WR-TIMEZONE:"+calendarTZID)
+
+	// Timezone definition for TZID used in events
+	writeCalendarLine(&b, "BEGIN:VTIMEZONE")
+	writeCalendarLine(&b, "TZID:"+calendarTZID)
+	writeCalendarLine(&b, "BEGIN:STANDARD")
+	writeCalendarLine(&b, "DTSTART:19700101T000000")
+	writeCalendarLine(&b, "TZOFFSETFROM:+0300")
+	writeCalendarLine(&b, "TZOFFSETTO:+0300")
+	writeCalendarLine(&b, "TZNAME:MSK")
+	writeCalendarLine(&b, "END:STANDARD")
+	writeCalendarLine(&b, "END:VTIMEZONE")
+
+	dtstamp := now.UTC().Format("20060102T150405Z")
+	for _, schedule := range schedules {
+		writeCalendarEvent(&b, schedule, dtstamp)
+	}
+
+	writeCalendarLine(&b, "END:VCALENDAR")
+
+	return []byte(b.String())
+}
+
+func writeCalendarEvent(b *strings.Builder, schedule *models.ScheduleData, dtstamp string) {
+	// Date of the first pair
+	date := schedule.StartDate
+	if !schedule.IsSession {
+		date = firstWeekday(schedule.StartDate, schedule.Weekday)
+		if date.After(schedule.EndDate) {
+			return
+		}
+	}
+
+	teachers := nonEmpty(schedule.Teachers)
+	rooms := nonEmpty(schedule.Rooms)
+
+	summary := schedule.Subject
+	if schedule.Type != "" {
+		summary = fmt.Sprintf("%v (%v)", schedule.Subject, schedule.Type)
+	}
+
+	location := append([]string{schedule.Location}, rooms...)
+
+	var description []string
+	if schedule.Group != "" {
+		description = append(description, "Группа: "+schedule.Group)
+	}
+	if len(teachers) > 0 {
+		description = append(description, "Преподаватели: "+strings.Join(teachers, ", "))
+	}
+	if len(rooms) > 0 {
+		description = append(description, "Аудитории: "+strings.Join(rooms, ", "))
+	}
+	if schedule.Link != "" {
+		description = append(description, "Ссылка: "+schedule.Link)
+	}
+
+	writeCalendarLine(b, "BEGIN:VEVENT")
+	writeCalendarLine(b, fmt.Sprintf("UID:%v@raspyx", schedule.UUID))
+	writeCalendarLine(b, "DTSTAMP:"+dtstamp)
+	writeCalendarLine(b, fmt.Sprintf("DTSTART;TZID=%v:%v", calendarTZID, calendarLocalTime(date, schedule.StartTime)))
+	writeCalendarLine(b, fmt.Sprintf("DTEND;TZID=%v:%v", calendarTZID, calendarLocalTime(date, schedule.EndTime)))
+	if !schedule.IsSession {
+		writeCalendarLine(b, "RRULE:FREQ=WEEKLY;UNTIL="+calendarUntil(schedule.EndDate, schedule.EndTime))
+	}
+	writeCalendarLine(b, "SUMMARY:"+escapeCalendarText(summary))
+	writeCalendarLine(b, "LOCATION:"+escapeCalendarText(strings.Join(location, ", ")))
+	if len(description) > 0 {
+		writeCalendarLine(b, "DESCRIPTION:"+escapeCalendarText(strings.Join(description, "\n")))
+	}
+	if schedule.Link != "" {
+		writeCalendarLine(b, "URL:"+schedule.Link)
+	}
+	writeCalendarLine(b, "END:VEVENT")
+}
+
+// dateOnly returns given date at midnight UTC, as dates of pairs are stored
+func dateOnly(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// firstWeekday returns first date not before given date with given weekday (1 - monday)
+func firstWeekday(date time.Time, weekday int) time.Time {
+	shift := (weekday - int(date.Weekday()) + 7) % 7
+	return date.AddDate(0, 0, shift)
+}
+
+func calendarLocalTime(date, clock time.Time) string {
+	return fmt.Sprintf("%vT%02d%02d%02d", date.Format("20060102"), clock.Hour(), clock.Minute(), clock.Second())
+}
+
+func calendarUntil(date, clock time.Time) string {
+	until := time.Date(
+		date.Year(), date.Month(), date.Day(),
+		clock.Hour(), clock.Minute(), clock.Second(), 0, ScheduleZone,
+	)
+	return until.UTC().Format("20060102T150405Z")
+}
+
+func nonEmpty(values []string) []string {
+	var res []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeCalendarLine writes content line folded to 75 octets without splitting utf-8 characters
+func writeCalendarLine(b *strings.Builder, line string) {
+	size := 0
+	for _, r := range line {
+		l := len(string(r))
+		if size+l > calendarLineSize {
+			b.WriteString("\r\n ")
+			size = 1
+		}
+		b.WriteRune(r)
+		size += l
+	}
+	b.WriteString("\r\n")
+}
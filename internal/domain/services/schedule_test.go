@@ -0,0 +1,368 @@
+package services
+
+import (
+	"errors"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleService_MakeCalendar(t *testing.T) {
+	clock := func(s string) time.Time {
+		c, _ := time.Parse(time.TimeOnly, s)
+		return c
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	scheduleUUID := uuid.MustParse("c555b9e8-0d7a-11f0-adcd-20114d2008d9")
+
+	tests := []struct {
+		name      string
+		schedule  *models.ScheduleData
+		want      []string
+		wantNotIn []string
+	}{
+		{
+			name: "regular pair starts on first matching weekday",
+			schedule: &models.ScheduleData{
+				UUID:      scheduleUUID,
+				Group:     "221-352",
+				Teachers:  []string{"Фамилия Имя Отчество"},
+				Rooms:     []string{"ав4805", ""},
+				Subject:   "Иностранный язык",
+				Type:      "Практика",
+				Location:  "Автозаводская",
+				StartTime: clock("09:00:00"),
+				EndTime:   clock("10:30:00"),
+				StartDate: date("2025-02-01"),
+				EndDate:   date("2025-06-01"),
+				Weekday:   3,
+			},
+			want: []string{
+				"UID:c555b9e8-0d7a-11f0-adcd-20114d2008d9@raspyx",
+				"DTSTART;TZID=Europe/Moscow:20250205T090000",
+				"DTEND;TZID=Europe/Moscow:20250205T103000",
+				"RRULE:FREQ=WEEKLY;UNTIL=20250601T073000Z",
+				"SUMMARY:Иностранный язык (Практика)",
+				"LOCATION:Автозаводская\\, ав4805\r\n",
+			},
+		},
+		{
+			name: "session pair is single event",
+			schedule: &models.ScheduleData{
+				UUID:      scheduleUUID,
+				Subject:   "Математика",
+				Type:      "Экзамен",
+				Location:  "Автозаводская",
+				StartTime: clock("12:20:00"),
+				EndTime:   clock("13:50:00"),
+				StartDate: date("2025-06-10"),
+				EndDate:   date("2025-06-10"),
+				Weekday:   2,
+				Link:      "https://online.mospolytech.ru/",
+				IsSession: true,
+			},
+			want: []string{
+				"DTSTART;TZID=Europe/Moscow:20250610T122000",
+				"URL:https://online.mospolytech.ru/",
+			},
+			wantNotIn: []string{"RRULE"},
+		},
+		{
+			name: "regular pair without occurrences is skipped",
+			schedule: &models.ScheduleData{
+				UUID:      scheduleUUID,
+				Subject:   "Математика",
+				StartTime: clock("09:00:00"),
+				EndTime:   clock("10:30:00"),
+				StartDate: date("2025-02-04"),
+				EndDate:   date("2025-02-04"),
+				Weekday:   1,
+			},
+			wantNotIn: []string{"BEGIN:VEVENT"},
+		},
+	}
+
+	scheduleService := NewScheduleService()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := string(scheduleService.MakeCalendar("221-352", []*models.ScheduleData{tt.schedule}, now))
+			for _, w := range tt.want {
+				if !strings.Contains(calendar, w) {
+					t.Errorf("ScheduleService.MakeCalendar() does not contain %q:\n%v", w, calendar)
+				}
+			}
+			for _, w := range tt.wantNotIn {
+				if strings.Contains(calendar, w) {
+					t.Errorf("ScheduleService.MakeCalendar() contains %q:\n%v", w, calendar)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCalendarLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short line", "SUMMARY:Математика"},
+		{"long cyrillic line", "DESCRIPTION:" + strings.Repeat("Преподаватели", 20)},
+		{"long ascii line", "DESCRIPTION:" + strings.Repeat("a", 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			writeCalendarLine(&b, tt.line)
+
+			for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+				if len(l) > calendarLineSize {
+					t.Errorf("writeCalendarLine() line is %v octets long", len(l))
+				}
+			}
+
+			unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+			if unfolded != tt.line+"\r\n" {
+				t.Errorf("writeCalendarLine() unfolded = %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestScheduleService_TakesPlace(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	regular := &models.ScheduleData{
+		StartDate: date("2025-02-01"),
+		EndDate:   date("2025-06-01"),
+		Weekday:   3,
+	}
+	session := &models.ScheduleData{
+		StartDate: date("2025-06-10"),
+		EndDate:   date("2025-06-10"),
+		Weekday:   2,
+		IsSession: true,
+	}
+
+	tests := []struct {
+		name     string
+		schedule *models.ScheduleData
+		date     time.Time
+		want     bool
+	}{
+		{"regular pair on its weekday", regular, date("2025-03-12"), true},
+		{"regular pair on another weekday", regular, date("2025-03-13"), false},
+		{"regular pair before start date", regular, date("2025-01-29"), false},
+		{"regular pair after end date", regular, date("2025-06-04"), false},
+		{"session pair on its date", session, date("2025-06-10"), true},
+		{"session pair week later", session, date("2025-06-17"), false},
+	}
+
+	scheduleService := NewScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduleService.TakesPlace(tt.schedule, tt.date); got != tt.want {
+				t.Errorf("ScheduleService.TakesPlace() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleService_ISOWeekStart(t *testing.T) {
+	tests := []struct {
+		name    string
+		year    int
+		week    int
+		want    string
+		wantErr error
+	}{
+		{"week in the middle of year", 2025, 11, "2025-03-10", nil},
+		{"first week starts in previous year", 2025, 1, "2024-12-30", nil},
+		{"53rd week exists", 2020, 53, "2020-12-28", nil},
+		{"53rd week does not exist", 2025, 53, "", InvalidWeek},
+		{"zero week", 2025, 0, "", InvalidWeek},
+	}
+
+	scheduleService := NewScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scheduleService.ISOWeekStart(tt.year, tt.week)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ScheduleService.ISOWeekStart() err = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.Format(time.DateOnly) != tt.want {
+				t.Errorf("ScheduleService.ISOWeekStart() = %v, want %v", got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleService_Conflicts(t *testing.T) {
+	clock := func(s string) time.Time {
+		c, _ := time.Parse(time.TimeOnly, s)
+		return c
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	pair := func(group, teacher, room, start, end, startDate, endDate string, weekday int) *models.ScheduleData {
+		return &models.ScheduleData{
+			UUID:      uuid.New(),
+			Group:     group,
+			Teachers:  []string{teacher},
+			Rooms:     []string{room},
+			StartTime: clock(start),
+			EndTime:   clock(end),
+			StartDate: date(startDate),
+			EndDate:   date(endDate),
+			Weekday:   weekday,
+		}
+	}
+	lesson := func(schedule *models.ScheduleData, subject string) *models.ScheduleData {
+		schedule.Subject, schedule.Type = subject, "Лекция"
+		return schedule
+	}
+
+	tests := []struct {
+		name      string
+		schedules []*models.ScheduleData
+		want      []string
+	}{
+		{
+			name: "teacher in two groups at the same time",
+			schedules: []*models.ScheduleData{
+				lesson(pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1), "Физика"),
+				lesson(pair("221-351", "Фамилия Имя", "ав4810", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1), "Химия"),
+			},
+			want: []string{"teacher:Фамилия Имя"},
+		},
+		{
+			name: "lecture of two groups is one booking of teacher and room",
+			schedules: []*models.ScheduleData{
+				lesson(pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1), "Физика"),
+				lesson(pair("221-351", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1), "Физика"),
+				lesson(pair("221-351", "", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1), "Химия"),
+			},
+			want: []string{"room:ав4805", "room:ав4805", "group:221-351"},
+		},
+		{
+			name: "room and group clash on overlapping times",
+			schedules: []*models.ScheduleData{
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1),
+				pair("221-352", "", "ав4805", "10:00:00", "11:30:00", "2025-03-01", "2025-04-01", 1),
+			},
+			want: []string{"group:221-352", "room:ав4805"},
+		},
+		{
+			name: "adjacent pairs do not clash",
+			schedules: []*models.ScheduleData{
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1),
+				pair("221-352", "Фамилия Имя", "ав4805", "10:30:00", "12:00:00", "2025-02-01", "2025-06-01", 1),
+			},
+		},
+		{
+			name: "different weekdays do not clash",
+			schedules: []*models.ScheduleData{
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 1),
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-06-01", 2),
+			},
+		},
+		{
+			name: "different dates do not clash",
+			schedules: []*models.ScheduleData{
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-01", "2025-03-01", 1),
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-03-02", "2025-06-01", 1),
+			},
+		},
+		{
+			name: "common dates without weekday of pairs do not clash",
+			schedules: []*models.ScheduleData{
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-03", "2025-02-05", 1),
+				pair("221-352", "Фамилия Имя", "ав4805", "09:00:00", "10:30:00", "2025-02-04", "2025-02-08", 1),
+			},
+		},
+	}
+
+	scheduleService := NewScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, c := range scheduleService.Conflicts(tt.schedules) {
+				got = append(got, c.Kind+":"+c.Resource)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ScheduleService.Conflicts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleService_IsBusy(t *testing.T) {
+	clock := func(s string) time.Time {
+		c, _ := time.Parse(time.TimeOnly, s)
+		return c
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+
+	schedules := []*models.ScheduleData{
+		{
+			StartTime: clock("09:00:00"),
+			EndTime:   clock("10:30:00"),
+			StartDate: date("2025-02-01"),
+			EndDate:   date("2025-06-01"),
+			Weekday:   3,
+		},
+		{
+			StartTime: clock("12:20:00"),
+			EndTime:   clock("13:50:00"),
+			StartDate: date("2025-06-10"),
+			EndDate:   date("2025-06-10"),
+			Weekday:   2,
+			IsSession: true,
+		},
+	}
+
+	tests := []struct {
+		name  string
+		date  time.Time
+		start time.Time
+		end   time.Time
+		want  bool
+	}{
+		{"regular pair in slot", date("2025-03-12"), clock("09:00:00"), clock("10:30:00"), true},
+		{"regular pair partly in slot", date("2025-03-12"), clock("10:00:00"), clock("11:00:00"), true},
+		{"slot after regular pair", date("2025-03-12"), clock("10:40:00"), clock("12:10:00"), false},
+		{"regular pair on another weekday", date("2025-03-13"), clock("09:00:00"), clock("10:30:00"), false},
+		{"session pair in slot", date("2025-06-10"), clock("12:20:00"), clock("13:50:00"), true},
+		{"session pair on another date", date("2025-06-17"), clock("12:20:00"), clock("13:50:00"), false},
+	}
+
+	scheduleService := NewScheduleService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := scheduleService.IsBusy(schedules, tt.date, tt.start, tt.end); got != tt.want {
+				t.Errorf("ScheduleService.IsBusy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
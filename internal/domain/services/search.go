@@ -0,0 +1,113 @@
+package services
+
+import (
+	"raspyx/internal/domain/models"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type SearchService struct{}
+
+func NewSearchService() *SearchService {
+	return &SearchService{}
+}
+
+// Latin letters to Cyrillic, longer combinations are matched first
+var transliteration = []struct {
+	latin    string
+	cyrillic string
+}{
+	{"shch", "щ"}, {"sch", "щ"},
+	{"zh", "ж"}, {"kh", "х"}, {"ts", "ц"}, {"ch", "ч"}, {"sh", "ш"},
+	{"yu", "ю"}, {"ya", "я"}, {"yo", "е"}, {"ye", "е"}, {"iy", "ий"}, {"yy", "ый"},
+	{"a", "а"}, {"b", "б"}, {"c", "к"}, {"d", "д"}, {"e", "е"}, {"f", "ф"}, {"g", "г"},
+	{"h", "х"}, {"i", "и"}, {"j", "й"}, {"k", "к"}, {"l", "л"}, {"m", "м"}, {"n", "н"},
+	{"o", "о"}, {"p", "п"}, {"q", "к"}, {"r", "р"}, {"s", "с"}, {"t", "т"}, {"u", "у"},
+	{"v", "в"}, {"w", "в"}, {"x", "кс"}, {"y", "ы"}, {"z", "з"}, {"'", "ь"},
+}
+
+// Keys of QWERTY layout to keys of ЙЦУКЕН layout
+var keyboardLayout = strings.NewReplacer(
+	"q", "й", "w", "ц", "e", "у", "r", "к", "t", "е", "y", "н", "u", "г", "i", "ш", "o", "щ", "p", "з", "[", "х", "]", "ъ",
+	"a", "ф", "s", "ы", "d", "в", "f", "а", "g", "п", "h", "р", "j", "о", "k", "л", "l", "д", ";", "ж", "'", "э",
+	"z", "я", "x", "ч", "c", "с", "v", "м", "b", "и", "n", "т", "m", "ь", ",", "б", ".", "ю",
+)
+
+var (
+	// Surname followed by initials: "иванов и.и.", "иванов и. и", "иванов и"
+	initialsAfter = regexp.MustCompile(`^([\p{L}-]{2,})\s+(\p{L})(?:\.\s*(\p{L})\.?|\.)?$`)
+	// Initials followed by surname: "и.и. иванов", "и. иванов"
+	initialsBefore = regexp.MustCompile(`^(\p{L})\.\s*(?:(\p{L})\.\s*)?([\p{L}-]{2,})$`)
+)
+
+// Normalize lowers query, replaces ё with е and collapses spaces
+func (s *SearchService) Normalize(query string) string {
+	query = strings.ReplaceAll(strings.ToLower(query), "ё", "е")
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// Terms returns spellings of normalized query: as typed, transliterated from Latin and typed in QWERTY layout instead of ЙЦУКЕН
+func (s *SearchService) Terms(query string) []string {
+	terms := []string{query}
+	if !strings.ContainsFunc(query, isLatin) {
+		return terms
+	}
+
+	for _, term := range []string{s.Transliterate(query), keyboardLayout.Replace(query)} {
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// Transliterate replaces Latin letters of lower case query with Cyrillic, e.g. "ivanov" becomes "иванов"
+func (s *SearchService) Transliterate(query string) string {
+	var b strings.Builder
+	prev := ' '
+	for len(query) > 0 {
+		replaced := false
+		for _, t := range transliteration {
+			if !strings.HasPrefix(query, t.latin) {
+				continue
+			}
+			cyrillic := t.cyrillic
+			// "y" after vowel is й as in "sergey"
+			if t.latin == "y" && strings.ContainsRune("аеиоуыэюя", prev) {
+				cyrillic = "й"
+			}
+			b.WriteString(cyrillic)
+			query = query[len(t.latin):]
+			prev = []rune(cyrillic)[len([]rune(cyrillic))-1]
+			replaced = true
+			break
+		}
+		if !replaced {
+			r := []rune(query)[0]
+			b.WriteRune(r)
+			query = query[len(string(r)):]
+			prev = r
+		}
+	}
+
+	return b.String()
+}
+
+// Initials reads surname and initials of teacher from normalized term, e.g. "иванов и.и." or "и.и. иванов"
+func (s *SearchService) Initials(term string) (models.SearchInitials, bool) {
+	if m := initialsAfter.FindStringSubmatch(term); m != nil {
+		return models.SearchInitials{SecondName: m[1], FirstName: m[2], MiddleName: m[3]}, true
+	}
+	if m := initialsBefore.FindStringSubmatch(term); m != nil {
+		return models.SearchInitials{SecondName: m[3], FirstName: m[1], MiddleName: m[2]}, true
+	}
+
+	return models.SearchInitials{}, false
+}
+
+func isLatin(r rune) bool {
+	return r < unicode.MaxASCII && unicode.IsLetter(r)
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"raspyx/internal/domain/models"
+	"reflect"
+	"testing"
+)
+
+func TestSearchService_Terms(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "cyrillic query is kept as is",
+			query: "  Иванов   Пётр ",
+			want:  []string{"иванов петр"},
+		},
+		{
+			name:  "transliterated name",
+			query: "Shcherbakov Sergey",
+			want:  []string{"shcherbakov sergey", "щербаков сергей", "ырсрукифлщм ыукпун"},
+		},
+		{
+			name:  "name typed in other layout",
+			query: "bdfyjd",
+			want:  []string{"bdfyjd", "бдфыйд", "иванов"},
+		},
+		{
+			name:  "group number",
+			query: "221-352",
+			want:  []string{"221-352"},
+		},
+	}
+
+	searchService := NewSearchService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			terms := searchService.Terms(searchService.Normalize(tt.query))
+			if !reflect.DeepEqual(terms, tt.want) {
+				t.Errorf("SearchService.Terms() = %q, want %q", terms, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchService_Initials(t *testing.T) {
+	tests := []struct {
+		name   string
+		term   string
+		want   models.SearchInitials
+		wantOk bool
+	}{
+		{
+			name:   "surname and initials",
+			term:   "иванов и.п.",
+			want:   models.SearchInitials{SecondName: "иванов", FirstName: "и", MiddleName: "п"},
+			wantOk: true,
+		},
+		{
+			name:   "surname and initials with space",
+			term:   "иванов и. п",
+			want:   models.SearchInitials{SecondName: "иванов", FirstName: "и", MiddleName: "п"},
+			wantOk: true,
+		},
+		{
+			name:   "surname and one initial",
+			term:   "иванов и",
+			want:   models.SearchInitials{SecondName: "иванов", FirstName: "и"},
+			wantOk: true,
+		},
+		{
+			name:   "initials before surname",
+			term:   "и.п. римский-корсаков",
+			want:   models.SearchInitials{SecondName: "римский-корсаков", FirstName: "и", MiddleName: "п"},
+			wantOk: true,
+		},
+		{
+			name:   "full name",
+			term:   "иванов иван",
+			wantOk: false,
+		},
+		{
+			name:   "initials without dots",
+			term:   "иванов ип",
+			wantOk: false,
+		},
+	}
+
+	searchService := NewSearchService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			initials, ok := searchService.Initials(tt.term)
+			if ok != tt.wantOk || initials != tt.want {
+				t.Errorf("SearchService.Initials() = %+v, %v, want %+v, %v", initials, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
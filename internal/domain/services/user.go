@@ -1,11 +1,18 @@
 package services
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"raspyx/config"
 	"raspyx/internal/domain/models"
+	"strings"
 	"time"
 )
 
@@ -19,6 +26,11 @@ var (
 	InvalidPassword = errors.New("password is longer than 72 bytes")
 )
 
+const (
+	defaultAccessTTL  = 15 * time.Minute
+	defaultRefreshTTL = 30 * 24 * time.Hour
+)
+
 func (s *UserService) GeneratePasswordHash(password string) (string, error) {
 	if len([]byte(password)) > 72 {
 		return "", InvalidPassword
@@ -36,16 +48,98 @@ func (s *UserService) CheckPassword(password, hash string) bool {
 	return err == nil
 }
 
+// Validate checks user has role, existence of role is checked by db
 func (s *UserService) Validate(user *models.User) bool {
-	return user.AccessLevel >= 0 && user.AccessLevel < 100
+	return user.Role != ""
 }
 
-func (s *UserService) CreateJWT(username string, accessLevel int, JWT config.JWT) (string, error) {
+// CreateJWT returns access token of user with permissions of user role, jti identifies token in denylist
+func (s *UserService) CreateJWT(user *models.User, permissions []string, jti uuid.UUID, JWT config.JWT) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
-		"sub":          username,
-		"access_level": accessLevel,
-		"exp":          time.Now().Add(time.Hour * 24).Unix(),
+		"sub":         user.Username,
+		"uid":         user.UUID.String(),
+		"role":        user.Role,
+		"permissions": permissions,
+		"jti":         jti.String(),
+		"iat":         now.Unix(),
+		"exp":         now.Add(s.AccessTTL(JWT)).Unix(),
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(JWT.JWTSecret))
 }
+
+func (s *UserService) AccessTTL(JWT config.JWT) time.Duration {
+	if JWT.AccessTTL <= 0 {
+		return defaultAccessTTL
+	}
+	return time.Duration(JWT.AccessTTL) * time.Minute
+}
+
+func (s *UserService) RefreshTTL(JWT config.JWT) time.Duration {
+	if JWT.RefreshTTL <= 0 {
+		return defaultRefreshTTL
+	}
+	return time.Duration(JWT.RefreshTTL) * time.Hour
+}
+
+// GenerateRefreshToken returns opaque refresh token, only its hash is stored in db
+func (s *UserService) GenerateRefreshToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func (s *UserService) HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// roleRank orders roles by their permissions
+var roleRank = map[string]int{
+	models.RoleViewer:    0,
+	models.RoleEditor:    1,
+	models.RoleModerator: 2,
+	models.RoleAdmin:     3,
+}
+
+// ParseRoleMapping parses comma separated "<claim value>=<role>" pairs, roles must be known
+func (s *UserService) ParseRoleMapping(mapping string) (map[string]string, error) {
+	roles := make(map[string]string)
+	for _, pair := range strings.Split(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		value, role, ok := strings.Cut(pair, "=")
+		value, role = strings.TrimSpace(value), strings.TrimSpace(role)
+		if _, known := roleRank[role]; !ok || !known || value == "" {
+			return nil, fmt.Errorf("invalid role mapping %q", pair)
+		}
+		roles[value] = role
+	}
+
+	return roles, nil
+}
+
+// MapRole returns the highest role mapped from groups of user, viewer if none of them is mapped
+func (s *UserService) MapRole(groups []string, mapping map[string]string) string {
+	role := models.RoleViewer
+	for _, group := range groups {
+		if mapped, ok := mapping[group]; ok && roleRank[mapped] > roleRank[role] {
+			role = mapped
+		}
+	}
+	return role
+}
+
+// GenerateState returns random value for state and nonce of OIDC login
+func (s *UserService) GenerateState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
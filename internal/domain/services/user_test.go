@@ -2,8 +2,12 @@ package services
 
 import (
 	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"raspyx/config"
 	"raspyx/internal/domain/models"
 	"testing"
+	"time"
 )
 
 func TestUserService_GeneratePasswordHash(t *testing.T) {
@@ -109,38 +113,23 @@ func TestUserService_Validate(t *testing.T) {
 		want bool
 	}{
 		{
-			"valid user AccessLevel admin",
-			&models.User{AccessLevel: 99},
+			"valid user admin",
+			&models.User{Role: models.RoleAdmin},
 			true,
 		},
 		{
-			"valid user AccessLevel moderator",
-			&models.User{AccessLevel: 50},
+			"valid user moderator",
+			&models.User{Role: models.RoleModerator},
 			true,
 		},
 		{
-			"valid user AccessLevel user",
-			&models.User{AccessLevel: 0},
+			"valid user viewer",
+			&models.User{Role: models.RoleViewer},
 			true,
 		},
 		{
-			"invalid user AccessLevel < 0",
-			&models.User{AccessLevel: -1},
-			false,
-		},
-		{
-			"invalid user AccessLevel < 0",
-			&models.User{AccessLevel: -2},
-			false,
-		},
-		{
-			"invalid AccessLevel",
-			&models.User{AccessLevel: 100},
-			false,
-		},
-		{
-			"invalid AccessLevel",
-			&models.User{AccessLevel: 101},
+			"invalid user without role",
+			&models.User{},
 			false,
 		},
 	}
@@ -156,3 +145,87 @@ func TestUserService_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestUserService_CreateJWT(t *testing.T) {
+	cfg := config.JWT{JWTSecret: "secret", AccessTTL: 15}
+	user := &models.User{UUID: uuid.New(), Username: "username", Role: models.RoleEditor}
+	jti := uuid.New()
+
+	userService := NewUserService()
+
+	tokenStr, err := userService.CreateJWT(user, []string{models.PermScheduleWrite}, jti, cfg)
+	if err != nil {
+		t.Fatalf("UserService.CreateJWT() err = %v", err)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(cfg.JWTSecret), nil
+	})
+	if err != nil {
+		t.Fatalf("parsing token err = %v", err)
+	}
+
+	if claims["jti"] != jti.String() || claims["uid"] != user.UUID.String() || claims["role"] != models.RoleEditor {
+		t.Errorf("UserService.CreateJWT() claims = %v", claims)
+	}
+	if permissions, _ := claims["permissions"].([]any); len(permissions) != 1 || permissions[0] != models.PermScheduleWrite {
+		t.Errorf("UserService.CreateJWT() permissions = %v", claims["permissions"])
+	}
+	exp, _ := claims.GetExpirationTime()
+	if ttl := time.Until(exp.Time); ttl <= 14*time.Minute || ttl > 15*time.Minute {
+		t.Errorf("UserService.CreateJWT() token expires in %v, want 15m", ttl)
+	}
+}
+
+func TestUserService_RefreshToken(t *testing.T) {
+	userService := NewUserService()
+
+	first, err := userService.GenerateRefreshToken()
+	if err != nil {
+		t.Fatalf("UserService.GenerateRefreshToken() err = %v", err)
+	}
+	second, _ := userService.GenerateRefreshToken()
+	if first == second {
+		t.Errorf("UserService.GenerateRefreshToken() returned the same token twice")
+	}
+
+	if userService.HashRefreshToken(first) != userService.HashRefreshToken(first) ||
+		userService.HashRefreshToken(first) == userService.HashRefreshToken(second) {
+		t.Errorf("UserService.HashRefreshToken() is not deterministic or collides")
+	}
+}
+
+func TestUserService_MapRole(t *testing.T) {
+	userService := NewUserService()
+
+	mapping, err := userService.ParseRoleMapping(" staff=editor, dispatchers = moderator,")
+	if err != nil {
+		t.Fatalf("UserService.ParseRoleMapping() err = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		groups []string
+		want   string
+	}{
+		{"without groups", nil, models.RoleViewer},
+		{"unmapped group", []string{"students"}, models.RoleViewer},
+		{"mapped group", []string{"students", "staff"}, models.RoleEditor},
+		{"highest role wins", []string{"dispatchers", "staff"}, models.RoleModerator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := userService.MapRole(tt.groups, mapping); got != tt.want {
+				t.Errorf("UserService.MapRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	for _, invalid := range []string{"staff", "staff=root", "=editor"} {
+		if _, err := userService.ParseRoleMapping(invalid); err == nil {
+			t.Errorf("UserService.ParseRoleMapping(%q) err = nil", invalid)
+		}
+	}
+}
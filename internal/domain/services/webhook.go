@@ -0,0 +1,133 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/netip"
+	"net/url"
+	"raspyx/internal/domain/models"
+	"strings"
+	"time"
+)
+
+const (
+	webhookMinSecretLen = 16
+	webhookBaseBackoff  = 30 * time.Second
+	webhookMaxBackoff   = time.Hour
+)
+
+type WebhookService struct{}
+
+func NewWebhookService() *WebhookService {
+	return &WebhookService{}
+}
+
+func (s *WebhookService) Validate(webhook *models.Webhook) bool {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return false
+	}
+	// Internal addresses are refused early, hostnames are checked again by dispatcher after resolving
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip := net.ParseIP(host); ip != nil && !s.PublicIP(ip) {
+		return false
+	}
+	if len(webhook.Secret) < webhookMinSecretLen {
+		return false
+	}
+	switch webhook.Kind {
+	case models.ResourceGroup, models.ResourceTeacher, models.ResourceRoom:
+	default:
+		return false
+	}
+
+	return webhook.Resource != ""
+}
+
+// webhookDeniedPrefixes are special purpose ranges that are not covered by checks of net.IP
+var webhookDeniedPrefixes = []netip.Prefix{
+	// "This network"
+	netip.MustParsePrefix("0.0.0.0/8"),
+	// Shared address space of carrier-grade NAT, also used by cloud and k8s overlay networks
+	netip.MustParsePrefix("100.64.0.0/10"),
+	// IETF protocol assignments
+	netip.MustParsePrefix("192.0.0.0/24"),
+	// Benchmarking
+	netip.MustParsePrefix("198.18.0.0/15"),
+	// Reserved and broadcast
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// PublicIP tells if webhook can be sent to ip, loopback, private, link-local, unspecified
+// and other special purpose addresses of internal services and cloud metadata are not allowed
+func (s *WebhookService) PublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range webhookDeniedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Sign returns value of signature header for payload: hex encoded HMAC-SHA256 of body with webhook secret
+func (s *WebhookService) Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff returns delay before next delivery after given number of failed attempts, doubling from 30s up to an hour
+func (s *WebhookService) Backoff(attempts int) time.Duration {
+	delay := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+
+	return delay
+}
+
+// Resources returns groups, teachers and rooms of pair before and after change
+func (s *WebhookService) Resources(change *models.ScheduleChange) (groups, teachers, rooms []string) {
+	seen := make(map[[2]string]bool)
+	add := func(list *[]string, kind, resource string) {
+		if resource == "" || seen[[2]string{kind, resource}] {
+			return
+		}
+		seen[[2]string{kind, resource}] = true
+		*list = append(*list, resource)
+	}
+
+	for _, schedule := range []*models.ScheduleData{change.Before, change.After} {
+		if schedule == nil {
+			continue
+		}
+		add(&groups, models.ResourceGroup, schedule.Group)
+		for _, teacher := range schedule.Teachers {
+			add(&teachers, models.ResourceTeacher, teacher)
+		}
+		for _, room := range schedule.Rooms {
+			add(&rooms, models.ResourceRoom, room)
+		}
+	}
+
+	return groups, teachers, rooms
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"raspyx/internal/domain/models"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWebhookService_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		webhook *models.Webhook
+		want    bool
+	}{
+		{"valid group webhook", &models.Webhook{
+			URL: "https://example.com/hook", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, true},
+		{"valid http url", &models.Webhook{
+			URL: "http://example.com:8081", Secret: "0123456789abcdef", Kind: models.ResourceRoom, Resource: "ав4805",
+		}, true},
+		{"localhost", &models.Webhook{
+			URL: "http://localhost:8081", Secret: "0123456789abcdef", Kind: models.ResourceRoom, Resource: "ав4805",
+		}, false},
+		{"private address", &models.Webhook{
+			URL: "http://10.0.0.5:5432", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"cloud metadata address", &models.Webhook{
+			URL: "http://169.254.169.254/latest/meta-data", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"loopback ipv6 address", &models.Webhook{
+			URL: "http://[::1]:6379", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"carrier-grade nat address", &models.Webhook{
+			URL: "http://100.64.0.10", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"this network address", &models.Webhook{
+			URL: "http://0.1.2.3", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"benchmarking address", &models.Webhook{
+			URL: "http://198.19.0.1", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"ietf protocol assignment address", &models.Webhook{
+			URL: "http://192.0.0.170", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"ipv4-mapped carrier-grade nat address", &models.Webhook{
+			URL: "http://[::ffff:100.64.0.10]", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"public address", &models.Webhook{
+			URL: "http://100.128.0.1", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, true},
+		{"unsupported scheme", &models.Webhook{
+			URL: "ftp://example.com", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"url without host", &models.Webhook{
+			URL: "https://", Secret: "0123456789abcdef", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"short secret", &models.Webhook{
+			URL: "https://example.com/hook", Secret: "secret", Kind: models.ResourceGroup, Resource: "221-352",
+		}, false},
+		{"unknown kind", &models.Webhook{
+			URL: "https://example.com/hook", Secret: "0123456789abcdef", Kind: "subject", Resource: "Физика",
+		}, false},
+		{"empty resource", &models.Webhook{
+			URL: "https://example.com/hook", Secret: "0123456789abcdef", Kind: models.ResourceTeacher,
+		}, false},
+	}
+
+	webhookService := NewWebhookService()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhookService.Validate(tt.webhook); got != tt.want {
+				t.Errorf("WebhookService.Validate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookService_Sign(t *testing.T) {
+	webhookService := NewWebhookService()
+
+	// echo -n '{"event":"schedule.changed"}' | openssl dgst -sha256 -hmac 0123456789abcdef
+	want := "sha256=cb04c144ad32135e405752a0429f52e9b5ef66f4dace41dcb7bab21cd817b20c"
+	if got := webhookService.Sign("0123456789abcdef", []byte(`{"event":"schedule.changed"}`)); got != want {
+		t.Errorf("WebhookService.Sign() = %v, want %v", got, want)
+	}
+}
+
+func TestWebhookService_Backoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+
+	webhookService := NewWebhookService()
+
+	for _, tt := range tests {
+		if got := webhookService.Backoff(tt.attempts); got != tt.want {
+			t.Errorf("WebhookService.Backoff(%v) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookService_Resources(t *testing.T) {
+	change := &models.ScheduleChange{
+		Before: &models.ScheduleData{Group: "221-352", Teachers: []string{"Иванов Иван"}, Rooms: []string{"ав4805"}},
+		After:  &models.ScheduleData{Group: "221-352", Teachers: []string{"Петров Пётр"}, Rooms: []string{"ав4805", "ав4810"}},
+	}
+
+	groups, teachers, rooms := NewWebhookService().Resources(change)
+	if want := []string{"221-352"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("WebhookService.Resources() groups = %v, want %v", groups, want)
+	}
+	if want := []string{"Иванов Иван", "Петров Пётр"}; !reflect.DeepEqual(teachers, want) {
+		t.Errorf("WebhookService.Resources() teachers = %v, want %v", teachers, want)
+	}
+	if want := []string{"ав4805", "ав4810"}; !reflect.DeepEqual(rooms, want) {
+		t.Errorf("WebhookService.Resources() rooms = %v, want %v", rooms, want)
+	}
+}
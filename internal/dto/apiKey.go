@@ -0,0 +1,21 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" example:"portal" binding:"required"`
+	Scopes []string `json:"scopes" example:"schedule:read" binding:"required"`
+	// Days till key expires, key without expiry is valid until revoked
+	ExpiresIn int `json:"expires_in" example:"365"`
+}
+
+// CreateAPIKeyResponse holds secret of key, it is shown only once
+type CreateAPIKeyResponse struct {
+	UUID      uuid.UUID  `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Key       string     `json:"key" example:"rpx_3fA9kQ2bX1vN7mZ8cL5tR0wY4eU6iO9pA2sD3fG4hJ5"`
+	Prefix    string     `json:"prefix" example:"rpx_3fA9kQ2b"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2026-03-12T09:00:00Z"`
+}
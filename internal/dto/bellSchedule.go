@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// BellScheduleRequest describes one pair slot, empty location and dates make bell default
+type BellScheduleRequest struct {
+	Location  string `json:"location,omitempty" example:"Автозаводская"`
+	StartDate string `json:"start_date,omitempty" example:"2025-02-01"`
+	EndDate   string `json:"end_date,omitempty" example:"2025-06-30"`
+	PairNum   int    `json:"pair_num" example:"1" binding:"required"`
+	StartTime string `json:"start_time" example:"09:00:00" binding:"required"`
+	EndTime   string `json:"end_time" example:"10:30:00" binding:"required"`
+}
+
+type CreateBellScheduleResponse struct {
+	UUID uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+}
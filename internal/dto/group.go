@@ -18,7 +18,8 @@ type GetGroupResponse struct {
 }
 
 type GetGroupsResponse struct {
-	Groups []models.Group `json:"groups" binding:"required"`
+	Groups     []*models.Group `json:"groups" binding:"required"`
+	Pagination Pagination      `json:"pagination"`
 }
 
 type UpdateGroupRequest struct {
@@ -0,0 +1,20 @@
+package dto
+
+// ListParams are query parameters of list routes
+type ListParams struct {
+	// Items per page
+	Limit string
+	// next_cursor of previous page
+	Cursor string
+	// Field to sort by, "-" prefix sorts descending
+	Sort string
+	// Filters like "number~=221-" or "weekday>=3"
+	Filters []string
+}
+
+type Pagination struct {
+	Limit int `json:"limit" example:"50"`
+	// Cursor of next page, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoibnVtYmVyIiwidiI6IjIyMS0zNTIiLCJ1IjoiYzU1NWI5ZTgtMGQ3YS0xMWYwLWFkY2QtMjAxMTRkMjAwOGQ5In0"`
+	HasMore    bool   `json:"has_more" example:"true"`
+}
@@ -14,7 +14,8 @@ type CreateLocationResponse struct {
 }
 
 type GetLocationsResponse struct {
-	Locations []models.Location `json:"locations" binding:"required"`
+	Locations  []*models.Location `json:"locations" binding:"required"`
+	Pagination Pagination         `json:"pagination"`
 }
 
 type UpdateLocationRequest struct {
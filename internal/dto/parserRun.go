@@ -0,0 +1,12 @@
+package dto
+
+import "github.com/google/uuid"
+
+type ParserRunRequest struct {
+	// Group to parse, every group is parsed if empty
+	Group string `json:"group" example:"221-352"`
+}
+
+type ParserRunResponse struct {
+	UUID uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+}
@@ -14,9 +14,20 @@ type CreateRoomResponse struct {
 }
 
 type GetRoomsResponse struct {
-	Rooms []models.Room `json:"rooms" binding:"required"`
+	Rooms      []*models.Room `json:"rooms" binding:"required"`
+	Pagination Pagination     `json:"pagination"`
 }
 
 type UpdateRoomRequest struct {
 	Number string `json:"number" example:"ав4805" binding:"required"`
 }
+
+// FreeRoomsRequest is slot to search free rooms in, either pair number or time range must be given
+type FreeRoomsRequest struct {
+	Location string `json:"location" example:"Автозаводская"`
+	Date     string `json:"date" example:"2025-03-12"`
+	Pair     string `json:"pair" example:"3"`
+	From     string `json:"from" example:"12:20"`
+	To       string `json:"to" example:"13:50"`
+	Prefix   string `json:"prefix" example:"ав48"`
+}
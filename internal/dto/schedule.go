@@ -2,6 +2,7 @@ package dto
 
 import (
 	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
 	"time"
 )
 
@@ -22,7 +23,12 @@ type ScheduleRequest struct {
 }
 
 type CreateScheduleResponse struct {
-	UUID uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	UUID      uuid.UUID                  `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Conflicts []*models.ScheduleConflict `json:"conflicts,omitempty"`
+}
+
+type ScheduleConflictsResponse struct {
+	Conflicts []*models.ScheduleConflict `json:"conflicts"`
 }
 
 // DeletePairsByGroupWeekdayTimeRequest
@@ -33,8 +39,20 @@ type DeletePBGWTRequest struct {
 	StartDate time.Time `json:"start_date" example:"2025-02-01"`
 }
 
+// DateFilter limits schedule to pairs that take place on given date or ISO week
+type DateFilter struct {
+	Date string `json:"date" example:"2025-03-12"`
+	Week string `json:"week" example:"2025-W11"`
+}
+
 type Week map[string]*Day
 
+// GetSchedulesResponse is page of pairs grouped by days
+type GetSchedulesResponse struct {
+	Week       *Week      `json:"week"`
+	Pagination Pagination `json:"pagination"`
+}
+
 //type Week struct {
 //	Monday    Day `json:"monday"`
 //	Tuesday   Day `json:"tuesday"`
@@ -52,18 +70,23 @@ type Day struct {
 	Fifth   []Pair `json:"5"`
 	Sixth   []Pair `json:"6"`
 	Seventh []Pair `json:"7"`
+	// Pairs that do not start on any bell
+	Other []Pair `json:"other,omitempty"`
 }
 
 type Pair struct {
 	Group     string   `json:"group,omitempty" example:"221-352"`
 	Subject   string   `json:"subject" example:"Иностранный язык"`
 	Teachers  []string `json:"teachers" example:"Фамилия Имя Отчество,Фамилия Имя"`
+	StartTime string   `json:"start_time" example:"09:00:00"`
+	EndTime   string   `json:"end_time" example:"10:30:00"`
 	StartDate string   `json:"start_date" example:"2025-02-01"`
 	EndDate   string   `json:"end_date" example:"2025-06-01"`
 	Rooms     []string `json:"rooms,omitempty" example:"ав4805,ав4810"`
 	Location  string   `json:"location" example:"Автозаводская"`
 	Type      string   `json:"type" example:"Практика"`
 	Link      string   `json:"link,omitempty" example:"https://online.mospolytech.ru/"`
+	Date      string   `json:"date,omitempty" example:"2025-03-12"`
 }
 
 type DeleteParams struct {
@@ -78,3 +101,7 @@ type DeleteParams struct {
 	Day       string
 	IsSession bool
 }
+
+type ScheduleChangesResponse struct {
+	Changes []*models.ScheduleChange `json:"changes"`
+}
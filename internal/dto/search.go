@@ -0,0 +1,16 @@
+package dto
+
+import "raspyx/internal/domain/models"
+
+// SearchParams are query parameters of search route
+type SearchParams struct {
+	// Text typed by user, it may be transliterated, mistyped or incomplete
+	Query string
+	// Types of entities to search, every type if empty
+	Types []string
+	Limit string
+}
+
+type SearchResponse struct {
+	Hits []*models.SearchHit `json:"hits" binding:"required"`
+}
@@ -14,7 +14,8 @@ type CreateSubjectResponse struct {
 }
 
 type GetSubjectsResponse struct {
-	Subjects []models.Subject `json:"subjects" binding:"required"`
+	Subjects   []*models.Subject `json:"subjects" binding:"required"`
+	Pagination Pagination        `json:"pagination"`
 }
 
 type UpdateSubjectRequest struct {
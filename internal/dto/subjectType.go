@@ -14,7 +14,8 @@ type CreateSubjectTypeResponse struct {
 }
 
 type GetSubjectTypesResponse struct {
-	SubjectTypes []models.SubjectType `json:"subjectTypes" binding:"required"`
+	SubjectTypes []*models.SubjectType `json:"subjectTypes" binding:"required"`
+	Pagination   Pagination            `json:"pagination"`
 }
 
 type UpdateSubjectTypeRequest struct {
@@ -19,7 +19,8 @@ type TeacherDTO struct {
 }
 
 type GetTeachersResponse struct {
-	Teachers []TeacherDTO `json:"teachers" binding:"required"`
+	Teachers   []*TeacherDTO `json:"teachers" binding:"required"`
+	Pagination Pagination    `json:"pagination"`
 }
 
 type UpdateTeacherRequest struct {
@@ -27,3 +28,27 @@ type UpdateTeacherRequest struct {
 	SecondName string `json:"second_name" example:"Фамилия" binding:"required"`
 	MiddleName string `json:"middle_name" example:"Отчество"`
 }
+
+type FreeSlot struct {
+	PairNum   int    `json:"pair_num" example:"3"`
+	StartTime string `json:"start_time" example:"12:20:00"`
+	EndTime   string `json:"end_time" example:"13:50:00"`
+}
+
+// LocationAvailability is free slots by bells of location that differ from default ones
+type LocationAvailability struct {
+	Location string     `json:"location" example:"Автозаводская"`
+	Free     []FreeSlot `json:"free"`
+}
+
+// DayAvailability is free slots by default bells and by bells of locations that have own ones on date
+type DayAvailability struct {
+	Date      string                 `json:"date" example:"2025-03-12"`
+	Free      []FreeSlot             `json:"free"`
+	Locations []LocationAvailability `json:"locations,omitempty"`
+}
+
+type AvailabilityResponse struct {
+	Teachers []string          `json:"teachers" example:"Фамилия Имя Отчество"`
+	Days     []DayAvailability `json:"days"`
+}
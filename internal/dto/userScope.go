@@ -0,0 +1,8 @@
+package dto
+
+// UserScopes are schedules user can edit, user without scopes edits every schedule
+type UserScopes struct {
+	Groups        []string `json:"groups" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Locations     []string `json:"locations" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	GroupPrefixes []string `json:"group_prefixes" example:"221-"`
+}
@@ -0,0 +1,26 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+)
+
+type WebhookRequest struct {
+	URL    string `json:"url" example:"https://example.com/raspyx" binding:"required"`
+	Secret string `json:"secret" example:"0123456789abcdef" binding:"required"`
+	Kind   string `json:"kind" example:"group" binding:"required"`
+	// Group number, teacher uuid or room number
+	Resource string `json:"resource" example:"221-352" binding:"required"`
+}
+
+type CreateWebhookResponse struct {
+	UUID uuid.UUID `json:"uuid" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+}
+
+// WebhookPayload is body of request sent to webhook, signed with its secret in X-Raspyx-Signature header
+type WebhookPayload struct {
+	Event    string                 `json:"event" example:"schedule.changed"`
+	Delivery uuid.UUID              `json:"delivery" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Webhook  uuid.UUID              `json:"webhook" example:"c555b9e8-0d7a-11f0-adcd-20114d2008d9"`
+	Change   *models.ScheduleChange `json:"change"`
+}
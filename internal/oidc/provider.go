@@ -0,0 +1,119 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"raspyx/config"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+var ErrInvalidToken = errors.New("invalid id token")
+
+// Provider is OpenID Connect provider of university SSO, it implements interfaces.OIDCProvider
+type Provider struct {
+	verifier      *oidc.IDTokenVerifier
+	oauth         oauth2.Config
+	usernameClaim string
+	roleClaim     string
+}
+
+// NewProvider discovers endpoints and keys of issuer
+func NewProvider(ctx context.Context, cfg config.OIDC) (*Provider, error) {
+	const op = "oidc.NewProvider"
+
+	provider, err := oidc.NewProvider(ctx, cfg.Issuer)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Provider{
+		verifier: provider.Verifier(&oidc.Config{ClientID: cfg.ClientID}),
+		oauth: oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Endpoint:     provider.Endpoint(),
+			Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+		},
+		usernameClaim: cfg.UsernameClaim,
+		roleClaim:     cfg.RoleClaim,
+	}, nil
+}
+
+func (p *Provider) AuthCodeURL(state, nonce string) string {
+	return p.oauth.AuthCodeURL(state, oidc.Nonce(nonce))
+}
+
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*models.OIDCClaims, error) {
+	const op = "oidc.Provider.Exchange"
+
+	token, err := p.oauth.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
+
+	// Signature, issuer, audience and expiry are checked by verifier
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, ErrInvalidToken, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("%s: %w: nonce mismatch", op, ErrInvalidToken)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	username := stringClaim(claims, p.usernameClaim)
+	if username == "" {
+		username = stringClaim(claims, "email")
+	}
+
+	return &models.OIDCClaims{
+		Issuer:   idToken.Issuer,
+		Subject:  idToken.Subject,
+		Username: username,
+		Groups:   listClaim(claims, p.roleClaim),
+	}, nil
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	if name == "" {
+		return ""
+	}
+	value, _ := claims[name].(string)
+	return strings.TrimSpace(value)
+}
+
+// listClaim returns values of claim which providers send either as string or as list of strings
+func listClaim(claims map[string]any, name string) []string {
+	if name == "" {
+		return nil
+	}
+
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
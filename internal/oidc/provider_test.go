@@ -0,0 +1,188 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/golang-jwt/jwt/v5"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"raspyx/config"
+	"testing"
+	"time"
+)
+
+// mockProvider is local OpenID Connect provider issuing id tokens for any code
+type mockProvider struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// Claims of next id token, issuer, audience and expiry are added to them
+	claims jwt.MapClaims
+}
+
+func newMockProvider(t *testing.T) *mockProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &mockProvider{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{
+			"issuer":                                p.server.URL,
+			"authorization_endpoint":                p.server.URL + "/authorize",
+			"token_endpoint":                        p.server.URL + "/token",
+			"jwks_uri":                              p.server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss": p.server.URL,
+			"aud": "raspyx",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(time.Minute).Unix(),
+		}
+		for k, v := range p.claims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, map[string]any{
+			"access_token": "access",
+			"token_type":   "Bearer",
+			"expires_in":   60,
+			"id_token":     idToken,
+		})
+	})
+
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestProvider(t *testing.T, mock *mockProvider) *Provider {
+	provider, err := NewProvider(context.Background(), config.OIDC{
+		Issuer:        mock.server.URL,
+		ClientID:      "raspyx",
+		ClientSecret:  "secret",
+		RedirectURL:   "http://localhost:8080/raspyx/api/v1/users/oidc/callback",
+		UsernameClaim: "preferred_username",
+		RoleClaim:     "groups",
+	})
+	if err != nil {
+		t.Fatalf("NewProvider() error = %v", err)
+	}
+	return provider
+}
+
+func TestProvider_AuthCodeURL(t *testing.T) {
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	u, err := url.Parse(provider.AuthCodeURL("state", "nonce"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if u.Path != "/authorize" || q.Get("state") != "state" || q.Get("nonce") != "nonce" || q.Get("client_id") != "raspyx" {
+		t.Errorf("AuthCodeURL() = %v", u)
+	}
+}
+
+func TestProvider_Exchange(t *testing.T) {
+	tests := []struct {
+		name         string
+		claims       jwt.MapClaims
+		wantUsername string
+		wantGroups   []string
+		wantErr      bool
+	}{
+		{
+			name:         "groups list",
+			claims:       jwt.MapClaims{"sub": "1", "nonce": "nonce", "preferred_username": "ivanov", "groups": []string{"students", "staff"}},
+			wantUsername: "ivanov",
+			wantGroups:   []string{"students", "staff"},
+		},
+		{
+			name:         "single group and email",
+			claims:       jwt.MapClaims{"sub": "2", "nonce": "nonce", "email": "petrov@example.com", "groups": "staff"},
+			wantUsername: "petrov@example.com",
+			wantGroups:   []string{"staff"},
+		},
+		{
+			name:    "wrong nonce",
+			claims:  jwt.MapClaims{"sub": "3", "nonce": "other", "preferred_username": "sidorov"},
+			wantErr: true,
+		},
+		{
+			name:    "wrong audience",
+			claims:  jwt.MapClaims{"sub": "4", "nonce": "nonce", "aud": "other"},
+			wantErr: true,
+		},
+	}
+
+	mock := newMockProvider(t)
+	provider := newTestProvider(t, mock)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.claims = tt.claims
+
+			claims, err := provider.Exchange(context.Background(), "code", "nonce")
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Exchange() error = %v, want %v", err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Exchange() error = %v", err)
+			}
+
+			if claims.Issuer != mock.server.URL || claims.Subject != tt.claims["sub"] || claims.Username != tt.wantUsername {
+				t.Errorf("Exchange() = %+v", claims)
+			}
+			if len(claims.Groups) != len(tt.wantGroups) {
+				t.Fatalf("Exchange() groups = %v, want %v", claims.Groups, tt.wantGroups)
+			}
+			for i := range tt.wantGroups {
+				if claims.Groups[i] != tt.wantGroups[i] {
+					t.Errorf("Exchange() groups = %v, want %v", claims.Groups, tt.wantGroups)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"raspyx/internal/domain/models"
+	"strconv"
+	"time"
+)
+
+var (
+	upstreamRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parser_upstream_requests_total",
+			Help: "Total number of parser requests to upstream by status code, error if request failed",
+		},
+		[]string{"status"},
+	)
+
+	upstreamRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "parser_upstream_request_duration_seconds",
+			Help:    "Duration of parser requests to upstream",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2, 3, 5, 10, 20},
+		},
+		[]string{"status"},
+	)
+
+	runsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parser_runs_total",
+			Help: "Total number of parser runs by trigger and status",
+		},
+		[]string{"trigger", "status"},
+	)
+
+	runDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "parser_run_duration_seconds",
+			Help:    "Duration of parser runs",
+			Buckets: []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+		},
+		[]string{"trigger"},
+	)
+
+	groupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parser_groups_total",
+			Help: "Total number of groups processed by parser, failed if any of their pairs was not synced",
+		},
+		[]string{"result"},
+	)
+
+	timetablesSkippedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "parser_timetables_skipped_total",
+			Help: "Total number of timetables skipped by parser because they did not change",
+		},
+	)
+
+	entitiesAddedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parser_entities_added_total",
+			Help: "Total number of entities added to db by parser",
+		},
+		[]string{"entity"},
+	)
+
+	scheduleRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "parser_schedule_rows_total",
+			Help: "Total number of schedule rows inserted or deleted by parser",
+		},
+		[]string{"action"},
+	)
+
+	lastSuccess = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "parser_last_success_timestamp_seconds",
+			Help: "Unix time of the last successful parser run",
+		},
+	)
+
+	interval = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "parser_interval_seconds",
+			Help: "Configured interval between scheduled parser runs",
+		},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(upstreamRequestsTotal)
+	prometheus.MustRegister(upstreamRequestDuration)
+	prometheus.MustRegister(runsTotal)
+	prometheus.MustRegister(runDuration)
+	prometheus.MustRegister(groupsTotal)
+	prometheus.MustRegister(timetablesSkippedTotal)
+	prometheus.MustRegister(entitiesAddedTotal)
+	prometheus.MustRegister(scheduleRowsTotal)
+	prometheus.MustRegister(lastSuccess)
+	prometheus.MustRegister(interval)
+}
+
+// observeUpstream records request to upstream, code is 0 if request failed without response
+func observeUpstream(start time.Time, code int) {
+	status := "error"
+	if code != 0 {
+		status = strconv.Itoa(code)
+	}
+
+	upstreamRequestsTotal.WithLabelValues(status).Inc()
+	upstreamRequestDuration.WithLabelValues(status).Observe(time.Since(start).Seconds())
+}
+
+// observeRun records finished run, groups maps groups of run to whether they were synced without errors
+func observeRun(run *models.ParserRun, groups map[string]bool) {
+	runsTotal.WithLabelValues(run.Trigger, run.Status).Inc()
+	runDuration.WithLabelValues(run.Trigger).Observe(float64(run.Duration) / 1000)
+
+	for _, ok := range groups {
+		if ok {
+			groupsTotal.WithLabelValues("parsed").Inc()
+		} else {
+			groupsTotal.WithLabelValues("failed").Inc()
+		}
+	}
+	timetablesSkippedTotal.Add(float64(run.Skipped))
+
+	for entity, n := range run.Added {
+		entitiesAddedTotal.WithLabelValues(entity).Add(float64(n))
+	}
+
+	if run.Status == models.RunSuccess && run.FinishedAt != nil {
+		lastSuccess.Set(float64(run.FinishedAt.Unix()))
+	}
+}
@@ -0,0 +1,152 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"raspyx/internal/dto"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kinds of dictionary entities in report
+const (
+	entityGroup    = "group"
+	entitySubject  = "subject"
+	entityTeacher  = "teacher"
+	entityRoom     = "room"
+	entityLocation = "location"
+	entityType     = "type"
+)
+
+// ReportPair is pair that would be inserted or deleted by parser
+type ReportPair struct {
+	Group     string   `json:"group"`
+	Day       string   `json:"day"`
+	PairNum   string   `json:"pair_num"`
+	IsSession bool     `json:"isSession"`
+	Pair      dto.Pair `json:"pair"`
+}
+
+// Report is diff between rasp.dmami.ru and db computed by dry run of parser
+type Report struct {
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Groups     []string      `json:"groups"`
+	Subjects   []string      `json:"subjects"`
+	Teachers   []string      `json:"teachers"`
+	Rooms      []string      `json:"rooms"`
+	Locations  []string      `json:"locations"`
+	Types      []string      `json:"types"`
+	Inserts    []*ReportPair `json:"inserts"`
+	Deletes    []*ReportPair `json:"deletes"`
+	Summary    string        `json:"summary"`
+
+	mu   sync.Mutex
+	seen map[[2]string]bool
+}
+
+func newReport() *Report {
+	return &Report{
+		StartedAt: time.Now(),
+		Groups:    []string{},
+		Subjects:  []string{},
+		Teachers:  []string{},
+		Rooms:     []string{},
+		Locations: []string{},
+		Types:     []string{},
+		Inserts:   []*ReportPair{},
+		Deletes:   []*ReportPair{},
+		seen:      make(map[[2]string]bool),
+	}
+}
+
+// addEntity adds new dictionary entity to report, reports whether it was not added before
+func (r *Report) addEntity(kind, name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.seen[[2]string{kind, name}] {
+		return false
+	}
+	r.seen[[2]string{kind, name}] = true
+
+	switch kind {
+	case entityGroup:
+		r.Groups = append(r.Groups, name)
+	case entitySubject:
+		r.Subjects = append(r.Subjects, name)
+	case entityTeacher:
+		r.Teachers = append(r.Teachers, name)
+	case entityRoom:
+		r.Rooms = append(r.Rooms, name)
+	case entityLocation:
+		r.Locations = append(r.Locations, name)
+	case entityType:
+		r.Types = append(r.Types, name)
+	}
+
+	return true
+}
+
+// replacePairs adds pairs of group on day at pair num that would be deleted and inserted
+func (r *Report) replacePairs(group, day, pairNum string, isSession bool, deleted, inserted []dto.Pair) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, pair := range deleted {
+		r.Deletes = append(r.Deletes, &ReportPair{Group: group, Day: day, PairNum: pairNum, IsSession: isSession, Pair: pair})
+	}
+	for _, pair := range inserted {
+		r.Inserts = append(r.Inserts, &ReportPair{Group: group, Day: day, PairNum: pairNum, IsSession: isSession, Pair: pair})
+	}
+}
+
+// finish sorts report and makes its summary from counters of parser run
+func (r *Report) finish(a *added) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.FinishedAt = time.Now()
+	for _, list := range [][]string{r.Groups, r.Subjects, r.Teachers, r.Rooms, r.Locations, r.Types} {
+		sort.Strings(list)
+	}
+	for _, pairs := range [][]*ReportPair{r.Inserts, r.Deletes} {
+		sort.SliceStable(pairs, func(i, j int) bool {
+			if pairs[i].Group != pairs[j].Group {
+				return pairs[i].Group < pairs[j].Group
+			}
+			if pairs[i].Day != pairs[j].Day {
+				return pairs[i].Day < pairs[j].Day
+			}
+			return pairs[i].PairNum < pairs[j].PairNum
+		})
+	}
+
+	changedGroups := make(map[string]bool)
+	for _, pairs := range [][]*ReportPair{r.Inserts, r.Deletes} {
+		for _, pair := range pairs {
+			changedGroups[pair.Group] = true
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dry run took %v, nothing was written.\n", r.FinishedAt.Sub(r.StartedAt).Round(time.Second))
+	fmt.Fprintf(&b, "Pairs: %v would be inserted, %v deleted in %v groups\n", a.schedule, len(r.Deletes), len(changedGroups))
+	fmt.Fprintf(
+		&b, "New: %v groups, %v subjects, %v teachers, %v rooms, %v locations, %v types",
+		a.groups, a.subjects, a.teachers, a.rooms, a.locations, a.types,
+	)
+	r.Summary = b.String()
+}
+
+// noCache is cache that is always empty, it keeps dry run from touching redis
+type noCache struct{}
+
+var errCacheMiss = errors.New("cache miss")
+
+func (noCache) Set(context.Context, string, string, time.Duration) error { return nil }
+func (noCache) Get(context.Context, string) (string, error)              { return "", errCacheMiss }
+func (noCache) Delete(context.Context, string) error                     { return nil }
@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"raspyx/internal/dto"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReport(t *testing.T) {
+	r := newReport()
+	a := &added{}
+	p := &ScheduleParser{report: r, added: a}
+
+	p.plan(entitySubject, "Физика", &a.subjects)
+	p.plan(entitySubject, "Высшая математика", &a.subjects)
+	p.plan(entitySubject, "Физика", &a.subjects)
+	p.plan(entityRoom, "ав4805", &a.rooms)
+
+	r.replacePairs("221-352", "2", "1", false, []dto.Pair{{Subject: "Физика"}}, []dto.Pair{{Subject: "Физика"}, {Subject: "Химия"}})
+	r.replacePairs("221-351", "1", "3", false, []dto.Pair{{Subject: "Химия"}}, nil)
+	a.schedule += 2
+
+	r.finish(a)
+
+	if want := []string{"Высшая математика", "Физика"}; !reflect.DeepEqual(r.Subjects, want) {
+		t.Errorf("Report.Subjects = %v, want %v", r.Subjects, want)
+	}
+	if a.subjects != 2 || a.rooms != 1 {
+		t.Errorf("added subjects = %v, rooms = %v, want 2 and 1", a.subjects, a.rooms)
+	}
+	if len(r.Inserts) != 2 || len(r.Deletes) != 2 {
+		t.Fatalf("Report has %v inserts and %v deletes, want 2 and 2", len(r.Inserts), len(r.Deletes))
+	}
+	if r.Deletes[0].Group != "221-351" {
+		t.Errorf("Report.Deletes are not sorted by group: %v", r.Deletes[0].Group)
+	}
+	if want := "Pairs: 2 would be inserted, 2 deleted in 2 groups"; !strings.Contains(r.Summary, want) {
+		t.Errorf("Report.Summary = %q, want it to contain %q", r.Summary, want)
+	}
+}
@@ -0,0 +1,158 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/usecase"
+	"time"
+)
+
+// Start starts parser run for given groups (every group if none) in background.
+// It fails with usecase.ErrParserBusy while another run is not finished
+func (p *ScheduleParser) Start(_ context.Context, groups []string) (uuid.UUID, error) {
+	if !p.running.TryLock() {
+		return uuid.Nil, usecase.ErrParserBusy
+	}
+	if p.ctx == nil {
+		p.running.Unlock()
+		return uuid.Nil, usecase.ErrParserNotStarted
+	}
+
+	run, err := p.begin(p.ctx, models.RunTriggerManual, groups)
+	if err != nil {
+		p.running.Unlock()
+		return uuid.Nil, err
+	}
+
+	go func() {
+		defer p.running.Unlock()
+
+		err := p.parse(p.ctx, run)
+		if err != nil {
+			p.log.Error(fmt.Sprintf("error parsing schedule: %v", err))
+		}
+	}()
+
+	return run.UUID, nil
+}
+
+// runScheduled runs parser by ticker, run is skipped if manual one is not finished yet
+func (p *ScheduleParser) runScheduled(ctx context.Context) {
+	if !p.running.TryLock() {
+		p.log.Warn("skipping scheduled run, parser is already running")
+		return
+	}
+	defer p.running.Unlock()
+
+	run, err := p.begin(ctx, models.RunTriggerSchedule, nil)
+	if err != nil {
+		p.log.Error(fmt.Sprintf("error starting parser run: %v", err))
+		return
+	}
+
+	err = p.parse(ctx, run)
+	if err != nil {
+		p.log.Error(fmt.Sprintf("error parsing schedule: %v", err))
+	}
+}
+
+// begin creates run of parser, it is not recorded in dry run
+func (p *ScheduleParser) begin(ctx context.Context, trigger string, groups []string) (*models.ParserRun, error) {
+	runUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.ParserRun{
+		UUID:      runUUID,
+		Trigger:   trigger,
+		Groups:    append([]string{}, groups...),
+		Status:    models.RunRunning,
+		StartedAt: time.Now(),
+	}
+	if p.dryRun {
+		return run, nil
+	}
+
+	err = p.runRepo.Create(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
+// finish records results of run, err is error that stopped it
+func (p *ScheduleParser) finish(ctx context.Context, run *models.ParserRun, err error) {
+	p.added.mu.Lock()
+	now := time.Now()
+	run.FinishedAt = &now
+	run.Duration = now.Sub(run.StartedAt).Milliseconds()
+	run.Skipped = p.added.skipped
+	run.Errors = p.added.errors
+	run.Added = map[string]int{
+		"schedules": p.added.schedule,
+		"groups":    p.added.groups,
+		"subjects":  p.added.subjects,
+		"teachers":  p.added.teachers,
+		"rooms":     p.added.rooms,
+		"locations": p.added.locations,
+		"types":     p.added.types,
+	}
+	// Group is processed when both regular and session schedules are parsed
+	run.GroupsProcessed = 0
+	groups := make(map[string]bool)
+	for group, n := range p.added.parsed {
+		if n == 2 {
+			run.GroupsProcessed++
+		}
+		if len(p.added.errors[group]) != 0 {
+			groups[group] = false
+		} else if n == 2 {
+			groups[group] = true
+		}
+	}
+	p.added.mu.Unlock()
+
+	switch {
+	case err != nil:
+		run.Status, run.Error = models.RunFailed, err.Error()
+	case len(run.Errors) != 0:
+		run.Status = models.RunPartial
+	default:
+		run.Status = models.RunSuccess
+	}
+
+	if p.dryRun {
+		return
+	}
+	observeRun(run, groups)
+
+	// Run is recorded even if it was canceled
+	err = p.runRepo.Update(context.WithoutCancel(ctx), run)
+	if err != nil {
+		p.log.Error(fmt.Sprintf("error saving parser run %v: %v", run.UUID, err))
+	}
+}
+
+// parsed counts parsed timetable of group with error of its parsing
+func (p *ScheduleParser) parsed(group string, err error) {
+	p.added.mu.Lock()
+	defer p.added.mu.Unlock()
+
+	p.added.parsed[group]++
+	if err == nil {
+		return
+	}
+
+	// Every error of pairs is listed separately
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			p.added.errors[group] = append(p.added.errors[group], e.Error())
+		}
+		return
+	}
+	p.added.errors[group] = append(p.added.errors[group], err.Error())
+}
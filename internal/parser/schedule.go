@@ -2,16 +2,15 @@ package parser
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/go-cmp/cmp"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
-	"io"
 	"log/slog"
-	"net/http"
 	"raspyx/config"
 	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
 	"raspyx/internal/domain/services"
 	"raspyx/internal/dto"
 	"raspyx/internal/repository"
@@ -19,19 +18,20 @@ import (
 	myredis "raspyx/internal/repository/redis"
 	"raspyx/internal/usecase"
 	"reflect"
-	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type ScheduleParser struct {
-	client       *http.Client
+	source       ScheduleSource
 	conn         *pgxpool.Pool
 	log          *slog.Logger
 	cfg          config.Parser
+	cacheCfg     config.Cache
 	added        *added
 	groupRepo    *postgres.GroupRepository
 	groupSVC     *services.GroupService
@@ -47,37 +47,31 @@ type ScheduleParser struct {
 	typeSVC      *services.SubjectTypeService
 	scheduleRepo *postgres.ScheduleRepository
 	scheduleSVC  *services.ScheduleService
+	bellRepo     *postgres.BellScheduleRepository
+	bellSVC      *services.BellScheduleService
+	changeRepo   *postgres.ScheduleChangeRepository
+	webhookRepo  *postgres.WebhookRepository
+	deliveryRepo *postgres.WebhookDeliveryRepository
+	scopeRepo    *postgres.UserScopeRepository
+	webhookSVC   *services.WebhookService
 	repoTToS     interfaces.TeachersToScheduleRepository
 	repoRToS     interfaces.RoomsToScheduleRepository
 	cache        interfaces.Cache
-}
-
-type lesson struct {
-	Sbj        string `json:"sbj"`
-	Teacher    string `json:"teacher"`
-	Dts        string `json:"dts"`
-	Df         string `json:"df"`
-	Dt         string `json:"dt"`
-	Auditories []struct {
-		Title string `json:"title"`
-		Color string `json:"color"`
-	} `json:"auditories"`
-	ShortRooms []string `json:"shortRooms"`
-	Location   string   `json:"location"`
-	Type       string   `json:"type"`
-	Week       string   `json:"week"`
-	Align      string   `json:"align"`
-	ELink      any      `json:"e_link"`
-}
-
-type response struct {
-	Status    string                         `json:"status"`
-	Message   string                         `json:"message"`
-	Grid      map[string]map[string][]lesson `json:"grid"`
-	IsSession bool                           `json:"isSession"`
+	txManager    *postgres.TxManager
+	runRepo      *postgres.ParserRunRepository
+	// Held while parser runs, so runs never overlap
+	running sync.Mutex
+	// Locks of dictionary entities by kind and name, workers add each entity once
+	entityLocks sync.Map
+	// Context of parser lifetime, manual runs are started with it
+	ctx context.Context
+	// Dry run computes changes into report instead of writing them
+	dryRun bool
+	report *Report
 }
 
 type added struct {
+	mu        sync.Mutex
 	groups    int
 	subjects  int
 	teachers  int
@@ -85,19 +79,26 @@ type added struct {
 	locations int
 	types     int
 	schedule  int
+	// Timetables that did not change since last sync
+	skipped int
+	// Parsed timetables of group
+	parsed map[string]int
+	// Errors of parsing schedule by group
+	errors map[string][]string
 }
 
-func NewScheduleParser(timeout time.Duration, conn *pgxpool.Pool, redisClient *redis.Client, log *slog.Logger, cfg config.Parser) *ScheduleParser {
+func NewScheduleParser(source ScheduleSource, conn *pgxpool.Pool, redisClient *redis.Client, log *slog.Logger, cfg config.Parser, cacheCfg config.Cache) *ScheduleParser {
 	return &ScheduleParser{
-		client: &http.Client{Timeout: timeout},
-		conn:   conn,
-		log:    log,
-		cache:  myredis.NewRedisCache(redisClient),
-		cfg:    cfg,
+		source:   source,
+		conn:     conn,
+		log:      log,
+		cache:    myredis.NewRedisCache(redisClient),
+		cfg:      cfg,
+		cacheCfg: cacheCfg,
 	}
 }
 
-func (p *ScheduleParser) New(ctx context.Context) {
+func (p *ScheduleParser) init() {
 	p.log = p.log.With(slog.String("module", "ScheduleParser"))
 
 	p.groupRepo = postgres.NewGroupRepository(p.conn)
@@ -120,19 +121,40 @@ func (p *ScheduleParser) New(ctx context.Context) {
 
 	p.scheduleRepo = postgres.NewScheduleRepository(p.conn)
 	p.scheduleSVC = services.NewScheduleService()
+	p.bellRepo = postgres.NewBellScheduleRepository(p.conn)
+	p.bellSVC = services.NewBellScheduleService()
+	p.changeRepo = postgres.NewScheduleChangeRepository(p.conn)
+	p.webhookRepo = postgres.NewWebhookRepository(p.conn)
+	p.deliveryRepo = postgres.NewWebhookDeliveryRepository(p.conn)
+	p.scopeRepo = postgres.NewUserScopeRepository(p.conn)
+	p.webhookSVC = services.NewWebhookService()
 	p.repoTToS = postgres.NewTeachersToScheduleRepository(p.conn)
 	p.repoRToS = postgres.NewRoomsToScheduleRepository(p.conn)
+	p.txManager = postgres.NewTxManager(p.conn)
+	p.runRepo = postgres.NewParserRunRepository(p.conn)
+}
 
-	// Init parsing schedule
-	err := p.parse(ctx)
+func (p *ScheduleParser) New(ctx context.Context) {
+	p.init()
+
+	// Runs of stopped app are never finished
+	err := p.runRepo.FailRunning(ctx, "interrupted by restart")
 	if err != nil {
-		p.log.Error(fmt.Sprintf("error parsing schedule: %v", err))
+		p.log.Error(fmt.Sprintf("error failing interrupted parser runs: %v", err))
 	}
 
+	p.running.Lock()
+	p.ctx = ctx
+	p.running.Unlock()
+
 	// Set timeout to 10 minute if it too small
 	if p.cfg.Timeout < 1 {
 		p.cfg.Timeout = 10
 	}
+	interval.Set(float64(p.cfg.Timeout * 60))
+
+	// Init parsing schedule
+	p.runScheduled(ctx)
 
 	// Ticker for parsing schedule
 	ticker := time.NewTicker(time.Duration(p.cfg.Timeout) * time.Minute)
@@ -145,10 +167,7 @@ func (p *ScheduleParser) New(ctx context.Context) {
 				p.log.Error(fmt.Sprintf("cancel schedule parser"))
 				return
 			case <-ticker.C:
-				err := p.parse(ctx)
-				if err != nil {
-					p.log.Error(fmt.Sprintf("error parsing schedule: %v", err))
-				}
+				p.runScheduled(ctx)
 			}
 		}
 	}()
@@ -156,119 +175,119 @@ func (p *ScheduleParser) New(ctx context.Context) {
 	<-ctx.Done()
 }
 
-func (p *ScheduleParser) parse(ctx context.Context) error {
-	t := time.Now()
+// DryRun fetches schedule of given groups (all groups if none given) and reports what parser would insert and delete.
+// Nothing is written to postgres and redis
+func (p *ScheduleParser) DryRun(ctx context.Context, groups ...string) (*Report, error) {
+	p.init()
+	p.dryRun = true
+	p.report = newReport()
+	p.cache = noCache{}
 
-	p.added = &added{}
+	run, err := p.begin(ctx, models.RunTriggerManual, groups)
+	if err != nil {
+		return nil, err
+	}
 
-	// Parsing groups
-	groups, err := p.parseGroups(ctx)
+	err = p.parse(ctx, run)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Adding groups to db
-	p.addGroupsToDB(ctx, groups)
+	return p.report, nil
+}
 
-	// Parsing schedule
-	// Not working with goroutines, rasp.dmami.ru not handling 500rps
-	// Parsing schedule time ~2m
-	var wg sync.WaitGroup
-	curr, groupsLen := 1, len(groups)
-	for _, group := range groups {
-		err = p.parseGroupSchedule(ctx, &wg, group, 0)
-		p.log.Debug(fmt.Sprintf("start parsing schedule for %v, isSession=%v: %v/%v", group, 0, curr, groupsLen))
-		curr++
-		if err != nil {
-			p.log.Error(fmt.Sprintf("error parsing schedule for %v: %v", group, err))
-		}
-	}
+// parse runs parser for groups of run (every group if none) and records results to run
+func (p *ScheduleParser) parse(ctx context.Context, run *models.ParserRun) error {
+	p.added = &added{parsed: make(map[string]int), errors: make(map[string][]string)}
 
-	wg.Wait()
-	curr = 1
-	for _, group := range groups {
-		err = p.parseGroupSchedule(ctx, &wg, group, 1)
-		p.log.Debug(fmt.Sprintf("start parsing schedule for %v, isSession=%v: %v/%v", group, 1, curr, groupsLen))
-		curr++
+	// Marking schedule changes made by this run
+	ctx = usecase.WithChangeSource(ctx, "parser:"+run.UUID.String())
+
+	// Parsing groups
+	groups := run.Groups
+	if len(groups) == 0 {
+		var err error
+		groups, err = p.source.Groups(ctx)
 		if err != nil {
-			p.log.Error(fmt.Sprintf("error parsing schedule for %v: %v", group, err))
+			p.finish(ctx, run, err)
+			return err
 		}
 	}
 
-	//group := "241-362"
-	//group := "221-352"
-	//group := "243-331"
-	//err = p.parseGroupSchedule(ctx, &wg, group, 0)
-	//err = p.parseGroupSchedule(ctx, &wg, group, 1)
-	//if err != nil {
-	//	p.log.Error(fmt.Sprintf("error parsing schedule for %v: %v", group, err))
-	//}
-
-	wg.Wait()
-	p.log.Info(
-		"schedule parsed",
-		slog.String("time_taken", time.Since(t).String()),
-		slog.Any("added", map[string]int{
-			"schedules": p.added.schedule,
-			"groups":    p.added.groups,
-			"subjects":  p.added.subjects,
-			"teachers":  p.added.teachers,
-			"rooms":     p.added.rooms,
-			"locations": p.added.locations,
-			"types":     p.added.types,
-		}),
-	)
-
-	return nil
-}
+	// Adding groups to db
+	p.addGroupsToDB(ctx, groups)
 
-func (p *ScheduleParser) parseGroups(ctx context.Context) ([]string, error) {
-	// New request to rasp.dmami.ru
-	req, err := http.NewRequestWithContext(ctx, "GET", "https://rasp.dmami.ru/", nil)
-	if err != nil {
-		return nil, err
+	// Parsing regular and session schedules of groups by pool of workers,
+	// requests to upstream are rate limited by source
+	type job struct {
+		group     string
+		isSession bool
 	}
+	jobs := make(chan job)
 
-	// Set referer
-	req.Header.Set("Referer", "https://rasp.dmami.ru/")
-
-	// Sending request
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return nil, err
+	workers := p.cfg.Concurrency
+	if workers < 1 {
+		workers = 1
 	}
-	defer resp.Body.Close()
 
-	// Reading response
-	raw, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	var wg sync.WaitGroup
+	var done atomic.Int64
+	total := 2 * len(groups)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				err := p.parseGroupSchedule(ctx, j.group, j.isSession)
+				p.parsed(j.group, err)
+				p.log.Debug(fmt.Sprintf("parsed schedule for %v, isSession=%v: %v/%v", j.group, j.isSession, done.Add(1), total))
+				if err != nil {
+					p.log.Error(fmt.Sprintf("error parsing schedule for %v, isSession=%v: %v", j.group, j.isSession, err))
+				}
+			}
+		}()
 	}
 
-	// Collect groups from response
-	re := regexp.MustCompile(`\d{2}[0-9a-zA-Zа-яА-Я]-\d{3,4}(\s[a-zA-Zа-яА-Я]{3})?`)
-	matches := re.FindAll(raw, -1)
-
-	// Deleting repeats from groups
-	gm := make(map[string]int)
-	for _, m := range matches {
-		if _, ok := gm[string(m)]; !ok {
-			gm[string(m)] = 1
+send:
+	for _, group := range groups {
+		for _, isSession := range []bool{false, true} {
+			select {
+			case <-ctx.Done():
+				break send
+			case jobs <- job{group: group, isSession: isSession}:
+			}
 		}
 	}
+	close(jobs)
+	wg.Wait()
 
-	// From map of groups to []string
-	groups := make([]string, 0, len(gm))
-	for group := range gm {
-		groups = append(groups, group)
+	if p.dryRun {
+		p.report.finish(p.added)
 	}
+	p.finish(ctx, run, ctx.Err())
+	p.log.Info(
+		"schedule parsed",
+		slog.String("run", run.UUID.String()),
+		slog.String("status", run.Status),
+		slog.String("time_taken", time.Duration(run.Duration*int64(time.Millisecond)).String()),
+		slog.Int("skipped", run.Skipped),
+		slog.Any("added", run.Added),
+	)
 
-	return groups, nil
+	return nil
 }
 
 func (p *ScheduleParser) addGroupsToDB(ctx context.Context, groups []string) {
-	groupUC := usecase.NewGroupUseCase(p.groupRepo, *p.groupSVC)
+	groupUC := usecase.NewGroupUseCase(p.groupRepo, *p.groupSVC, nil)
 	for _, group := range groups {
+		if p.dryRun {
+			_, err := groupUC.GetByNumber(ctx, strings.TrimSpace(group))
+			if err != nil && strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+				p.plan(entityGroup, strings.TrimSpace(group), &p.added.groups)
+			}
+			continue
+		}
+
 		// Adding group to db
 		_, err := groupUC.Create(ctx, &dto.CreateGroupRequest{Group: strings.TrimSpace(group)})
 
@@ -278,81 +297,115 @@ func (p *ScheduleParser) addGroupsToDB(ctx context.Context, groups []string) {
 				p.log.Error(fmt.Sprintf("error adding group %v to db: %v", group, err))
 			}
 		} else {
-			p.added.groups++
+			p.count(&p.added.groups, 1)
 		}
 	}
 }
 
-func (p *ScheduleParser) parseGroupSchedule(ctx context.Context, wg *sync.WaitGroup, group string, isSession int) error {
-
-	// New request to rasp.dmami.ru
-	req, err := http.NewRequestWithContext(
-		ctx,
-		"GET", fmt.Sprintf("https://rasp.dmami.ru/site/group?group=%v&session=%v", group, isSession),
-		nil,
-	)
-	if err != nil {
-		return err
+// plan adds entity that would be created to dry run report, counting it once
+func (p *ScheduleParser) plan(kind, name string, counter *int) {
+	if p.report.addEntity(kind, name) {
+		p.count(counter, 1)
 	}
+}
 
-	// Set referer
-	req.Header.Set("Referer", "https://rasp.dmami.ru/")
+// count adds n to counter of added entities, workers of parser call it concurrently
+func (p *ScheduleParser) count(counter *int, n int) {
+	p.added.mu.Lock()
+	defer p.added.mu.Unlock()
 
-	// Sending request
-	resp, err := p.client.Do(req)
+	*counter += n
+}
+
+func (p *ScheduleParser) parseGroupSchedule(ctx context.Context, group string, isSession bool) error {
+	state := p.loadState(ctx, group, isSession)
+
+	// Getting bells for converting pair nums to times, pairs of timetable change with them
+	bells, err := p.bellRepo.Get(ctx)
+	if err != nil {
+		return p.syncError("error getting bell schedule for the group %v: %v", group, err)
+	}
+	bellsHash, err := hashBells(bells)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
 
-	// Reading response
-	raw, err := io.ReadAll(resp.Body)
+	// Timetable is downloaded in full after bells changed, as upstream does not know about them
+	validators := state.Validators
+	if state.Bells != bellsHash {
+		validators = Validators{}
+	}
+
+	// Asking upstream whether timetable changed since last sync if it can tell
+	var r *Timetable
+	if source, ok := p.source.(ConditionalSource); ok {
+		r, err = source.ScheduleSince(ctx, group, isSession, validators)
+	} else {
+		r, err = p.source.Schedule(ctx, group, isSession)
+	}
+	if errors.Is(err, ErrNotModified) {
+		p.count(&p.added.skipped, 1)
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	// Unmarshall response
-	var r response
-	if err := json.Unmarshal(raw, &r); err != nil {
-		return fmt.Errorf("error unmarshling response %v: %v", r, err)
+	// Source has no schedule for the group
+	if r == nil {
+		return nil
 	}
 
-	// Handling error response
-	if r.Status != "ok" {
-		if r.Message == "Не нашлось расписание для группы" {
-			return nil
+	// Skipping timetable that did not change since last sync
+	hash, err := hashTimetable(r)
+	if err != nil {
+		return err
+	}
+	if hash == state.Hash && bellsHash == state.Bells {
+		p.count(&p.added.skipped, 1)
+		if r.Validators != state.Validators {
+			p.saveState(ctx, group, isSession, syncState{Hash: hash, Bells: bellsHash, Validators: r.Validators})
 		}
-		return fmt.Errorf("unknown response error: %v", r.Message)
+		return nil
 	}
 
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-
-		// Parsing subjects
-		p.parseSubjects(ctx, &r)
+	// Parsing subjects
+	p.parseSubjects(ctx, r)
 
-		// Parsing teachers
-		p.parseTeachers(ctx, &r)
+	// Parsing teachers
+	p.parseTeachers(ctx, r)
 
-		// Parsing rooms
-		p.parseRooms(ctx, &r)
+	// Parsing rooms
+	p.parseRooms(ctx, r)
 
-		// Parsing locations
-		p.parseLocations(ctx, &r)
+	// Parsing locations
+	p.parseLocations(ctx, r)
 
-		// Parsing types
-		p.parseTypes(ctx, &r)
+	// Parsing types
+	p.parseTypes(ctx, r)
 
-		// Parsing schedules
-		p.parseSchedules(ctx, group, &r)
-	}()
+	// Parsing schedules, timetable is synced again next time if something failed
+	errs := p.parseSchedules(ctx, group, r, bells)
+	if len(errs) != 0 {
+		return errors.Join(errs...)
+	}
+	if !p.dryRun {
+		p.saveState(ctx, group, isSession, syncState{Hash: hash, Bells: bellsHash, Validators: r.Validators})
+	}
 
 	return nil
 }
 
-func (p *ScheduleParser) parseSubjects(ctx context.Context, r *response) {
-	sbjUC := usecase.NewSubjectUseCase(p.sbjRepo, *p.sbjSVC)
+// lockEntity serialises check and creation of dictionary entity with given name between workers,
+// so pairs of groups sharing teacher or subject do not add it twice. Unlock function is returned
+func (p *ScheduleParser) lockEntity(kind, name string) func() {
+	mu, _ := p.entityLocks.LoadOrStore(kind+":"+name, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
+
+func (p *ScheduleParser) parseSubjects(ctx context.Context, r *Timetable) {
+	sbjUC := usecase.NewSubjectUseCase(p.sbjRepo, *p.sbjSVC, nil)
 
 	for _, day := range r.Grid {
 		for _, pair := range day {
@@ -361,9 +414,9 @@ func (p *ScheduleParser) parseSubjects(ctx context.Context, r *response) {
 				case <-ctx.Done():
 					return
 				default:
-					err := p.addSubjectToDB(ctx, sbjUC, pairData.Sbj)
+					err := p.addSubjectToDB(ctx, sbjUC, pairData.Subject)
 					if err != nil {
-						p.log.Error(fmt.Sprintf("error adding subject %v to db: %v", pairData.Sbj, err))
+						p.log.Error(fmt.Sprintf("error adding subject %v to db: %v", pairData.Subject, err))
 					}
 				}
 			}
@@ -372,17 +425,25 @@ func (p *ScheduleParser) parseSubjects(ctx context.Context, r *response) {
 }
 
 func (p *ScheduleParser) addSubjectToDB(ctx context.Context, sbjUC *usecase.SubjectUseCase, sbj string) error {
+	defer p.lockEntity(entitySubject, strings.TrimSpace(sbj))()
+
 	// Trying to get subject from db
 	_, err := sbjUC.GetByName(ctx, strings.TrimSpace(sbj))
 
 	// Adding subject if it does not exist
 	if err != nil {
 		if strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+			if p.dryRun {
+				p.plan(entitySubject, strings.TrimSpace(sbj), &p.added.subjects)
+				return nil
+			}
 			_, err = sbjUC.Create(ctx, &dto.CreateSubjectRequest{Name: strings.TrimSpace(sbj)})
 			if err != nil {
-				p.log.Error(fmt.Sprintf("error adding subject %v to db: %v", sbj, err))
+				if !strings.Contains(err.Error(), repository.ErrExist.Error()) {
+					p.log.Error(fmt.Sprintf("error adding subject %v to db: %v", sbj, err))
+				}
 			} else {
-				p.added.subjects++
+				p.count(&p.added.subjects, 1)
 			}
 		} else {
 			return err
@@ -392,8 +453,8 @@ func (p *ScheduleParser) addSubjectToDB(ctx context.Context, sbjUC *usecase.Subj
 	return nil
 }
 
-func (p *ScheduleParser) parseTeachers(ctx context.Context, r *response) {
-	teacherUC := usecase.NewTeacherUseCase(p.teacherRepo, *p.teacherSVC)
+func (p *ScheduleParser) parseTeachers(ctx context.Context, r *Timetable) {
+	teacherUC := usecase.NewTeacherUseCase(p.teacherRepo, *p.teacherSVC, nil)
 
 	for _, day := range r.Grid {
 		for _, pair := range day {
@@ -402,8 +463,7 @@ func (p *ScheduleParser) parseTeachers(ctx context.Context, r *response) {
 				case <-ctx.Done():
 					return
 				default:
-					teachers := teachersFromString(pairData.Teacher)
-					for _, fullname := range teachers {
+					for _, fullname := range pairData.Teachers {
 						// First, Last, Middle
 						flm := strings.Split(fullname, " ")
 
@@ -427,21 +487,29 @@ func (p *ScheduleParser) addTeacherToDB(ctx context.Context, teacherUC *usecase.
 		return fmt.Errorf("length of flm < 3")
 	}
 
+	defer p.lockEntity(entityTeacher, strings.TrimSpace(strings.Join(flm, " ")))()
+
 	// Trying to get teacher from db
 	_, err := teacherUC.GetByFullName(ctx, strings.TrimSpace(strings.Join(flm, " ")))
 
 	// Adding teacher if it does not exist
 	if err != nil {
 		if strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+			if p.dryRun {
+				p.plan(entityTeacher, strings.TrimSpace(strings.Join(flm, " ")), &p.added.teachers)
+				return nil
+			}
 			_, err = teacherUC.Create(ctx, &dto.CreateTeacherRequest{
 				FirstName:  flm[1],
 				SecondName: flm[0],
 				MiddleName: strings.TrimSpace(strings.Join(flm[2:], " ")),
 			})
 			if err != nil {
-				p.log.Error(fmt.Sprintf("error adding teacher %v to db: %v", strings.TrimSpace(strings.Join(flm, " ")), err))
+				if !strings.Contains(err.Error(), repository.ErrExist.Error()) {
+					p.log.Error(fmt.Sprintf("error adding teacher %v to db: %v", strings.TrimSpace(strings.Join(flm, " ")), err))
+				}
 			} else {
-				p.added.teachers++
+				p.count(&p.added.teachers, 1)
 			}
 		} else {
 			return err
@@ -451,8 +519,8 @@ func (p *ScheduleParser) addTeacherToDB(ctx context.Context, teacherUC *usecase.
 	return nil
 }
 
-func (p *ScheduleParser) parseRooms(ctx context.Context, r *response) {
-	roomUC := usecase.NewRoomUseCase(p.roomRepo, *p.roomSVC)
+func (p *ScheduleParser) parseRooms(ctx context.Context, r *Timetable) {
+	roomUC := usecase.NewRoomUseCase(p.roomRepo, p.bellRepo, *p.roomSVC, *p.bellSVC, nil)
 
 	for _, day := range r.Grid {
 		for _, pair := range day {
@@ -461,10 +529,7 @@ func (p *ScheduleParser) parseRooms(ctx context.Context, r *response) {
 				case <-ctx.Done():
 					return
 				default:
-					for _, room := range pairData.Auditories {
-						// Removing trash from room number
-						roomNum := removeHTML(removeEmojis(room.Title))
-
+					for _, roomNum := range pairData.Rooms {
 						// Adding room to db
 						err := p.addRoomToDB(ctx, roomUC, roomNum)
 						if err != nil {
@@ -478,17 +543,25 @@ func (p *ScheduleParser) parseRooms(ctx context.Context, r *response) {
 }
 
 func (p *ScheduleParser) addRoomToDB(ctx context.Context, roomUC *usecase.RoomUseCase, roomNum string) error {
+	defer p.lockEntity(entityRoom, strings.TrimSpace(roomNum))()
+
 	// Trying to get room from db
 	_, err := roomUC.GetByNumber(ctx, roomNum)
 
 	// Adding room if it does not exist
 	if err != nil {
 		if strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+			if p.dryRun {
+				p.plan(entityRoom, strings.TrimSpace(roomNum), &p.added.rooms)
+				return nil
+			}
 			_, err = roomUC.Create(ctx, &dto.CreateRoomRequest{Number: strings.TrimSpace(roomNum)})
 			if err != nil {
-				p.log.Error(fmt.Sprintf("error adding room %v to db: %v", roomNum, err))
+				if !strings.Contains(err.Error(), repository.ErrExist.Error()) {
+					p.log.Error(fmt.Sprintf("error adding room %v to db: %v", roomNum, err))
+				}
 			} else {
-				p.added.rooms++
+				p.count(&p.added.rooms, 1)
 			}
 		} else {
 			return err
@@ -498,22 +571,8 @@ func (p *ScheduleParser) addRoomToDB(ctx context.Context, roomUC *usecase.RoomUs
 	return nil
 }
 
-func removeEmojis(text string) string {
-	emojiRegex := regexp.MustCompile(`[\x{1F600}-\x{1F64F}]|[\x{1F300}-\x{1F5FF}]|[\x{1F680}-\x{1F6FF}]|[\x{2600}-\x{26FF}]|[\x{2700}-\x{27BF}]`)
-	return strings.TrimSpace(emojiRegex.ReplaceAllString(text, ""))
-}
-
-func removeHTML(text string) string {
-	htmlRegex := regexp.MustCompile(`>.*<`)
-	newText := htmlRegex.FindString(text)
-	if newText == "" {
-		return text
-	}
-	return strings.TrimSpace(newText[1 : len(newText)-1])
-}
-
-func (p *ScheduleParser) parseLocations(ctx context.Context, r *response) {
-	locationUC := usecase.NewLocationUseCase(p.locationRepo, *p.locationSVC)
+func (p *ScheduleParser) parseLocations(ctx context.Context, r *Timetable) {
+	locationUC := usecase.NewLocationUseCase(p.locationRepo, *p.locationSVC, nil)
 
 	for _, day := range r.Grid {
 		for _, pair := range day {
@@ -534,17 +593,25 @@ func (p *ScheduleParser) parseLocations(ctx context.Context, r *response) {
 }
 
 func (p *ScheduleParser) addLocationToDB(ctx context.Context, locationUC *usecase.LocationUseCase, location string) error {
+	defer p.lockEntity(entityLocation, strings.TrimSpace(location))()
+
 	// Trying to get location from db
 	_, err := locationUC.GetByName(ctx, strings.TrimSpace(location))
 
 	// Adding location if it does not exist
 	if err != nil {
 		if strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+			if p.dryRun {
+				p.plan(entityLocation, strings.TrimSpace(location), &p.added.locations)
+				return nil
+			}
 			_, err = locationUC.Create(ctx, &dto.CreateLocationRequest{Name: strings.TrimSpace(location)})
 			if err != nil {
-				p.log.Error(fmt.Sprintf("error adding location %v to db: %v", location, err))
+				if !strings.Contains(err.Error(), repository.ErrExist.Error()) {
+					p.log.Error(fmt.Sprintf("error adding location %v to db: %v", location, err))
+				}
 			} else {
-				p.added.locations++
+				p.count(&p.added.locations, 1)
 			}
 		} else {
 			return err
@@ -554,7 +621,7 @@ func (p *ScheduleParser) addLocationToDB(ctx context.Context, locationUC *usecas
 	return nil
 }
 
-func (p *ScheduleParser) parseTypes(ctx context.Context, r *response) {
+func (p *ScheduleParser) parseTypes(ctx context.Context, r *Timetable) {
 	typeUC := usecase.NewSubjectTypeUseCase(p.typeRepo, *p.typeSVC)
 
 	for _, day := range r.Grid {
@@ -576,17 +643,25 @@ func (p *ScheduleParser) parseTypes(ctx context.Context, r *response) {
 }
 
 func (p *ScheduleParser) addTypeToDB(ctx context.Context, typeUC *usecase.SubjectTypeUseCase, sbjType string) error {
+	defer p.lockEntity(entityType, strings.TrimSpace(sbjType))()
+
 	// Trying to get type from db
 	_, err := typeUC.GetByType(ctx, strings.TrimSpace(sbjType))
 
 	// Adding type if it does not exist
 	if err != nil {
 		if strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+			if p.dryRun {
+				p.plan(entityType, strings.TrimSpace(sbjType), &p.added.types)
+				return nil
+			}
 			_, err = typeUC.Create(ctx, &dto.CreateSubjectTypeRequest{Type: strings.TrimSpace(sbjType)})
 			if err != nil {
-				p.log.Error(fmt.Sprintf("error adding type %v to db: %v", sbjType, err))
+				if !strings.Contains(err.Error(), repository.ErrExist.Error()) {
+					p.log.Error(fmt.Sprintf("error adding type %v to db: %v", sbjType, err))
+				}
 			} else {
-				p.added.types++
+				p.count(&p.added.types, 1)
 			}
 		} else {
 			return err
@@ -596,22 +671,32 @@ func (p *ScheduleParser) addTypeToDB(ctx context.Context, typeUC *usecase.Subjec
 	return nil
 }
 
-func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *response) {
+// parseSchedules syncs pairs of group in db with timetable, returns errors of pairs that failed
+func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *Timetable, bells []*models.BellSchedule) []error {
+	// Changes of parser drop cached schedule queries same as changes from API
+	cached := myredis.NewCachedScheduleRepository(
+		p.scheduleRepo, p.cache, p.groupRepo, p.teacherRepo, p.roomRepo, p.sbjRepo, p.locationRepo,
+		time.Duration(p.cacheCfg.ScheduleTTL)*time.Second, time.Duration(p.cacheCfg.ScheduleAllTTL)*time.Second)
 	scheduleUC := usecase.NewScheduleUseCase(
-		p.scheduleRepo, p.groupRepo, p.sbjRepo, p.typeRepo,
+		p.scheduleRepo, cached, p.groupRepo, p.sbjRepo, p.typeRepo,
 		p.locationRepo, p.teacherRepo, p.roomRepo, p.repoTToS,
-		p.repoRToS, *p.scheduleSVC, p.cache)
+		p.repoRToS, p.bellRepo, p.changeRepo, p.webhookRepo, p.deliveryRepo, p.scopeRepo,
+		*p.scheduleSVC, *p.bellSVC, *p.webhookSVC, p.txManager)
 
 	// Getting week from db
-	week, err := scheduleUC.GetByGroup(ctx, group, r.IsSession)
+	week, err := scheduleUC.GetByGroup(ctx, group, r.IsSession, nil)
 
 	// Set the week to empty if it is not contained in the database
 	if err != nil && strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
 		week = &dto.Week{}
+	} else if err != nil {
+		return []error{p.syncError("error getting schedule of the group %v: %v", group, err)}
 	}
+
+	var errs []error
 	select {
 	case <-ctx.Done():
-		return
+		return []error{ctx.Err()}
 	default:
 		for dayNum, day := range r.Grid {
 			for pairNum, pair := range day {
@@ -633,18 +718,23 @@ func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *re
 				if len(parsedPairs) == 0 && len(dbPairs) == 0 {
 					continue
 				} else if len(parsedPairs) == 0 && len(dbPairs) > 0 {
+					if p.dryRun {
+						p.report.replacePairs(group, dayNum, pairNum, r.IsSession, dbPairs, nil)
+						continue
+					}
 					for _, dbPair := range dbPairs {
-						st, et := pairNumToSTET(pairNum)
 						err = scheduleUC.DeleteByParams(ctx, &dto.DeleteParams{
 							Group:     group,
-							StartTime: st,
-							EndTime:   et,
+							StartTime: dbPair.StartTime,
+							EndTime:   dbPair.EndTime,
 							StartDate: dbPair.StartDate,
 							Day:       dayNum,
 							IsSession: r.IsSession,
 						})
-						if err != nil && !strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
-							p.log.Error(fmt.Sprintf(
+						if err == nil {
+							scheduleRowsTotal.WithLabelValues("deleted").Inc()
+						} else if !strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+							errs = append(errs, p.syncError(
 								"error deleting schedule for the group %v on %v at %v: %v",
 								group, numToDay(dayNum), pairNum, err,
 							))
@@ -652,9 +742,9 @@ func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *re
 					}
 				} else {
 					// Converting parsed pairs to DTO
-					parsedPairsDTO, errs := parsedPairsToDTO(parsedPairs, r.IsSession)
-					if len(errs) != 0 {
-						p.log.Error(fmt.Sprintf("error convetring parsed pairs to DTO: %v", errs))
+					parsedPairsDTO, convErrs := p.parsedPairsToDTO(bells, parsedPairs, pairNum)
+					if len(convErrs) != 0 {
+						errs = append(errs, p.syncError("error convetring parsed pairs to DTO: %v", convErrs))
 					}
 
 					// Sorting teachers and rooms in pair from db
@@ -666,20 +756,27 @@ func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *re
 					}
 
 					if !cmp.Equal(dbPairs, parsedPairsDTO) {
+						if p.dryRun {
+							p.report.replacePairs(group, dayNum, pairNum, r.IsSession, dbPairs, parsedPairsDTO)
+							p.count(&p.added.schedule, len(parsedPairsDTO))
+							continue
+						}
+
 						// Deleting pair from db
 						for _, dbPair := range dbPairs {
-							st, et := pairNumToSTET(pairNum)
 							err = scheduleUC.DeleteByParams(ctx, &dto.DeleteParams{
 								Group:     group,
-								StartTime: st,
-								EndTime:   et,
+								StartTime: dbPair.StartTime,
+								EndTime:   dbPair.EndTime,
 								StartDate: dbPair.StartDate,
 								Day:       dayNum,
 								IsSession: r.IsSession,
 							})
 
-							if err != nil && !strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
-								p.log.Error(fmt.Sprintf(
+							if err == nil {
+								scheduleRowsTotal.WithLabelValues("deleted").Inc()
+							} else if !strings.Contains(err.Error(), repository.ErrNotFound.Error()) {
+								errs = append(errs, p.syncError(
 									"error deleting schedule for the group %v on %v at %v: %v",
 									group, numToDay(dayNum), pairNum, err,
 								))
@@ -688,70 +785,63 @@ func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *re
 
 						// Adding new pair to db
 						for _, pairData := range parsedPairs {
-							// Removing trash from rooms
-							var rooms []string
-							for _, room := range pairData.Auditories {
-								rooms = append(rooms, removeHTML(removeEmojis(room.Title)))
-							}
-
 							// Getting start and end times from pair num
-							st, et := pairNumToSTET(pairNum)
+							st, et, err := p.pairNumToSTET(bells, pairData, pairNum)
+							if err != nil {
+								errs = append(errs, p.syncError("error getting time of pair %v for the group %v: %v", pairNum, group, err))
+								continue
+							}
 
 							// Getting teachers uuid
-							teacherUC := usecase.NewTeacherUseCase(p.teacherRepo, *p.teacherSVC)
+							teacherUC := usecase.NewTeacherUseCase(p.teacherRepo, *p.teacherSVC, nil)
 							var teachersUUID []string
-							if pairData.Teacher != "" {
-								teachersUUID, err = teachersToUUID(ctx, strings.Split(pairData.Teacher, ", "), teacherUC)
+							if len(pairData.Teachers) != 0 {
+								teachersUUID, err = teachersToUUID(ctx, pairData.Teachers, teacherUC)
 								if err != nil {
-									p.log.Error(fmt.Sprintf("error getting teachers uuid %v: %v", pairData.Teacher, err))
+									errs = append(errs, p.syncError("error getting teachers uuid %v: %v", pairData.Teachers, err))
 								}
 							}
 
 							// Getting subject uuid
-							subjUC := usecase.NewSubjectUseCase(p.sbjRepo, *p.sbjSVC)
-							subjUUID, err := subjectToUUID(ctx, pairData.Sbj, subjUC)
+							subjUC := usecase.NewSubjectUseCase(p.sbjRepo, *p.sbjSVC, nil)
+							subjUUID, err := subjectToUUID(ctx, pairData.Subject, subjUC)
 							if err != nil {
-								p.log.Error(fmt.Sprintf("error getting subject %v uuid: %v", pairData.Sbj, err))
+								errs = append(errs, p.syncError("error getting subject %v uuid: %v", pairData.Subject, err))
 							}
 
 							// Mapping parsed pair to dto
 							pairDataDTO := &dto.ScheduleRequest{
 								Group:        group,
 								TeachersUUID: teachersUUID,
-								Rooms:        rooms,
+								Rooms:        pairData.Rooms,
 								SubjectUUID:  subjUUID,
-								Type:         strings.TrimSpace(pairData.Type),
-								Location:     strings.TrimSpace(pairData.Location),
+								Type:         pairData.Type,
+								Location:     pairData.Location,
 								StartTime:    st,
 								EndTime:      et,
-								Link:         getLinkFromHTML(pairData.Auditories[0].Title),
+								StartDate:    pairData.StartDate,
+								EndDate:      pairData.EndDate,
+								Link:         pairData.Link,
 								IsSession:    r.IsSession,
 							}
 
 							if !r.IsSession {
-								pairDataDTO.StartDate = strings.TrimSpace(pairData.Df)
-								pairDataDTO.EndDate = strings.TrimSpace(pairData.Dt)
 								wd, err := strconv.Atoi(dayNum)
 								if err != nil {
-									p.log.Error(fmt.Sprintf("error converting dayNum %v err: %v", dayNum, err))
+									errs = append(errs, p.syncError("error converting dayNum %v err: %v", dayNum, err))
 								}
 								pairDataDTO.Weekday = wd
 							} else {
-								date, err := parseDTSTime(pairData.Dts)
-								if err != nil {
-									p.log.Error(fmt.Sprintf("error parsing date %v err: %v", dayNum, err))
-								}
-								pairDataDTO.StartDate = date
-								pairDataDTO.EndDate = date
 								t, err := time.Parse(time.DateOnly, dayNum)
 								if err != nil {
-									p.log.Error(fmt.Sprintf("error parsing date %v err: %v", dayNum, err))
+									errs = append(errs, p.syncError("error parsing date %v err: %v", dayNum, err))
 								}
 								pairDataDTO.Weekday = int(t.Weekday())
 							}
 
 							err = p.addScheduleToDB(ctx, scheduleUC, pairDataDTO)
 							if err != nil {
+								errs = append(errs, fmt.Errorf("error adding pair to db: %v", err))
 								p.log.Error(
 									fmt.Sprintf("error adding pair to db: %v", err),
 									slog.Any("pairDataDTO", pairDataDTO),
@@ -763,6 +853,16 @@ func (p *ScheduleParser) parseSchedules(ctx context.Context, group string, r *re
 			}
 		}
 	}
+
+	return errs
+}
+
+// syncError logs error of syncing pairs and returns it
+func (p *ScheduleParser) syncError(format string, args ...any) error {
+	err := fmt.Errorf(format, args...)
+	p.log.Error(err.Error())
+
+	return err
 }
 
 func (p *ScheduleParser) deletePBGWT(ctx context.Context, scheduleUC *usecase.ScheduleUseCase, group, day, pairNum, startDate string, isSession bool) error {
@@ -798,81 +898,55 @@ func (p *ScheduleParser) deletePBGWT(ctx context.Context, scheduleUC *usecase.Sc
 	return err
 }
 
-func parseDTSTime(dts string) (string, error) {
-	months := map[string]string{
-		"Янв": "Jan", "Фев": "Feb", "Мар": "Mar",
-		"Апр": "Apr", "Май": "May", "Июн": "Jun",
-		"Июл": "Jul", "Авг": "Aug", "Сен": "Sep",
-		"Окт": "Oct", "Ноя": "Nov", "Дек": "Dec",
-	}
-
-	for ru, en := range months {
-		if strings.Contains(dts, ru) {
-			dts = strings.Replace(dts, ru, en, 1)
-			break
-		}
-	}
-
-	if len(strings.Split(dts, " ")) == 2 {
-		dts += fmt.Sprintf(" %v", time.Now().Year())
-	}
-
-	layout := "02 Jan 2006"
-	parsed, err := time.Parse(layout, dts)
-	if err != nil {
-		return "", err
-	}
-
-	return parsed.Format(time.DateOnly), nil
-}
-
 func (p *ScheduleParser) addScheduleToDB(ctx context.Context, scheduleUC *usecase.ScheduleUseCase, pairDataDTO *dto.ScheduleRequest) error {
-	_, err := scheduleUC.Create(ctx, pairDataDTO)
+	// Pairs are imported as they are, clashes are listed by conflicts report
+	_, err := scheduleUC.Create(ctx, pairDataDTO, true)
 	if err != nil {
 		return err
 	}
-	p.added.schedule++
+	p.count(&p.added.schedule, 1)
+	scheduleRowsTotal.WithLabelValues("inserted").Inc()
 
 	return nil
 }
 
-func parsedPairsToDTO(parsedPairs []lesson, isSession bool) ([]dto.Pair, []error) {
+func (p *ScheduleParser) parsedPairsToDTO(bells []*models.BellSchedule, parsedPairs []Lesson, pairNum string) ([]dto.Pair, []error) {
 	var parsedPairsDTO []dto.Pair
 	var errs []error
 	for _, pairData := range parsedPairs {
-		// Removing trash from rooms
-		var rooms []string
-		for _, room := range pairData.Auditories {
-			rooms = append(rooms, removeHTML(removeEmojis(room.Title)))
+		// Pairs from db have single empty name when they have no teachers or rooms
+		teachers := append([]string{}, pairData.Teachers...)
+		if len(teachers) == 0 {
+			teachers = []string{""}
+		}
+		rooms := append([]string{}, pairData.Rooms...)
+		if len(rooms) == 0 {
+			rooms = []string{""}
 		}
 
 		// Sorting teachers and rooms in parsed pair
-		teachers := teachersFromString(pairData.Teacher)
 		sort.Slice(teachers, func(i, j int) bool { return strings.ToLower(teachers[i]) < strings.ToLower(teachers[j]) })
 		sort.Slice(rooms, func(i, j int) bool { return strings.ToLower(rooms[i]) < strings.ToLower(rooms[j]) })
 
 		// Mapping parsed pair to dto
 		pairDataDTO := &dto.Pair{
-			Subject:  strings.TrimSpace(pairData.Sbj),
-			Teachers: teachers,
-			Rooms:    rooms,
-			Location: strings.TrimSpace(pairData.Location),
-			Type:     strings.TrimSpace(pairData.Type),
-			Link:     getLinkFromHTML(pairData.Auditories[0].Title),
+			Subject:   pairData.Subject,
+			Teachers:  teachers,
+			Rooms:     rooms,
+			Location:  pairData.Location,
+			Type:      pairData.Type,
+			Link:      pairData.Link,
+			StartDate: pairData.StartDate,
+			EndDate:   pairData.EndDate,
 		}
 
-		// Adding start and end dates
-		if !isSession {
-			pairDataDTO.StartDate = strings.TrimSpace(pairData.Df)
-			pairDataDTO.EndDate = strings.TrimSpace(pairData.Dt)
-		} else {
-			d, err := parseDTSTime(pairData.Dts)
-			if err != nil {
-				errs = append(errs, err)
-			}
-			pairDataDTO.StartDate = d
-			pairDataDTO.EndDate = d
+		// Adding start and end times
+		st, et, err := p.pairNumToSTET(bells, pairData, pairNum)
+		if err != nil {
+			errs = append(errs, err)
 		}
+		pairDataDTO.StartTime = st
+		pairDataDTO.EndTime = et
 
 		parsedPairsDTO = append(parsedPairsDTO, *pairDataDTO)
 	}
@@ -920,31 +994,24 @@ func getDay(week *dto.Week, dayName string) *dto.Day {
 	return &day
 }
 
-func pairNumToSTET(pair string) (string, string) {
-	p := map[string]string{
-		"1": "09:00:00 10:30:00",
-		"2": "10:40:00 12:10:00",
-		"3": "12:20:00 13:50:00",
-		"4": "14:30:00 16:00:00",
-		"5": "16:10:00 17:40:00",
-		"6": "17:50:00 19:20:00",
-		"7": "19:30:00 21:00:00",
+// pairNumToSTET returns start and end times of pair by bell schedule of pair location and date
+func (p *ScheduleParser) pairNumToSTET(bells []*models.BellSchedule, pairData Lesson, pairNum string) (string, string, error) {
+	num, err := strconv.Atoi(pairNum)
+	if err != nil {
+		return "", "", err
 	}
-	times := strings.Split(p[pair], " ")
-	return times[0], times[1]
-}
 
-func getLinkFromHTML(html string) string {
-	htmlRegex := regexp.MustCompile(`['"].*?['"]`)
-	links := htmlRegex.FindAllString(html, -1)
+	d, err := time.Parse(time.DateOnly, pairData.StartDate)
+	if err != nil {
+		return "", "", err
+	}
 
-	for _, link := range links {
-		if strings.Contains(link, "http") {
-			return link[1 : len(link)-1]
-		}
+	bell, ok := p.bellSVC.Slot(bells, pairData.Location, d, num)
+	if !ok {
+		return "", "", fmt.Errorf("no bell for pair %v", pairNum)
 	}
 
-	return ""
+	return bell.StartTime.Format(time.TimeOnly), bell.EndTime.Format(time.TimeOnly), nil
 }
 
 func teachersToUUID(ctx context.Context, teachers []string, teacherUC *usecase.TeacherUseCase) ([]string, error) {
@@ -976,22 +1043,3 @@ func subjectToUUID(ctx context.Context, subject string, subjUC *usecase.SubjectU
 
 	return subjectUUID[0].UUID.String(), nil
 }
-
-func teachersFromString(str string) []string {
-	var res []string
-	teachers := strings.Split(str, ", ")
-	for _, fullname := range teachers {
-		// first, last, middle
-		var flm []string
-
-		// Deleting extra spaces from names
-		for _, str := range strings.Split(fullname, " ") {
-			if str != "" && str != " " {
-				flm = append(flm, str)
-			}
-		}
-		res = append(res, strings.Join(flm, " "))
-	}
-
-	return res
-}
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"raspyx/config"
+	"time"
+)
+
+const (
+	SourceDmami = "dmami"
+	SourceDir   = "dir"
+	SourceTable = "table"
+)
+
+// ErrNotModified is returned by ConditionalSource when timetable did not change since given validators
+var ErrNotModified = errors.New("timetable not modified")
+
+// ScheduleSource gives groups and their timetables to parser
+type ScheduleSource interface {
+	// Groups returns numbers of all groups known to source
+	Groups(ctx context.Context) ([]string, error)
+	// Schedule returns regular or session timetable of group, nil if source has no schedule for group
+	Schedule(ctx context.Context, group string, isSession bool) (*Timetable, error)
+}
+
+// ConditionalSource is source which upstream tells whether timetable changed by ETag or Last-Modified
+type ConditionalSource interface {
+	ScheduleSource
+	// ScheduleSince works like Schedule, but returns ErrNotModified if timetable did not change since validators
+	ScheduleSince(ctx context.Context, group string, isSession bool, since Validators) (*Timetable, error)
+}
+
+// Validators are cache validators of timetable given by upstream
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// Timetable is schedule of group normalized by source.
+// Grid maps day to pair number to lessons, day is weekday number ("1" is monday) for regular schedule
+// and date ("2025-06-10") for session. Pairs missing in grid are not touched by parser
+type Timetable struct {
+	IsSession  bool
+	Grid       map[string]map[string][]Lesson
+	Validators Validators `json:"-"`
+}
+
+// Lesson is a single pair of timetable
+type Lesson struct {
+	Subject string
+	// Full names "Second First Middle"
+	Teachers []string
+	Rooms    []string
+	Link     string
+	Location string
+	Type     string
+	// Dates in time.DateOnly format, they are equal for session pairs
+	StartDate string
+	EndDate   string
+}
+
+// NewSource returns schedule source configured by cfg.Source
+func NewSource(cfg config.Parser, timeout time.Duration) (ScheduleSource, error) {
+	switch cfg.Source {
+	case SourceDmami:
+		return newDmamiSource(&http.Client{Timeout: timeout}, dmamiURL, cfg.RPS, cfg.Retries), nil
+	case SourceDir:
+		if cfg.SourcePath == "" {
+			return nil, fmt.Errorf("PARSER_SOURCE_PATH is required for %v source", SourceDir)
+		}
+		return newDirSource(cfg.SourcePath), nil
+	case SourceTable:
+		if cfg.SourcePath == "" {
+			return nil, fmt.Errorf("PARSER_SOURCE_PATH is required for %v source", SourceTable)
+		}
+		return newTableSource(cfg.SourcePath), nil
+	default:
+		return nil, fmt.Errorf("unknown schedule source %q", cfg.Source)
+	}
+}
+
+// newWeekGrid returns regular schedule grid with every weekday and pair, so parser deletes pairs missing in source
+func newWeekGrid() map[string]map[string][]Lesson {
+	grid := make(map[string]map[string][]Lesson)
+	for day := 1; day <= 6; day++ {
+		pairs := make(map[string][]Lesson)
+		for pair := 1; pair <= 7; pair++ {
+			pairs[fmt.Sprint(pair)] = []Lesson{}
+		}
+		grid[fmt.Sprint(day)] = pairs
+	}
+
+	return grid
+}
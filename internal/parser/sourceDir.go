@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const sessionSuffix = ".session.json"
+
+// dirSource replays responses of rasp.dmami.ru saved to directory.
+// Regular schedule of group is read from "<group>.json", session from "<group>.session.json"
+type dirSource struct {
+	path string
+}
+
+func newDirSource(path string) *dirSource {
+	return &dirSource{path: path}
+}
+
+func (s *dirSource) Groups(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	gm := make(map[string]bool)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		gm[strings.TrimSuffix(strings.TrimSuffix(name, sessionSuffix), ".json")] = true
+	}
+
+	groups := make([]string, 0, len(gm))
+	for group := range gm {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return groups, nil
+}
+
+func (s *dirSource) Schedule(ctx context.Context, group string, isSession bool) (*Timetable, error) {
+	name := group + ".json"
+	if isSession {
+		name = group + sessionSuffix
+	}
+
+	raw, err := os.ReadFile(filepath.Join(s.path, name))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return decodeResponse(raw)
+}
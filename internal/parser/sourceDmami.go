@@ -0,0 +1,342 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"golang.org/x/time/rate"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	dmamiURL = "https://rasp.dmami.ru/"
+	// First retry waits up to retryDelay, every next one up to twice as long
+	retryDelay = 500 * time.Millisecond
+)
+
+var groupRegex = regexp.MustCompile(`\d{2}[0-9a-zA-Zа-яА-Я]-\d{3,4}(\s[a-zA-Zа-яА-Я]{3})?`)
+
+// dmamiSource reads schedule from JSON API of rasp.dmami.ru
+type dmamiSource struct {
+	client  *http.Client
+	url     string
+	limiter *rate.Limiter
+	retries int
+}
+
+// statusError is returned for responses with unexpected status code
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status code %v", e.code)
+}
+
+type lesson struct {
+	Sbj        string `json:"sbj"`
+	Teacher    string `json:"teacher"`
+	Dts        string `json:"dts"`
+	Df         string `json:"df"`
+	Dt         string `json:"dt"`
+	Auditories []struct {
+		Title string `json:"title"`
+		Color string `json:"color"`
+	} `json:"auditories"`
+	ShortRooms []string `json:"shortRooms"`
+	Location   string   `json:"location"`
+	Type       string   `json:"type"`
+	Week       string   `json:"week"`
+	Align      string   `json:"align"`
+	ELink      any      `json:"e_link"`
+}
+
+type response struct {
+	Status    string                         `json:"status"`
+	Message   string                         `json:"message"`
+	Grid      map[string]map[string][]lesson `json:"grid"`
+	IsSession bool                           `json:"isSession"`
+}
+
+// newDmamiSource returns source making at most rps requests per second to url, unlimited if rps <= 0.
+// Failed requests are retried on timeouts and 5xx responses
+func newDmamiSource(client *http.Client, url string, rps float64, retries int) *dmamiSource {
+	limit := rate.Inf
+	if rps > 0 {
+		limit = rate.Limit(rps)
+	}
+
+	return &dmamiSource{
+		client:  client,
+		url:     url,
+		limiter: rate.NewLimiter(limit, 1),
+		retries: retries,
+	}
+}
+
+func (s *dmamiSource) Groups(ctx context.Context) ([]string, error) {
+	raw, _, err := s.get(ctx, s.url, Validators{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect groups from main page, deleting repeats
+	gm := make(map[string]bool)
+	for _, m := range groupRegex.FindAll(raw, -1) {
+		gm[string(m)] = true
+	}
+
+	groups := make([]string, 0, len(gm))
+	for group := range gm {
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+func (s *dmamiSource) Schedule(ctx context.Context, group string, isSession bool) (*Timetable, error) {
+	return s.ScheduleSince(ctx, group, isSession, Validators{})
+}
+
+func (s *dmamiSource) ScheduleSince(ctx context.Context, group string, isSession bool, since Validators) (*Timetable, error) {
+	session := 0
+	if isSession {
+		session = 1
+	}
+
+	raw, validators, err := s.get(ctx, fmt.Sprintf("%vsite/group?group=%v&session=%v", s.url, url.QueryEscape(group), session), since)
+	if err != nil {
+		return nil, err
+	}
+
+	timetable, err := decodeResponse(raw)
+	if err != nil || timetable == nil {
+		return nil, err
+	}
+	timetable.Validators = validators
+
+	return timetable, nil
+}
+
+// get requests link conditionally if validators are given
+func (s *dmamiSource) get(ctx context.Context, link string, since Validators) ([]byte, Validators, error) {
+	for attempt := 0; ; attempt++ {
+		raw, validators, err := s.fetch(ctx, link, since)
+		if err == nil || attempt >= s.retries || !retryable(err) || ctx.Err() != nil {
+			return raw, validators, err
+		}
+
+		// Exponential backoff with full jitter, so workers do not retry at the same moment
+		delay := time.Duration(rand.Int64N(int64(retryDelay << attempt)))
+		select {
+		case <-ctx.Done():
+			return nil, Validators{}, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (s *dmamiSource) fetch(ctx context.Context, link string, since Validators) ([]byte, Validators, error) {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return nil, Validators{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+
+	// rasp.dmami.ru does not answer without referer
+	req.Header.Set("Referer", s.url)
+	if since.ETag != "" {
+		req.Header.Set("If-None-Match", since.ETag)
+	}
+	if since.LastModified != "" {
+		req.Header.Set("If-Modified-Since", since.LastModified)
+	}
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		observeUpstream(start, 0)
+		return nil, Validators{}, err
+	}
+	defer resp.Body.Close()
+	observeUpstream(start, resp.StatusCode)
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, since, ErrNotModified
+	}
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, Validators{}, &statusError{code: resp.StatusCode}
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, Validators{}, err
+	}
+
+	return raw, Validators{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified")}, nil
+}
+
+// retryable reports whether request failed because of timeout or overloaded upstream
+func retryable(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return true
+	}
+
+	var ne net.Error
+	return errors.As(err, &ne) && ne.Timeout()
+}
+
+// decodeResponse converts response of rasp.dmami.ru to timetable
+func decodeResponse(raw []byte) (*Timetable, error) {
+	var r response
+	if err := json.Unmarshal(raw, &r); err != nil {
+		return nil, fmt.Errorf("error unmarshling response: %v", err)
+	}
+
+	// Handling error response
+	if r.Status != "ok" {
+		if r.Message == "Не нашлось расписание для группы" {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("unknown response error: %v", r.Message)
+	}
+
+	timetable := &Timetable{IsSession: r.IsSession, Grid: make(map[string]map[string][]Lesson)}
+	for day, pairs := range r.Grid {
+		timetable.Grid[day] = make(map[string][]Lesson)
+		for pairNum, lessons := range pairs {
+			normalized := make([]Lesson, 0, len(lessons))
+			for _, l := range lessons {
+				n, err := l.normalize(r.IsSession)
+				if err != nil {
+					return nil, fmt.Errorf("error reading pair %v on %v: %v", pairNum, day, err)
+				}
+				normalized = append(normalized, n)
+			}
+			timetable.Grid[day][pairNum] = normalized
+		}
+	}
+
+	return timetable, nil
+}
+
+func (l lesson) normalize(isSession bool) (Lesson, error) {
+	n := Lesson{
+		Subject:   strings.TrimSpace(l.Sbj),
+		Location:  strings.TrimSpace(l.Location),
+		Type:      strings.TrimSpace(l.Type),
+		StartDate: strings.TrimSpace(l.Df),
+		EndDate:   strings.TrimSpace(l.Dt),
+	}
+
+	for _, teacher := range teachersFromString(l.Teacher) {
+		if teacher != "" {
+			n.Teachers = append(n.Teachers, teacher)
+		}
+	}
+
+	// Removing trash from rooms
+	for _, room := range l.Auditories {
+		n.Rooms = append(n.Rooms, removeHTML(removeEmojis(room.Title)))
+	}
+	if len(l.Auditories) > 0 {
+		n.Link = getLinkFromHTML(l.Auditories[0].Title)
+	}
+
+	// Session pairs have only day and month
+	if isSession {
+		date, err := parseDTSTime(l.Dts)
+		if err != nil {
+			return Lesson{}, err
+		}
+		n.StartDate, n.EndDate = date, date
+	}
+
+	return n, nil
+}
+
+func parseDTSTime(dts string) (string, error) {
+	months := map[string]string{
+		"Янв": "Jan", "Фев": "Feb", "Мар": "Mar",
+		"Апр": "Apr", "Май": "May", "Июн": "Jun",
+		"Июл": "Jul", "Авг": "Aug", "Сен": "Sep",
+		"Окт": "Oct", "Ноя": "Nov", "Дек": "Dec",
+	}
+
+	for ru, en := range months {
+		if strings.Contains(dts, ru) {
+			dts = strings.Replace(dts, ru, en, 1)
+			break
+		}
+	}
+
+	if len(strings.Split(dts, " ")) == 2 {
+		dts += fmt.Sprintf(" %v", time.Now().Year())
+	}
+
+	layout := "02 Jan 2006"
+	parsed, err := time.Parse(layout, dts)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.Format(time.DateOnly), nil
+}
+
+func removeEmojis(text string) string {
+	emojiRegex := regexp.MustCompile(`[\x{1F600}-\x{1F64F}]|[\x{1F300}-\x{1F5FF}]|[\x{1F680}-\x{1F6FF}]|[\x{2600}-\x{26FF}]|[\x{2700}-\x{27BF}]`)
+	return strings.TrimSpace(emojiRegex.ReplaceAllString(text, ""))
+}
+
+func removeHTML(text string) string {
+	htmlRegex := regexp.MustCompile(`>.*<`)
+	newText := htmlRegex.FindString(text)
+	if newText == "" {
+		return text
+	}
+	return strings.TrimSpace(newText[1 : len(newText)-1])
+}
+
+func getLinkFromHTML(html string) string {
+	htmlRegex := regexp.MustCompile(`['"].*?['"]`)
+	links := htmlRegex.FindAllString(html, -1)
+
+	for _, link := range links {
+		if strings.Contains(link, "http") {
+			return link[1 : len(link)-1]
+		}
+	}
+
+	return ""
+}
+
+func teachersFromString(str string) []string {
+	var res []string
+	teachers := strings.Split(str, ", ")
+	for _, fullname := range teachers {
+		// first, last, middle
+		var flm []string
+
+		// Deleting extra spaces from names
+		for _, str := range strings.Split(fullname, " ") {
+			if str != "" && str != " " {
+				flm = append(flm, str)
+			}
+		}
+		res = append(res, strings.Join(flm, " "))
+	}
+
+	return res
+}
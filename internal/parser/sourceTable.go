@@ -0,0 +1,408 @@
+package parser
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Columns of timetable file, header row names them in any order
+const (
+	columnGroup     = "group"
+	columnDay       = "day"
+	columnPair      = "pair"
+	columnSubject   = "subject"
+	columnType      = "type"
+	columnTeachers  = "teachers"
+	columnRooms     = "rooms"
+	columnLocation  = "location"
+	columnStartDate = "start_date"
+	columnEndDate   = "end_date"
+	columnLink      = "link"
+)
+
+// tableSource reads schedule from uploaded .csv or .xlsx timetable with one pair per row.
+// Day is weekday number (1 is monday) for regular pairs and date for session pairs,
+// teachers and rooms are separated by commas. File is read again on every Groups call
+type tableSource struct {
+	path string
+	mu   sync.Mutex
+	// Rows by group
+	rows map[string][]tableRow
+}
+
+type tableRow struct {
+	day       string
+	pair      string
+	isSession bool
+	lesson    Lesson
+}
+
+func newTableSource(path string) *tableSource {
+	return &tableSource{path: path}
+}
+
+func (s *tableSource) Groups(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(s.rows))
+	for group := range s.rows {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	return groups, nil
+}
+
+func (s *tableSource) Schedule(ctx context.Context, group string, isSession bool) (*Timetable, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rows == nil {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	timetable := &Timetable{IsSession: isSession, Grid: make(map[string]map[string][]Lesson)}
+	if !isSession {
+		timetable.Grid = newWeekGrid()
+	}
+
+	found := false
+	for _, row := range s.rows[group] {
+		if row.isSession != isSession {
+			continue
+		}
+		found = true
+
+		if _, ok := timetable.Grid[row.day]; !ok {
+			timetable.Grid[row.day] = make(map[string][]Lesson)
+		}
+		timetable.Grid[row.day][row.pair] = append(timetable.Grid[row.day][row.pair], row.lesson)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	return timetable, nil
+}
+
+func (s *tableSource) load() error {
+	records, err := readTable(s.path)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return fmt.Errorf("timetable %v is empty", s.path)
+	}
+
+	// Mapping column names to indexes
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range []string{columnGroup, columnDay, columnPair, columnSubject} {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("timetable %v has no %q column", s.path, name)
+		}
+	}
+
+	rows := make(map[string][]tableRow)
+	for i, record := range records[1:] {
+		value := func(name string) string {
+			idx, ok := columns[name]
+			if !ok || idx >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[idx])
+		}
+
+		group := value(columnGroup)
+		if group == "" {
+			continue
+		}
+
+		row, err := newTableRow(value)
+		if err != nil {
+			// Header is the first line
+			return fmt.Errorf("timetable %v line %v: %v", s.path, i+2, err)
+		}
+		rows[group] = append(rows[group], row)
+	}
+	s.rows = rows
+
+	return nil
+}
+
+func newTableRow(value func(name string) string) (tableRow, error) {
+	row := tableRow{
+		pair: value(columnPair),
+		lesson: Lesson{
+			Subject:  value(columnSubject),
+			Rooms:    splitList(value(columnRooms)),
+			Link:     value(columnLink),
+			Location: value(columnLocation),
+			Type:     value(columnType),
+		},
+	}
+
+	for _, teacher := range splitList(value(columnTeachers)) {
+		row.lesson.Teachers = append(row.lesson.Teachers, strings.Join(strings.Fields(teacher), " "))
+	}
+
+	if num, err := strconv.Atoi(row.pair); err != nil || num < 1 {
+		return tableRow{}, fmt.Errorf("invalid pair number %q", row.pair)
+	}
+	if row.lesson.Subject == "" {
+		return tableRow{}, fmt.Errorf("empty subject")
+	}
+
+	// Weekday for regular pair, date for session one
+	day := value(columnDay)
+	if wd, err := strconv.Atoi(day); err == nil && wd >= 1 && wd <= 6 {
+		row.day = day
+
+		sd, err := parseTableDate(value(columnStartDate))
+		if err != nil {
+			return tableRow{}, fmt.Errorf("invalid start date: %v", err)
+		}
+		ed, err := parseTableDate(value(columnEndDate))
+		if err != nil {
+			return tableRow{}, fmt.Errorf("invalid end date: %v", err)
+		}
+		row.lesson.StartDate, row.lesson.EndDate = sd, ed
+	} else {
+		date, err := parseTableDate(day)
+		if err != nil {
+			return tableRow{}, fmt.Errorf("day %q is neither weekday number nor date", day)
+		}
+		row.day, row.isSession = date, true
+		row.lesson.StartDate, row.lesson.EndDate = date, date
+	}
+
+	return row, nil
+}
+
+// parseTableDate accepts ISO and russian dates, and date serial numbers of Excel
+func parseTableDate(s string) (string, error) {
+	for _, layout := range []string{time.DateOnly, "02.01.2006"} {
+		if d, err := time.Parse(layout, s); err == nil {
+			return d.Format(time.DateOnly), nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 0 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)).Format(time.DateOnly), nil
+	}
+
+	return "", fmt.Errorf("unknown date format %q", s)
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
+
+func readTable(name string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		raw, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		return readCSV(raw)
+	case ".xlsx":
+		return readXLSX(name)
+	default:
+		return nil, fmt.Errorf("unsupported timetable format %v, use .csv or .xlsx", filepath.Ext(name))
+	}
+}
+
+func readCSV(raw []byte) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(raw))
+	r.FieldsPerRecord = -1
+
+	// Excel with russian locale saves csv separated by semicolons
+	header, _, _ := bytes.Cut(raw, []byte("\n"))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	return r.ReadAll()
+}
+
+// readXLSX returns rows of the first sheet of workbook
+func readXLSX(name string) ([][]string, error) {
+	zr, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	// Finding file of the first sheet
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := decodeZipXML(files, "xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) == 0 {
+		return nil, fmt.Errorf("workbook has no sheets")
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeZipXML(files, "xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	sheetName := ""
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].ID {
+			sheetName = path.Join("xl", rel.Target)
+			if strings.HasPrefix(rel.Target, "/") {
+				sheetName = strings.TrimPrefix(rel.Target, "/")
+			}
+		}
+	}
+
+	// Shared strings are optional
+	var sst struct {
+		Items []struct {
+			T string `xml:"t"`
+			R []struct {
+				T string `xml:"t"`
+			} `xml:"r"`
+		} `xml:"si"`
+	}
+	if _, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeZipXML(files, "xl/sharedStrings.xml", &sst); err != nil {
+			return nil, err
+		}
+	}
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.T
+		for _, run := range item.R {
+			shared[i] += run.T
+		}
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeZipXML(files, sheetName, &sheet); err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(sheet.Rows))
+	for _, row := range sheet.Rows {
+		var record []string
+		for i, cell := range row.Cells {
+			// Empty cells are omitted, so position is taken from reference
+			col := i
+			if cell.Ref != "" {
+				var err error
+				col, err = columnIndex(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(shared) {
+					return nil, fmt.Errorf("invalid shared string %v in cell %v", cell.Value, cell.Ref)
+				}
+				record[col] = shared[idx]
+			case "inlineStr":
+				record[col] = cell.Inline
+			default:
+				record[col] = cell.Value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func decodeZipXML(files map[string]*zip.File, name string, v any) error {
+	f, ok := files[name]
+	if !ok {
+		return fmt.Errorf("workbook has no %v", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// maxColumns is number of columns of xlsx sheet, last one is XFD
+const maxColumns = 16384
+
+// columnIndex converts cell reference like "AB12" to zero based column index
+func columnIndex(ref string) (int, error) {
+	idx := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		idx = idx*26 + int(r-'A'+1)
+		if idx > maxColumns {
+			return 0, fmt.Errorf("invalid cell reference %q", ref)
+		}
+	}
+	if idx == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+
+	return idx - 1, nil
+}
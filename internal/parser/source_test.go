@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"raspyx/config"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	raw := []byte(`{"status":"ok","isSession":false,"grid":{"1":{"1":[{
+		"sbj":" Физика ","teacher":"Иванов  Иван Иванович, Петров Петр",
+		"df":"2025-02-01","dt":"2025-06-01","location":"Автозаводская","type":"Практика",
+		"auditories":[{"title":"<a href=\"https://online.mospolytech.ru/\" target=\"_blank\">ав4805</a>"}]
+	}],"2":[]}}}`)
+
+	timetable, err := decodeResponse(raw)
+	if err != nil {
+		t.Fatalf("decodeResponse() error = %v", err)
+	}
+
+	want := Lesson{
+		Subject:   "Физика",
+		Teachers:  []string{"Иванов Иван Иванович", "Петров Петр"},
+		Rooms:     []string{"ав4805"},
+		Link:      "https://online.mospolytech.ru/",
+		Location:  "Автозаводская",
+		Type:      "Практика",
+		StartDate: "2025-02-01",
+		EndDate:   "2025-06-01",
+	}
+	if got := timetable.Grid["1"]["1"]; len(got) != 1 || !reflect.DeepEqual(got[0], want) {
+		t.Errorf("decodeResponse() lesson = %+v, want %+v", got, want)
+	}
+
+	// Group without schedule
+	timetable, err = decodeResponse([]byte(`{"status":"error","message":"Не нашлось расписание для группы"}`))
+	if err != nil || timetable != nil {
+		t.Errorf("decodeResponse() = %v, %v, want nil timetable", timetable, err)
+	}
+}
+
+func TestTableSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timetable.csv")
+	csv := "group;day;pair;subject;type;teachers;rooms;location;start_date;end_date\n" +
+		"221-352;1;1;Физика;Практика;Иванов Иван Иванович, Петров Петр;ав4805;Автозаводская;01.02.2025;2025-06-01\n" +
+		"221-352;2025-06-10;2;Физика;Экзамен;Иванов Иван Иванович;;Автозаводская;;\n" +
+		"221-351;3;2;Химия;Лекция;;пк312;Павла Корчагина;2025-02-01;2025-06-01\n"
+	if err := os.WriteFile(path, []byte(csv), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	source, err := NewSource(config.Parser{Source: SourceTable, SourcePath: path}, 0)
+	if err != nil {
+		t.Fatalf("NewSource() error = %v", err)
+	}
+
+	groups, err := source.Groups(context.Background())
+	if err != nil {
+		t.Fatalf("tableSource.Groups() error = %v", err)
+	}
+	if want := []string{"221-351", "221-352"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("tableSource.Groups() = %v, want %v", groups, want)
+	}
+
+	regular, err := source.Schedule(context.Background(), "221-352", false)
+	if err != nil {
+		t.Fatalf("tableSource.Schedule() error = %v", err)
+	}
+	if got := regular.Grid["1"]["1"]; len(got) != 1 || got[0].StartDate != "2025-02-01" || len(got[0].Teachers) != 2 {
+		t.Errorf("tableSource.Schedule() regular pair = %+v", got)
+	}
+	// Every pair of week is listed so missing ones are deleted by parser
+	if _, ok := regular.Grid["6"]["7"]; !ok {
+		t.Errorf("tableSource.Schedule() regular grid misses empty pairs")
+	}
+
+	session, err := source.Schedule(context.Background(), "221-352", true)
+	if err != nil {
+		t.Fatalf("tableSource.Schedule() error = %v", err)
+	}
+	if got := session.Grid["2025-06-10"]["2"]; len(got) != 1 || got[0].EndDate != "2025-06-10" || !session.IsSession {
+		t.Errorf("tableSource.Schedule() session pair = %+v", got)
+	}
+
+	// Group has no session pairs
+	session, err = source.Schedule(context.Background(), "221-351", true)
+	if err != nil || session != nil {
+		t.Errorf("tableSource.Schedule() = %v, %v, want nil timetable", session, err)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{ref: "A1", want: 0},
+		{ref: "AB12", want: 27},
+		{ref: "XFD1", want: maxColumns - 1},
+		{ref: "XFE1", wantErr: true},
+		{ref: "XFDZZZ1", wantErr: true},
+		{ref: "a1", wantErr: true},
+		{ref: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := columnIndex(tt.ref)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("columnIndex(%q) = %v, %v, want %v, error %v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDmamiSourceRetry(t *testing.T) {
+	var requests atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") == "" {
+			t.Errorf("request without referer")
+		}
+		// Upstream is overloaded for the first two requests
+		if requests.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok","isSession":true,"grid":{}}`))
+	}))
+	defer srv.Close()
+
+	unavailable := testutil.ToFloat64(upstreamRequestsTotal.WithLabelValues("503"))
+	source := newDmamiSource(srv.Client(), srv.URL+"/", 0, 2)
+	timetable, err := source.Schedule(context.Background(), "221-352", true)
+	if err != nil {
+		t.Fatalf("dmamiSource.Schedule() error = %v", err)
+	}
+	if !timetable.IsSession || requests.Load() != 3 {
+		t.Errorf("dmamiSource.Schedule() made %v requests, want 3", requests.Load())
+	}
+	if got := testutil.ToFloat64(upstreamRequestsTotal.WithLabelValues("503")) - unavailable; got != 2 {
+		t.Errorf("parser_upstream_requests_total{status=\"503\"} grew by %v, want 2", got)
+	}
+
+	// Out of retries
+	requests.Store(0)
+	source = newDmamiSource(srv.Client(), srv.URL+"/", 0, 1)
+	if _, err := source.Schedule(context.Background(), "221-352", true); err == nil {
+		t.Errorf("dmamiSource.Schedule() error = nil, want status error")
+	}
+}
+
+func TestDmamiSourceNotModified(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(`{"status":"ok","isSession":false,"grid":{}}`))
+	}))
+	defer srv.Close()
+
+	source := newDmamiSource(srv.Client(), srv.URL+"/", 0, 0)
+	timetable, err := source.ScheduleSince(context.Background(), "221-352", false, Validators{})
+	if err != nil {
+		t.Fatalf("dmamiSource.ScheduleSince() error = %v", err)
+	}
+	if timetable.Validators.ETag != `"v1"` {
+		t.Errorf("dmamiSource.ScheduleSince() ETag = %v, want \"v1\"", timetable.Validators.ETag)
+	}
+
+	_, err = source.ScheduleSince(context.Background(), "221-352", false, timetable.Validators)
+	if !errors.Is(err, ErrNotModified) {
+		t.Errorf("dmamiSource.ScheduleSince() error = %v, want ErrNotModified", err)
+	}
+}
@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"raspyx/internal/domain/models"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Timetables are synced in full at least once in stateTTL even if they did not change,
+// so pairs edited in db by hand are restored
+const stateTTL = 7 * 24 * time.Hour
+
+// syncState is what parser remembers about timetable of group it synced with db,
+// timetable is synced again if either it or bell schedule changed
+type syncState struct {
+	Hash       string     `json:"hash"`
+	Bells      string     `json:"bells"`
+	Validators Validators `json:"validators"`
+}
+
+func stateKey(group string, isSession bool) string {
+	if isSession {
+		return fmt.Sprintf("parser:state:%v:1", group)
+	}
+	return fmt.Sprintf("parser:state:%v:0", group)
+}
+
+// loadState returns state of last sync of timetable, empty state if it is unknown
+func (p *ScheduleParser) loadState(ctx context.Context, group string, isSession bool) syncState {
+	var state syncState
+
+	cached, err := p.cache.Get(ctx, stateKey(group, isSession))
+	if err != nil {
+		return syncState{}
+	}
+	if err := json.Unmarshal([]byte(cached), &state); err != nil {
+		return syncState{}
+	}
+
+	return state
+}
+
+func (p *ScheduleParser) saveState(ctx context.Context, group string, isSession bool, state syncState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+
+	if err := p.cache.Set(ctx, stateKey(group, isSession), string(data), stateTTL); err != nil {
+		p.log.Error(fmt.Sprintf("error saving sync state of %v: %v", group, err))
+	}
+}
+
+// hashTimetable returns hash of timetable content
+func hashTimetable(timetable *Timetable) (string, error) {
+	// Maps are marshalled with sorted keys, so equal timetables give equal hashes
+	data, err := json.Marshal(timetable)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// hashBells returns hash of bell schedule, pair numbers of timetable are converted to times with it
+func hashBells(bells []*models.BellSchedule) (string, error) {
+	// Bells are sorted, so their order in db does not change hash
+	rows := make([]string, 0, len(bells))
+	for _, bell := range bells {
+		row, err := json.Marshal(bell)
+		if err != nil {
+			return "", err
+		}
+		rows = append(rows, string(row))
+	}
+	slices.Sort(rows)
+
+	sum := sha256.Sum256([]byte(strings.Join(rows, "\n")))
+	return hex.EncodeToString(sum[:]), nil
+}
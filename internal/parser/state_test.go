@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"raspyx/internal/domain/models"
+	"testing"
+	"time"
+)
+
+func TestHashBells(t *testing.T) {
+	bell := func(pairNum, hour int) *models.BellSchedule {
+		return &models.BellSchedule{
+			PairNum:   pairNum,
+			StartTime: time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC),
+			EndTime:   time.Date(0, 1, 1, hour+1, 30, 0, 0, time.UTC),
+		}
+	}
+
+	hash := func(bells ...*models.BellSchedule) string {
+		t.Helper()
+		h, err := hashBells(bells)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	base := hash(bell(1, 9), bell(2, 10))
+	if hash(bell(2, 10), bell(1, 9)) != base {
+		t.Fatal("expected order of bells to keep hash")
+	}
+	if hash(bell(1, 9), bell(2, 11)) == base {
+		t.Fatal("expected changed bells to change hash")
+	}
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+)
+
+type APIKeyRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyRepository(db *pgxpool.Pool) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+var apiKeySelectStatement = `
+	SELECT uuid, name, prefix, key_hash, scopes, created_by, created_at, expires_at, last_used_at, revoked_at
+	FROM api_keys`
+
+func (r *APIKeyRepository) Create(ctx context.Context, key *models.APIKey) error {
+	const op = "repository.postgres.APIKeyRepository.Create"
+
+	query := `INSERT INTO api_keys (uuid, name, prefix, key_hash, scopes, created_by, created_at, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, key.UUID, key.Name, key.Prefix, key.KeyHash,
+		key.Scopes, key.CreatedBy, key.CreatedAt, key.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) Get(ctx context.Context) ([]*models.APIKey, error) {
+	const op = "repository.postgres.APIKeyRepository.Get"
+
+	query := apiKeySelectStatement + ` ORDER BY created_at DESC`
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	const op = "repository.postgres.APIKeyRepository.GetByHash"
+
+	query := apiKeySelectStatement + ` WHERE key_hash = $1`
+	key, err := scanAPIKey(conn(ctx, r.db).QueryRow(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, uuid uuid.UUID) error {
+	const op = "repository.postgres.APIKeyRepository.Revoke"
+
+	query := `UPDATE api_keys SET revoked_at = now() WHERE uuid = $1 AND revoked_at IS NULL`
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, uuid uuid.UUID) error {
+	const op = "repository.postgres.APIKeyRepository.TouchLastUsed"
+
+	query := `UPDATE api_keys SET last_used_at = now() WHERE uuid = $1`
+	_, err := conn(ctx, r.db).Exec(ctx, query, uuid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row interface{ Scan(dest ...any) error }) (*models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.UUID, &key.Name, &key.Prefix, &key.KeyHash, &key.Scopes, &key.CreatedBy,
+		&key.CreatedAt, &key.ExpiresAt, &key.LastUsedAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &key, nil
+}
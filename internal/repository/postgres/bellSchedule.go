@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+	"strings"
+)
+
+type BellScheduleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewBellScheduleRepository(db *pgxpool.Pool) *BellScheduleRepository {
+	return &BellScheduleRepository{db: db}
+}
+
+var bellSelectStatement = `
+	SELECT bell_schedules.uuid, bell_schedules.location_uuid, COALESCE(locations.name, ''),
+		bell_schedules.start_date, bell_schedules.end_date, bell_schedules.pair_num,
+		bell_schedules.start_time, bell_schedules.end_time
+	FROM bell_schedules
+		LEFT JOIN locations ON bell_schedules.location_uuid = locations.uuid`
+
+func (r *BellScheduleRepository) Create(ctx context.Context, bell *models.BellSchedule) error {
+	const op = "repository.postgres.BellScheduleRepository.Create"
+
+	query := `INSERT INTO bell_schedules (uuid, location_uuid, start_date, end_date, pair_num, start_time, end_time)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, bell.UUID, bell.LocationUUID, bell.StartDate,
+		bell.EndDate, bell.PairNum, bell.StartTime, bell.EndTime,
+	)
+
+	if err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return fmt.Errorf("%s: %w", op, repository.ErrExist)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *BellScheduleRepository) Get(ctx context.Context) ([]*models.BellSchedule, error) {
+	const op = "repository.postgres.BellScheduleRepository.Get"
+
+	query := bellSelectStatement + `
+		ORDER BY bell_schedules.pair_num, bell_schedules.start_time`
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var bells []*models.BellSchedule
+	for rows.Next() {
+		var bell models.BellSchedule
+		err := rows.Scan(
+			&bell.UUID, &bell.LocationUUID, &bell.Location, &bell.StartDate,
+			&bell.EndDate, &bell.PairNum, &bell.StartTime, &bell.EndTime,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		bells = append(bells, &bell)
+	}
+
+	return bells, nil
+}
+
+func (r *BellScheduleRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.BellSchedule, error) {
+	const op = "repository.postgres.BellScheduleRepository.GetByUUID"
+
+	query := bellSelectStatement + `
+		WHERE bell_schedules.uuid = $1`
+
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
+	var bell models.BellSchedule
+	err := row.Scan(
+		&bell.UUID, &bell.LocationUUID, &bell.Location, &bell.StartDate,
+		&bell.EndDate, &bell.PairNum, &bell.StartTime, &bell.EndTime,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &bell, nil
+}
+
+func (r *BellScheduleRepository) Update(ctx context.Context, bell *models.BellSchedule) error {
+	const op = "repository.postgres.BellScheduleRepository.Update"
+
+	query := `UPDATE bell_schedules
+			  SET location_uuid = $1, start_date = $2, end_date = $3,
+			      pair_num = $4, start_time = $5, end_time = $6
+			  WHERE uuid = $7`
+
+	result, err := conn(ctx, r.db).Exec(
+		ctx, query, bell.LocationUUID, bell.StartDate, bell.EndDate,
+		bell.PairNum, bell.StartTime, bell.EndTime, bell.UUID,
+	)
+	if err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return fmt.Errorf("%s: %w", op, repository.ErrExist)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *BellScheduleRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
+	const op = "repository.postgres.BellScheduleRepository.Delete"
+
+	query := `DELETE FROM bell_schedules WHERE uuid = $1`
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
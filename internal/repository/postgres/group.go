@@ -25,7 +25,7 @@ func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error
 
 	query := `INSERT INTO groups (uuid, number) 
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, group.UUID, group.Number)
+	_, err := conn(ctx, r.db).Exec(ctx, query, group.UUID, group.Number)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -41,7 +41,7 @@ func (r *GroupRepository) Get(ctx context.Context) ([]*models.Group, error) {
 
 	query := `SELECT uuid, number
 			  FROM groups`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -61,13 +61,47 @@ func (r *GroupRepository) Get(ctx context.Context) ([]*models.Group, error) {
 	return groups, nil
 }
 
+// groupListColumns are columns of list fields
+var groupListColumns = map[string]string{"number": "number"}
+
+// List returns page of groups filtered and sorted by query
+func (r *GroupRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Group], error) {
+	const op = "repository.postgres.GroupRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, number FROM groups`, query, models.GroupListFields, groupListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var groups []*models.Group
+	var sortValues []string
+	for rows.Next() {
+		var group models.Group
+		var sortValue string
+		err := rows.Scan(&group.UUID, &group.Number, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		groups = append(groups, &group)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(groups, sortValues, query.Limit, func(group *models.Group) uuid.UUID { return group.UUID }), nil
+}
+
 func (r *GroupRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Group, error) {
 	const op = "repository.postgres.GroupRepository.GetByUUID"
 
 	query := `SELECT uuid, number
 			  FROM groups
 			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 
 	var group models.Group
 	err := row.Scan(&group.UUID, &group.Number)
@@ -87,7 +121,7 @@ func (r *GroupRepository) GetByNumber(ctx context.Context, number string) (*mode
 	query := `SELECT uuid, number
 			  FROM groups
 			  WHERE number = $1`
-	row := r.db.QueryRow(ctx, query, number)
+	row := conn(ctx, r.db).QueryRow(ctx, query, number)
 
 	var group models.Group
 	err := row.Scan(&group.UUID, &group.Number)
@@ -107,7 +141,7 @@ func (r *GroupRepository) Update(ctx context.Context, group *models.Group) error
 	query := `UPDATE groups
 			  SET number = $1
 			  WHERE uuid = $2`
-	result, err := r.db.Exec(ctx, query, group.Number, group.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, group.Number, group.UUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -127,7 +161,7 @@ func (r *GroupRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 
 	query := `DELETE FROM groups
 			  WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
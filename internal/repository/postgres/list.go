@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+// sqlTypes of list fields, values of filters and cursor are cast to them
+var sqlTypes = map[string]string{
+	models.ListFieldString: "text",
+	models.ListFieldInt:    "int",
+	models.ListFieldBool:   "boolean",
+	models.ListFieldDate:   "date",
+	models.ListFieldTime:   "time",
+	models.ListFieldArray:  "text",
+}
+
+var sqlOperators = map[string]string{
+	models.FilterEq: "=",
+	models.FilterNe: "<>",
+	models.FilterGt: ">",
+	models.FilterGe: ">=",
+	models.FilterLt: "<",
+	models.FilterLe: "<=",
+}
+
+// escapeLike keeps wildcards of value from matching any text
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// listStatement wraps base query with filters, order and limit of list query.
+// Columns are fields of list query to columns of base query, base query must select uuid column.
+// One extra row is selected to tell if there is next page, sort value of every row is selected last as list_sort_value
+func listStatement(base string, query *models.ListQuery, fields models.ListFields, columns map[string]string) (string, []any) {
+	var args []any
+	arg := func(value any) string {
+		args = append(args, value)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where []string
+	for _, filter := range query.Filters {
+		column := "list." + columns[filter.Field]
+		fieldType := fields[filter.Field]
+
+		switch {
+		case fieldType == models.ListFieldArray && filter.Op == models.FilterContains:
+			where = append(where, fmt.Sprintf(
+				`EXISTS (SELECT 1 FROM UNNEST(%s) AS item WHERE item ILIKE '%%' || %s || '%%')`,
+				column, arg(escapeLike(filter.Value)),
+			))
+		case fieldType == models.ListFieldArray:
+			where = append(where, fmt.Sprintf(`%s = ANY(%s)`, arg(filter.Value), column))
+		case filter.Op == models.FilterContains:
+			where = append(where, fmt.Sprintf(`%s ILIKE '%%' || %s || '%%'`, column, arg(escapeLike(filter.Value))))
+		default:
+			where = append(where, fmt.Sprintf(`%s %s %s::%s`, column, sqlOperators[filter.Op], arg(filter.Value), sqlTypes[fieldType]))
+		}
+	}
+
+	sortColumn := "list." + columns[query.SortField]
+	direction, compare := "ASC", ">"
+	if query.SortDesc {
+		direction, compare = "DESC", "<"
+	}
+	if query.After != nil {
+		where = append(where, fmt.Sprintf(`(%s, list.uuid) %s (%s::%s, %s)`,
+			sortColumn, compare, arg(query.After.Value), sqlTypes[fields[query.SortField]], arg(query.After.UUID),
+		))
+	}
+
+	statement := fmt.Sprintf(`SELECT list.*, %s::text AS list_sort_value FROM (%s) AS list`, sortColumn, base)
+	if len(where) > 0 {
+		statement += ` WHERE ` + strings.Join(where, ` AND `)
+	}
+	statement += fmt.Sprintf(` ORDER BY %s %s, list.uuid %s LIMIT %s`, sortColumn, direction, direction, arg(query.Limit+1))
+
+	return statement, args
+}
+
+// listPage cuts extra row selected by listStatement and makes cursor of next page from the last item
+func listPage[T any](items []T, sortValues []string, limit int, uuidOf func(T) uuid.UUID) *models.ListPage[T] {
+	page := &models.ListPage[T]{Items: items}
+	if len(items) > limit {
+		page.Items = items[:limit]
+		page.Next = &models.ListCursor{Value: sortValues[limit-1], UUID: uuidOf(items[limit-1])}
+	}
+
+	return page
+}
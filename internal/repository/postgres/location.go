@@ -25,7 +25,7 @@ func (r *LocationRepository) Create(ctx context.Context, location *models.Locati
 
 	query := `INSERT INTO locations (uuid, name) 
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, location.UUID, location.Name)
+	_, err := conn(ctx, r.db).Exec(ctx, query, location.UUID, location.Name)
 
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
@@ -43,7 +43,7 @@ func (r *LocationRepository) Get(ctx context.Context) ([]*models.Location, error
 
 	query := `SELECT uuid, name
 			  FROM locations`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -63,6 +63,40 @@ func (r *LocationRepository) Get(ctx context.Context) ([]*models.Location, error
 	return locations, nil
 }
 
+// locationListColumns are columns of list fields
+var locationListColumns = map[string]string{"name": "name"}
+
+// List returns page of locations filtered and sorted by query
+func (r *LocationRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Location], error) {
+	const op = "repository.postgres.LocationRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, name FROM locations`, query, models.LocationListFields, locationListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var locations []*models.Location
+	var sortValues []string
+	for rows.Next() {
+		var location models.Location
+		var sortValue string
+		err := rows.Scan(&location.UUID, &location.Name, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		locations = append(locations, &location)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(locations, sortValues, query.Limit, func(location *models.Location) uuid.UUID { return location.UUID }), nil
+}
+
 func (r *LocationRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Location, error) {
 	const op = "repository.postgres.LocationRepository.GetByUUID"
 
@@ -70,7 +104,7 @@ func (r *LocationRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*mo
 			  FROM locations
 			  WHERE uuid = $1`
 
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var location models.Location
 	err := row.Scan(&location.UUID, &location.Name)
 	if err != nil {
@@ -90,7 +124,7 @@ func (r *LocationRepository) GetByName(ctx context.Context, name string) (*model
 			  FROM locations
 			  WHERE name = $1`
 
-	row := r.db.QueryRow(ctx, query, name)
+	row := conn(ctx, r.db).QueryRow(ctx, query, name)
 	var location models.Location
 	err := row.Scan(&location.UUID, &location.Name)
 	if err != nil {
@@ -110,7 +144,7 @@ func (r *LocationRepository) Update(ctx context.Context, location *models.Locati
 			  SET name = $1
 			  WHERE uuid = $2`
 
-	result, err := r.db.Exec(ctx, query, location.Name, location.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, location.Name, location.UUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -130,7 +164,7 @@ func (r *LocationRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	const op = "repository.postgres.LocationRepository.Delete"
 
 	query := `DELETE FROM locations WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
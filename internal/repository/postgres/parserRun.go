@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+)
+
+type ParserRunRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewParserRunRepository(db *pgxpool.Pool) *ParserRunRepository {
+	return &ParserRunRepository{db: db}
+}
+
+var parserRunSelectStatement = `
+	SELECT uuid, trigger, groups, status, started_at, finished_at, duration_ms,
+		groups_processed, skipped, added, errors, error
+	FROM parser_runs`
+
+func (r *ParserRunRepository) Create(ctx context.Context, run *models.ParserRun) error {
+	const op = "repository.postgres.ParserRunRepository.Create"
+
+	query := `INSERT INTO parser_runs (uuid, trigger, groups, status, started_at)
+			  VALUES ($1, $2, $3, $4, $5)`
+	_, err := conn(ctx, r.db).Exec(ctx, query, run.UUID, run.Trigger, run.Groups, run.Status, run.StartedAt)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *ParserRunRepository) Update(ctx context.Context, run *models.ParserRun) error {
+	const op = "repository.postgres.ParserRunRepository.Update"
+
+	query := `UPDATE parser_runs
+			  SET status = $2, finished_at = $3, duration_ms = $4, groups_processed = $5,
+			      skipped = $6, added = $7, errors = $8, error = $9
+			  WHERE uuid = $1`
+	result, err := conn(ctx, r.db).Exec(
+		ctx, query, run.UUID, run.Status, run.FinishedAt, run.Duration, run.GroupsProcessed,
+		run.Skipped, run.Added, run.Errors, run.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *ParserRunRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.ParserRun, error) {
+	const op = "repository.postgres.ParserRunRepository.GetByUUID"
+
+	query := parserRunSelectStatement + ` WHERE uuid = $1`
+	run, err := scanParserRun(conn(ctx, r.db).QueryRow(ctx, query, uuid))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return run, nil
+}
+
+func (r *ParserRunRepository) Get(ctx context.Context, limit int) ([]*models.ParserRun, error) {
+	const op = "repository.postgres.ParserRunRepository.Get"
+
+	query := parserRunSelectStatement + ` ORDER BY started_at DESC LIMIT $1`
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	runs := []*models.ParserRun{}
+	for rows.Next() {
+		run, err := scanParserRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		runs = append(runs, run)
+	}
+
+	return runs, rows.Err()
+}
+
+func (r *ParserRunRepository) FailRunning(ctx context.Context, reason string) error {
+	const op = "repository.postgres.ParserRunRepository.FailRunning"
+
+	query := `UPDATE parser_runs SET status = $1, error = $2, finished_at = now() WHERE status = $3`
+	_, err := conn(ctx, r.db).Exec(ctx, query, models.RunFailed, reason, models.RunRunning)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanParserRun(row interface{ Scan(dest ...any) error }) (*models.ParserRun, error) {
+	var run models.ParserRun
+	err := row.Scan(
+		&run.UUID, &run.Trigger, &run.Groups, &run.Status, &run.StartedAt, &run.FinishedAt,
+		&run.Duration, &run.GroupsProcessed, &run.Skipped, &run.Added, &run.Errors, &run.Error,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
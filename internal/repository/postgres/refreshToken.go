@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+	"time"
+)
+
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+var refreshTokenSelectStatement = `
+	SELECT uuid, user_uuid, family, token_hash, access_jti, created_at, expires_at, revoked_at
+	FROM refresh_tokens`
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) error {
+	const op = "repository.postgres.RefreshTokenRepository.Create"
+
+	query := `INSERT INTO refresh_tokens (uuid, user_uuid, family, token_hash, access_jti, created_at, expires_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, token.UUID, token.UserUUID, token.Family, token.TokenHash,
+		token.AccessJTI, token.CreatedAt, token.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	const op = "repository.postgres.RefreshTokenRepository.GetByHash"
+
+	query := refreshTokenSelectStatement + ` WHERE token_hash = $1`
+	token, err := scanRefreshToken(conn(ctx, r.db).QueryRow(ctx, query, hash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+func (r *RefreshTokenRepository) GetCreatedSince(ctx context.Context, userUUID uuid.UUID, since time.Time) ([]*models.RefreshToken, error) {
+	const op = "repository.postgres.RefreshTokenRepository.GetCreatedSince"
+
+	query := refreshTokenSelectStatement + ` WHERE user_uuid = $1 AND created_at > $2`
+	rows, err := conn(ctx, r.db).Query(ctx, query, userUUID, since)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var tokens []*models.RefreshToken
+	for rows.Next() {
+		token, err := scanRefreshToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, uuid uuid.UUID) error {
+	const op = "repository.postgres.RefreshTokenRepository.Revoke"
+
+	// Token can be rotated once, concurrent refresh with the same token fails
+	query := `UPDATE refresh_tokens SET revoked_at = now() WHERE uuid = $1 AND revoked_at IS NULL`
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, family uuid.UUID) ([]uuid.UUID, error) {
+	const op = "repository.postgres.RefreshTokenRepository.RevokeFamily"
+
+	query := `UPDATE refresh_tokens SET revoked_at = now()
+			  WHERE family = $1 AND revoked_at IS NULL
+			  RETURNING access_jti`
+	rows, err := conn(ctx, r.db).Query(ctx, query, family)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var jtis []uuid.UUID
+	for rows.Next() {
+		var jti uuid.UUID
+		if err := rows.Scan(&jti); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		jtis = append(jtis, jti)
+	}
+
+	return jtis, rows.Err()
+}
+
+func scanRefreshToken(row interface{ Scan(dest ...any) error }) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := row.Scan(
+		&token.UUID, &token.UserUUID, &token.Family, &token.TokenHash,
+		&token.AccessJTI, &token.CreatedAt, &token.ExpiresAt, &token.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+)
+
+type RoleRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRoleRepository(db *pgxpool.Pool) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+var roleSelectStatement = `
+	SELECT r.name, r.description, COALESCE(ARRAY_AGG(rp.permission ORDER BY rp.permission)
+		FILTER (WHERE rp.permission IS NOT NULL), '{}')
+	FROM roles r
+	LEFT JOIN role_permissions rp ON rp.role = r.name`
+
+func (r *RoleRepository) Get(ctx context.Context) ([]*models.Role, error) {
+	const op = "repository.postgres.RoleRepository.Get"
+
+	query := roleSelectStatement + ` GROUP BY r.name, r.description ORDER BY r.name`
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var roles []*models.Role
+	for rows.Next() {
+		var role models.Role
+		err := rows.Scan(&role.Name, &role.Description, &role.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		roles = append(roles, &role)
+	}
+
+	return roles, rows.Err()
+}
+
+func (r *RoleRepository) GetByName(ctx context.Context, name string) (*models.Role, error) {
+	const op = "repository.postgres.RoleRepository.GetByName"
+
+	query := roleSelectStatement + ` WHERE r.name = $1 GROUP BY r.name, r.description`
+	row := conn(ctx, r.db).QueryRow(ctx, query, name)
+	var role models.Role
+	err := row.Scan(&role.Name, &role.Description, &role.Permissions)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &role, nil
+}
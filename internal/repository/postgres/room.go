@@ -10,6 +10,7 @@ import (
 	"raspyx/internal/domain/models"
 	"raspyx/internal/repository"
 	"strings"
+	"time"
 )
 
 type RoomRepository struct {
@@ -25,7 +26,7 @@ func (r *RoomRepository) Create(ctx context.Context, room *models.Room) error {
 
 	query := `INSERT INTO rooms (uuid, number)
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, room.UUID, room.Number)
+	_, err := conn(ctx, r.db).Exec(ctx, query, room.UUID, room.Number)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -41,7 +42,7 @@ func (r *RoomRepository) Get(ctx context.Context) ([]*models.Room, error) {
 
 	query := `SELECT uuid, number
 			  FROM rooms`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -61,13 +62,47 @@ func (r *RoomRepository) Get(ctx context.Context) ([]*models.Room, error) {
 	return rooms, nil
 }
 
+// roomListColumns are columns of list fields
+var roomListColumns = map[string]string{"number": "number"}
+
+// List returns page of rooms filtered and sorted by query
+func (r *RoomRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Room], error) {
+	const op = "repository.postgres.RoomRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, number FROM rooms`, query, models.RoomListFields, roomListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var rooms []*models.Room
+	var sortValues []string
+	for rows.Next() {
+		var room models.Room
+		var sortValue string
+		err := rows.Scan(&room.UUID, &room.Number, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rooms = append(rooms, &room)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(rooms, sortValues, query.Limit, func(room *models.Room) uuid.UUID { return room.UUID }), nil
+}
+
 func (r *RoomRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Room, error) {
 	const op = "repository.postgres.RoomRepository.GetByUUID"
 
 	query := `SELECT uuid, number
 			  FROM rooms
 			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var room models.Room
 	err := row.Scan(&room.UUID, &room.Number)
 	if err != nil {
@@ -86,7 +121,7 @@ func (r *RoomRepository) GetByNumber(ctx context.Context, number string) (*model
 	query := `SELECT uuid, number
 			  FROM rooms
 			  WHERE number = $1`
-	row := r.db.QueryRow(ctx, query, number)
+	row := conn(ctx, r.db).QueryRow(ctx, query, number)
 	var room models.Room
 	err := row.Scan(&room.UUID, &room.Number)
 	if err != nil {
@@ -99,13 +134,56 @@ func (r *RoomRepository) GetByNumber(ctx context.Context, number string) (*model
 	return &room, nil
 }
 
+// GetFree returns rooms with number prefix that are not booked between st and et on date.
+// With location given only rooms that have ever been used there are returned, rooms have no location of their own.
+func (r *RoomRepository) GetFree(ctx context.Context, location, prefix string, date, st, et time.Time) ([]*models.Room, error) {
+	const op = "repository.postgres.RoomRepository.GetFree"
+
+	query := `SELECT rooms.uuid, rooms.number
+			  FROM rooms
+			  WHERE starts_with(LOWER(rooms.number), LOWER($2))
+			  	AND ($1 = '' OR EXISTS (
+			  		SELECT 1
+			  		FROM rooms_to_schedule
+			  			JOIN schedule ON rooms_to_schedule.schedule_uuid = schedule.uuid
+			  			JOIN locations ON schedule.location_uuid = locations.uuid
+			  		WHERE rooms_to_schedule.room_uuid = rooms.uuid AND locations.name = $1))
+			  	AND NOT EXISTS (
+			  		SELECT 1
+			  		FROM rooms_to_schedule
+			  			JOIN schedule ON rooms_to_schedule.schedule_uuid = schedule.uuid
+			  		WHERE rooms_to_schedule.room_uuid = rooms.uuid
+			  			AND schedule.start_date <= $3 AND schedule.end_date >= $3
+			  			AND schedule.weekday = $4
+			  			AND schedule.start_time < $6 AND schedule.end_time > $5)
+			  ORDER BY rooms.number`
+	rows, err := conn(ctx, r.db).Query(ctx, query, location, prefix, date, int(date.Weekday()), st, et)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var rooms []*models.Room
+	for rows.Next() {
+		var room models.Room
+		err := rows.Scan(&room.UUID, &room.Number)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rooms = append(rooms, &room)
+	}
+
+	return rooms, nil
+}
+
 func (r *RoomRepository) Update(ctx context.Context, room *models.Room) error {
 	const op = "repository.postgres.RoomRepository.Update"
 
 	query := `UPDATE rooms
 			  SET number = $1
 			  WHERE uuid = $2`
-	result, err := r.db.Exec(ctx, query, room.Number, room.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, room.Number, room.UUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -125,7 +203,7 @@ func (r *RoomRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	const op = "repository.postgres.RoomRepository.Delete"
 
 	query := `DELETE FROM rooms WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
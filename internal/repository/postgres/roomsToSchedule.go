@@ -23,7 +23,7 @@ func (r *RoomsToScheduleRepository) Create(ctx context.Context, roomsToSchedule
 
 	query := `INSERT INTO rooms_to_schedule (room_uuid, schedule_uuid) 
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, roomsToSchedule.RoomUUID, roomsToSchedule.ScheduleUUID)
+	_, err := conn(ctx, r.db).Exec(ctx, query, roomsToSchedule.RoomUUID, roomsToSchedule.ScheduleUUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -41,7 +41,7 @@ func (r *RoomsToScheduleRepository) Get(ctx context.Context) ([]*models.RoomsToS
 
 	query := `SELECT room_uuid, schedule_uuid
 			  FROM rooms_to_schedule`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -67,7 +67,7 @@ func (r *RoomsToScheduleRepository) GetByRoomUUID(ctx context.Context, roomUUID
 	query := `SELECT room_uuid, schedule_uuid
 			  FROM rooms_to_schedule
 			  WHERE room_uuid = $1`
-	rows, err := r.db.Query(ctx, query, roomUUID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, roomUUID)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -97,7 +97,7 @@ func (r *RoomsToScheduleRepository) GetByScheduleUUID(ctx context.Context, sched
 	query := `SELECT room_uuid, schedule_uuid
 			  FROM rooms_to_schedule
 			  WHERE schedule_uuid = $1`
-	rows, err := r.db.Query(ctx, query, scheduleUUID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, scheduleUUID)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -126,7 +126,7 @@ func (r *RoomsToScheduleRepository) Delete(ctx context.Context, roomsToSchedule
 
 	query := `DELETE FROM rooms_to_schedule
 			  WHERE room_uuid = $1 AND schedule_uuid = $2`
-	result, err := r.db.Exec(ctx, query, roomsToSchedule.RoomUUID, roomsToSchedule.ScheduleUUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, roomsToSchedule.RoomUUID, roomsToSchedule.ScheduleUUID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
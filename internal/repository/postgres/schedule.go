@@ -12,7 +12,6 @@ import (
 	"raspyx/internal/domain/models"
 	"raspyx/internal/repository"
 	"strings"
-	"time"
 )
 
 type ScheduleRepository struct {
@@ -73,7 +72,7 @@ func (r *ScheduleRepository) Create(ctx context.Context, schedule *models.Schedu
                       				location_uuid, start_time, end_time, start_date,
                       				end_date, weekday, link, is_session)
 			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
-	_, err := r.db.Exec(
+	_, err := conn(ctx, r.db).Exec(
 		ctx, query, schedule.UUID, schedule.GroupUUID, schedule.SubjectUUID,
 		schedule.TypeUUID, schedule.LocationUUID, schedule.StartTime, schedule.EndTime,
 		schedule.StartDate, schedule.EndDate, schedule.Weekday, schedule.Link, schedule.IsSession,
@@ -89,7 +88,7 @@ func (r *ScheduleRepository) Get(ctx context.Context) ([]*models.ScheduleData, e
 	const op = "repository.postgres.ScheduleRepository.GetByTeacherUUID"
 
 	query := baseSelectStatement + baseGroupByStatement
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -107,14 +106,62 @@ func (r *ScheduleRepository) Get(ctx context.Context) ([]*models.ScheduleData, e
 	return schedules, nil
 }
 
+// scheduleListColumns are columns of list fields
+var scheduleListColumns = map[string]string{
+	"group":      "group_number",
+	"teacher":    "teachers",
+	"room":       "rooms",
+	"subject":    "subject_name",
+	"type":       "subject_type",
+	"location":   "location",
+	"weekday":    "weekday",
+	"start_time": "start_time",
+	"start_date": "start_date",
+	"end_date":   "end_date",
+	"is_session": "is_session",
+}
+
+// listedSchedule is pair selected with its sort value
+type listedSchedule struct {
+	models.ScheduleData
+	SortValue string `db:"list_sort_value"`
+}
+
+// List returns page of pairs filtered and sorted by query
+func (r *ScheduleRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.ScheduleData], error) {
+	const op = "repository.postgres.ScheduleRepository.List"
+
+	statement, args := listStatement(baseSelectStatement+baseGroupByStatement, query, models.ScheduleListFields, scheduleListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var listed []*listedSchedule
+	err = pgxscan.ScanAll(&listed, rows)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	schedules := make([]*models.ScheduleData, len(listed))
+	sortValues := make([]string, len(listed))
+	for i, schedule := range listed {
+		schedules[i], sortValues[i] = &schedule.ScheduleData, schedule.SortValue
+	}
+
+	return listPage(schedules, sortValues, query.Limit, func(schedule *models.ScheduleData) uuid.UUID { return schedule.UUID }), nil
+}
+
 func (r *ScheduleRepository) GetForUpdate(ctx context.Context, uuid uuid.UUID) (*models.Schedule, error) {
 	const op = "repository.postgres.ScheduleRepository.GetForUpdate"
 
 	query := `SELECT uuid, group_uuid, subject_uuid, type_uuid, location_uuid, start_time,
 					 end_time, start_date, end_date, weekday, link, is_session
 			  FROM schedule
-			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+			  WHERE uuid = $1
+			  FOR UPDATE`
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 
 	var schedule models.Schedule
 	err := row.Scan(
@@ -136,7 +183,7 @@ func (r *ScheduleRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*mo
 	const op = "repository.postgres.ScheduleRepository.GetByUUID"
 
 	query := baseSelectStatement + ` WHERE schedule.uuid = $1 ` + baseGroupByStatement
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var schedule models.ScheduleData
 	err := row.Scan(
 		&schedule.UUID, &schedule.Group, &schedule.Teachers,
@@ -163,10 +210,10 @@ func (r *ScheduleRepository) GetByTeacher(ctx context.Context, firstName, second
 	var row pgx.Row
 	if middleName != "" {
 		query += ` AND middle_name = $3`
-		row = r.db.QueryRow(ctx, query, firstName, secondName, middleName)
+		row = conn(ctx, r.db).QueryRow(ctx, query, firstName, secondName, middleName)
 	} else {
 		query += ` AND middle_name IS NULL`
-		row = r.db.QueryRow(ctx, query, firstName, secondName)
+		row = conn(ctx, r.db).QueryRow(ctx, query, firstName, secondName)
 	}
 
 	var teacherUUID uuid.UUID
@@ -195,7 +242,7 @@ func (r *ScheduleRepository) GetByTeacherUUID(ctx context.Context, teacherUUID u
 			FROM teachers_to_schedule
 			WHERE teacher_uuid = $1
 		) AND is_session = $2 ` + baseGroupByStatement
-	rows, err := r.db.Query(ctx, query, teacherUUID, isSession)
+	rows, err := conn(ctx, r.db).Query(ctx, query, teacherUUID, isSession)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -220,7 +267,7 @@ func (r *ScheduleRepository) GetByGroup(ctx context.Context, groupNumber string,
 			  FROM groups
 			  WHERE number = $1`
 
-	row := r.db.QueryRow(ctx, query, groupNumber)
+	row := conn(ctx, r.db).QueryRow(ctx, query, groupNumber)
 
 	var groupUUID uuid.UUID
 	err := row.Scan(&groupUUID)
@@ -248,7 +295,7 @@ func (r *ScheduleRepository) GetByGroupUUID(ctx context.Context, groupUUID uuid.
 			   start_date, end_date, weekday,
 			   link, is_session
 			  FROM (` + baseSelectStatement + ` WHERE groups.uuid = $1 AND is_session = $2 ` + baseGroupByStatement + ")"
-	rows, err := r.db.Query(ctx, query, groupUUID, isSession)
+	rows, err := conn(ctx, r.db).Query(ctx, query, groupUUID, isSession)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -273,7 +320,7 @@ func (r *ScheduleRepository) GetByRoom(ctx context.Context, roomNumber string, i
 			  FROM rooms
 			  WHERE number = $1`
 
-	row := r.db.QueryRow(ctx, query, roomNumber)
+	row := conn(ctx, r.db).QueryRow(ctx, query, roomNumber)
 
 	var roomUUID uuid.UUID
 	err := row.Scan(&roomUUID)
@@ -301,7 +348,7 @@ func (r *ScheduleRepository) GetByRoomUUID(ctx context.Context, roomUUID uuid.UU
 			FROM rooms_to_schedule
 			WHERE room_uuid = $1
 		) AND is_session = $2 ` + baseGroupByStatement
-	rows, err := r.db.Query(ctx, query, roomUUID, isSession)
+	rows, err := conn(ctx, r.db).Query(ctx, query, roomUUID, isSession)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -326,7 +373,7 @@ func (r *ScheduleRepository) GetBySubject(ctx context.Context, subjectName strin
 			  FROM subjects
 			  WHERE name = $1`
 
-	row := r.db.QueryRow(ctx, query, subjectName)
+	row := conn(ctx, r.db).QueryRow(ctx, query, subjectName)
 
 	var subjectUUID uuid.UUID
 	err := row.Scan(&subjectUUID)
@@ -349,7 +396,7 @@ func (r *ScheduleRepository) GetBySubjectUUID(ctx context.Context, subjectUUID u
 	const op = "repository.postgres.ScheduleRepository.GetBySubjectUUID"
 
 	query := baseSelectStatement + ` WHERE subjects.uuid = $1 AND is_session = $2 ` + baseGroupByStatement
-	rows, err := r.db.Query(ctx, query, subjectUUID, isSession)
+	rows, err := conn(ctx, r.db).Query(ctx, query, subjectUUID, isSession)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -374,7 +421,7 @@ func (r *ScheduleRepository) GetByLocation(ctx context.Context, locationName str
 			  FROM locations
 			  WHERE name = $1`
 
-	row := r.db.QueryRow(ctx, query, locationName)
+	row := conn(ctx, r.db).QueryRow(ctx, query, locationName)
 
 	var locationUUID uuid.UUID
 	err := row.Scan(&locationUUID)
@@ -397,7 +444,7 @@ func (r *ScheduleRepository) GetByLocationUUID(ctx context.Context, locationUUID
 	const op = "repository.postgres.ScheduleRepository.GetByLocationUUID"
 
 	query := baseSelectStatement + ` WHERE locations.uuid = $1 AND is_session = $2 ` + baseGroupByStatement
-	rows, err := r.db.Query(ctx, query, locationUUID, isSession)
+	rows, err := conn(ctx, r.db).Query(ctx, query, locationUUID, isSession)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -423,7 +470,7 @@ func (r *ScheduleRepository) Update(ctx context.Context, schedule *models.Schedu
 			      location_uuid = $5, start_time = $6, end_time = $7,
 			      start_date = $8, end_date = $9, weekday = $10, link = $11
 			  WHERE uuid = $1`
-	result, err := r.db.Exec(
+	result, err := conn(ctx, r.db).Exec(
 		ctx, query, schedule.UUID, schedule.GroupUUID, schedule.SubjectUUID,
 		schedule.TypeUUID, schedule.LocationUUID, schedule.StartTime, schedule.EndTime,
 		schedule.StartDate, schedule.EndDate, schedule.Weekday, schedule.Link,
@@ -444,7 +491,7 @@ func (r *ScheduleRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	const op = "repository.postgres.ScheduleRepository.Delete"
 
 	query := `DELETE FROM schedule WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -457,25 +504,8 @@ func (r *ScheduleRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	return nil
 }
 
-func (r *ScheduleRepository) DeletePairsByGroupWeekdayTime(ctx context.Context, groupUUID uuid.UUID, weekday int, st, sd time.Time, isSession bool) error {
-	const op = "repository.postgres.ScheduleRepository.DeletePairsByGroupWeekdayTime"
-
-	query := `DELETE FROM schedule 
-       		  WHERE group_uuid = $1 AND weekday = $2 AND start_time = $3 AND start_date = $4 AND is_session = $5`
-	result, err := r.db.Exec(ctx, query, groupUUID, weekday, st, sd, isSession)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
-	}
-
-	return nil
-}
-
-func (r *ScheduleRepository) DeleteByParams(ctx context.Context, params *models.ScheduleData) error {
+// DeleteByParams deletes pairs matching non-empty params and returns uuids of deleted pairs
+func (r *ScheduleRepository) DeleteByParams(ctx context.Context, params *models.ScheduleData) ([]uuid.UUID, error) {
 	const op = "repository.postgres.ScheduleRepository.DeleteByParams"
 
 	base := `DELETE FROM schedule
@@ -527,19 +557,32 @@ func (r *ScheduleRepository) DeleteByParams(ctx context.Context, params *models.
 	add("schedule.is_session = $%d", params.IsSession)
 
 	if len(conds) == 0 {
-		return fmt.Errorf("%s: no parameters provided for deletion", op)
+		return nil, fmt.Errorf("%s: no parameters provided for deletion", op)
 	}
 
-	query := base + " AND " + strings.Join(conds, " AND ")
+	query := base + " AND " + strings.Join(conds, " AND ") + " RETURNING schedule.uuid"
 
-	result, err := r.db.Exec(ctx, query, args...)
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	defer rows.Close()
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	var deleted []uuid.UUID
+	for rows.Next() {
+		var UUID uuid.UUID
+		err := rows.Scan(&UUID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		deleted = append(deleted, UUID)
 	}
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(deleted) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+	return deleted, nil
 }
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type ScheduleChangeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewScheduleChangeRepository(db *pgxpool.Pool) *ScheduleChangeRepository {
+	return &ScheduleChangeRepository{db: db}
+}
+
+func (r *ScheduleChangeRepository) Create(ctx context.Context, change *models.ScheduleChange) error {
+	const op = "repository.postgres.ScheduleChangeRepository.Create"
+
+	query := `INSERT INTO schedule_changes (uuid, schedule_uuid, group_number, action, before, after, source, created_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, change.UUID, change.ScheduleUUID, change.Group,
+		change.Action, change.Before, change.After, change.Source, change.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetByGroup returns changes made since given time, changes of every group are returned if group number is empty
+func (r *ScheduleChangeRepository) GetByGroup(ctx context.Context, groupNumber string, since time.Time) ([]*models.ScheduleChange, error) {
+	const op = "repository.postgres.ScheduleChangeRepository.GetByGroup"
+
+	query := `SELECT uuid, schedule_uuid, group_number, action, before, after, source, created_at
+			  FROM schedule_changes
+			  WHERE ($1 = '' OR group_number = $1) AND created_at >= $2
+			  ORDER BY created_at, uuid`
+	rows, err := conn(ctx, r.db).Query(ctx, query, groupNumber, since)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var changes []*models.ScheduleChange
+	for rows.Next() {
+		var change models.ScheduleChange
+		err := rows.Scan(
+			&change.UUID, &change.ScheduleUUID, &change.Group, &change.Action,
+			&change.Before, &change.After, &change.Source, &change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		changes = append(changes, &change)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+type SearchRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSearchRepository(db *pgxpool.Pool) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// searchEntities are tables searched by their title, titles are matched in lower case with ё replaced by е
+// as in trigram indexes
+var searchEntities = []struct {
+	typ   string
+	table string
+	title string
+}{
+	{models.SearchTypeGroup, "groups", "number"},
+	{models.SearchTypeTeacher, "teachers", "TRIM(second_name || ' ' || first_name || ' ' || COALESCE(middle_name, ''))"},
+	{models.SearchTypeRoom, "rooms", "number"},
+	{models.SearchTypeSubject, "subjects", "name"},
+}
+
+// searchStatement scores titles by trigram similarity to terms, titles starting with term get 1 more
+// and titles with word starting with term get 0.5 more for autocomplete
+const searchStatement = `SELECT '{type}' AS type, e.uuid, e.title,
+       (GREATEST(similarity(e.search, terms.term), word_similarity(terms.term, e.search))
+        + CASE WHEN e.search LIKE terms.pattern || '%' THEN 1
+               WHEN e.search LIKE '% ' || terms.pattern || '%' THEN 0.5
+               ELSE 0 END)::float8 AS score
+FROM (SELECT uuid, {title} AS title, replace(lower({title}), 'ё', 'е') AS search FROM {table}) AS e, terms
+WHERE '{type}' = ANY($3::text[])
+  AND (e.search % terms.term OR terms.term <% e.search OR e.search LIKE '%' || terms.pattern || '%')`
+
+// searchInitialsStatement matches teachers by similar surname and first letters of name and patronymic
+const searchInitialsStatement = `SELECT 'teacher' AS type, t.uuid,
+       TRIM(t.second_name || ' ' || t.first_name || ' ' || COALESCE(t.middle_name, '')) AS title,
+       (1 + similarity(replace(lower(t.second_name), 'ё', 'е'), i.second_name))::float8 AS score
+FROM teachers AS t, unnest($4::text[], $5::text[], $6::text[]) AS i(second_name, first_name, middle_name)
+WHERE 'teacher' = ANY($3::text[])
+  AND replace(lower(t.second_name), 'ё', 'е') % i.second_name
+  AND replace(lower(t.first_name), 'ё', 'е') LIKE i.first_name || '%'
+  AND replace(lower(COALESCE(t.middle_name, '')), 'ё', 'е') LIKE i.middle_name || '%'`
+
+// Search returns best matches of query terms among groups, teachers, rooms and subjects
+func (r *SearchRepository) Search(ctx context.Context, query *models.SearchQuery) ([]*models.SearchHit, error) {
+	const op = "repository.postgres.SearchRepository.Search"
+
+	branches := make([]string, 0, len(searchEntities)+1)
+	for _, entity := range searchEntities {
+		branches = append(branches, strings.NewReplacer(
+			"{type}", entity.typ, "{table}", entity.table, "{title}", entity.title,
+		).Replace(searchStatement))
+	}
+	branches = append(branches, searchInitialsStatement)
+
+	statement := `WITH terms AS (SELECT * FROM unnest($1::text[], $2::text[]) AS terms(term, pattern))
+				  SELECT type, uuid, title, MAX(score) AS score
+				  FROM (` + strings.Join(branches, "\nUNION ALL\n") + `) AS hits
+				  GROUP BY type, uuid, title
+				  ORDER BY score DESC, title
+				  LIMIT $7`
+
+	patterns := make([]string, len(query.Terms))
+	for i, term := range query.Terms {
+		patterns[i] = escapeLike(term)
+	}
+	secondNames := make([]string, len(query.Initials))
+	firstNames := make([]string, len(query.Initials))
+	middleNames := make([]string, len(query.Initials))
+	for i, initials := range query.Initials {
+		secondNames[i], firstNames[i], middleNames[i] = initials.SecondName, initials.FirstName, initials.MiddleName
+	}
+
+	rows, err := conn(ctx, r.db).Query(ctx, statement,
+		query.Terms, patterns, query.Types, secondNames, firstNames, middleNames, query.Limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	hits := make([]*models.SearchHit, 0)
+	for rows.Next() {
+		var hit models.SearchHit
+		err := rows.Scan(&hit.Type, &hit.UUID, &hit.Title, &hit.Score)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		hits = append(hits, &hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"raspyx/internal/domain/models"
 	"raspyx/internal/repository"
+	"strings"
 )
 
 type SubjectRepository struct {
@@ -22,12 +23,17 @@ func NewSubjectRepository(db *pgxpool.Pool) *SubjectRepository {
 func (r *SubjectRepository) Create(ctx context.Context, subject *models.Subject) error {
 	const op = "repository.postgres.SubjectRepository.Create"
 
+	// Name is unique, parser workers may add the same subject at the same time
 	query := `INSERT INTO subjects (uuid, name) 
-			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, subject.UUID, subject.Name)
+			  VALUES ($1, $2)
+			  ON CONFLICT DO NOTHING`
+	result, err := conn(ctx, r.db).Exec(ctx, query, subject.UUID, subject.Name)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrExist)
+	}
 
 	return nil
 }
@@ -37,7 +43,7 @@ func (r *SubjectRepository) Get(ctx context.Context) ([]*models.Subject, error)
 
 	query := `SELECT uuid, name
 			  FROM subjects`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -57,13 +63,47 @@ func (r *SubjectRepository) Get(ctx context.Context) ([]*models.Subject, error)
 	return subjects, nil
 }
 
+// subjectListColumns are columns of list fields
+var subjectListColumns = map[string]string{"name": "name"}
+
+// List returns page of subjects filtered and sorted by query
+func (r *SubjectRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Subject], error) {
+	const op = "repository.postgres.SubjectRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, name FROM subjects`, query, models.SubjectListFields, subjectListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var subjects []*models.Subject
+	var sortValues []string
+	for rows.Next() {
+		var subject models.Subject
+		var sortValue string
+		err := rows.Scan(&subject.UUID, &subject.Name, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		subjects = append(subjects, &subject)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(subjects, sortValues, query.Limit, func(subject *models.Subject) uuid.UUID { return subject.UUID }), nil
+}
+
 func (r *SubjectRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Subject, error) {
 	const op = "repository.postgres.SubjectRepository.GetByUUID"
 
 	query := `SELECT uuid, name
 			  FROM subjects
 			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var subject models.Subject
 	err := row.Scan(&subject.UUID, &subject.Name)
 	if err != nil {
@@ -82,7 +122,7 @@ func (r *SubjectRepository) GetByName(ctx context.Context, name string) ([]*mode
 	query := `SELECT uuid, name
 			  FROM subjects
 			  WHERE name = $1`
-	rows, err := r.db.Query(ctx, query, name)
+	rows, err := conn(ctx, r.db).Query(ctx, query, name)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -112,8 +152,11 @@ func (r *SubjectRepository) Update(ctx context.Context, subject *models.Subject)
 	query := `UPDATE subjects
 			  SET name = $1
 			  WHERE uuid = $2`
-	result, err := r.db.Exec(ctx, query, subject.Name, subject.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, subject.Name, subject.UUID)
 	if err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return fmt.Errorf("%s: %w", op, repository.ErrExist)
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -129,7 +172,7 @@ func (r *SubjectRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	const op = "repository.postgres.SubjectRepository.Delete"
 
 	query := `DELETE FROM subjects WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -25,7 +25,7 @@ func (r *SubjectTypeRepository) Create(ctx context.Context, subjectType *models.
 
 	query := `INSERT INTO subj_types (uuid, type) 
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, subjectType.UUID, subjectType.Type)
+	_, err := conn(ctx, r.db).Exec(ctx, query, subjectType.UUID, subjectType.Type)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -41,7 +41,7 @@ func (r *SubjectTypeRepository) Get(ctx context.Context) ([]*models.SubjectType,
 
 	query := `SELECT uuid, type
 			  FROM subj_types`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -61,13 +61,47 @@ func (r *SubjectTypeRepository) Get(ctx context.Context) ([]*models.SubjectType,
 	return subjTypes, nil
 }
 
+// subjectTypeListColumns are columns of list fields
+var subjectTypeListColumns = map[string]string{"type": "type"}
+
+// List returns page of subjTypes filtered and sorted by query
+func (r *SubjectTypeRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.SubjectType], error) {
+	const op = "repository.postgres.SubjectTypeRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, type FROM subj_types`, query, models.SubjectTypeListFields, subjectTypeListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var subjTypes []*models.SubjectType
+	var sortValues []string
+	for rows.Next() {
+		var subjType models.SubjectType
+		var sortValue string
+		err := rows.Scan(&subjType.UUID, &subjType.Type, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		subjTypes = append(subjTypes, &subjType)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(subjTypes, sortValues, query.Limit, func(subjType *models.SubjectType) uuid.UUID { return subjType.UUID }), nil
+}
+
 func (r *SubjectTypeRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.SubjectType, error) {
 	const op = "repository.postgres.SubjectTypeRepository.GetByUUID"
 
 	query := `SELECT uuid, type
 			  FROM subj_types
 			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var subjType models.SubjectType
 	err := row.Scan(&subjType.UUID, &subjType.Type)
 	if err != nil {
@@ -86,7 +120,7 @@ func (r *SubjectTypeRepository) GetByType(ctx context.Context, subjectType strin
 	query := `SELECT uuid, type
 			  FROM subj_types
 			  WHERE type = $1`
-	row := r.db.QueryRow(ctx, query, subjectType)
+	row := conn(ctx, r.db).QueryRow(ctx, query, subjectType)
 	var subjType models.SubjectType
 	err := row.Scan(&subjType.UUID, &subjType.Type)
 	if err != nil {
@@ -105,7 +139,7 @@ func (r *SubjectTypeRepository) Update(ctx context.Context, subjectType *models.
 	query := `UPDATE subj_types
 			  SET type = $1
 			  WHERE uuid = $2`
-	result, err := r.db.Exec(ctx, query, subjectType.Type, subjectType.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, subjectType.Type, subjectType.UUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -125,7 +159,7 @@ func (r *SubjectTypeRepository) Delete(ctx context.Context, uuid uuid.UUID) erro
 	const op = "repository.postgres.SubjectTypeRepository.Delete"
 
 	query := `DELETE FROM subj_types WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
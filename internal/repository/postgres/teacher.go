@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"raspyx/internal/domain/models"
 	"raspyx/internal/repository"
+	"strings"
 )
 
 type TeacherRepository struct {
@@ -22,9 +23,11 @@ func NewTeacherRepository(db *pgxpool.Pool) *TeacherRepository {
 func (r *TeacherRepository) Create(ctx context.Context, teacher *models.Teacher) error {
 	const op = "repository.postgres.TeacherRepository.Create"
 
+	// Full name is unique, parser workers may add the same teacher at the same time
 	query := `INSERT INTO teachers (uuid, first_name, second_name, middle_name) 
-			  VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(ctx, query,
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT DO NOTHING`
+	result, err := conn(ctx, r.db).Exec(ctx, query,
 		teacher.UUID,
 		teacher.FirstName,
 		teacher.SecondName,
@@ -33,6 +36,9 @@ func (r *TeacherRepository) Create(ctx context.Context, teacher *models.Teacher)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrExist)
+	}
 
 	return nil
 }
@@ -42,7 +48,7 @@ func (r *TeacherRepository) Get(ctx context.Context) ([]*models.Teacher, error)
 
 	query := `SELECT uuid, first_name, second_name, middle_name 
 			  FROM teachers`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -62,6 +68,40 @@ func (r *TeacherRepository) Get(ctx context.Context) ([]*models.Teacher, error)
 	return teachers, nil
 }
 
+// teacherListColumns are columns of list fields
+var teacherListColumns = map[string]string{"first_name": "first_name", "second_name": "second_name", "middle_name": "middle_name"}
+
+// List returns page of teachers filtered and sorted by query
+func (r *TeacherRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.Teacher], error) {
+	const op = "repository.postgres.TeacherRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, first_name, second_name, COALESCE(middle_name, '') AS middle_name FROM teachers`, query, models.TeacherListFields, teacherListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var teachers []*models.Teacher
+	var sortValues []string
+	for rows.Next() {
+		var teacher models.Teacher
+		var sortValue string
+		err := rows.Scan(&teacher.UUID, &teacher.FirstName, &teacher.SecondName, &teacher.MiddleName, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		teachers = append(teachers, &teacher)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(teachers, sortValues, query.Limit, func(teacher *models.Teacher) uuid.UUID { return teacher.UUID }), nil
+}
+
 func (r *TeacherRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Teacher, error) {
 	const op = "repository.postgres.TeacherRepository.GetByUUID"
 
@@ -69,7 +109,7 @@ func (r *TeacherRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*mod
 			  FROM teachers 
 			  WHERE uuid = $1`
 
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 
 	var teacher models.Teacher
 	var middleName sql.NullString
@@ -96,7 +136,7 @@ func (r *TeacherRepository) GetByFullName(ctx context.Context, fn string) ([]*mo
 			  FROM teachers 
 			  WHERE TRIM(CONCAT(second_name, ' ', first_name, ' ', middle_name)) = $1`
 
-	rows, err := r.db.Query(ctx, query, fn)
+	rows, err := conn(ctx, r.db).Query(ctx, query, fn)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -135,8 +175,11 @@ func (r *TeacherRepository) Update(ctx context.Context, teacher *models.Teacher)
 	          SET first_name = $1, second_name = $2, middle_name = $3 
 	          WHERE uuid = $4`
 
-	result, err := r.db.Exec(ctx, query, teacher.FirstName, teacher.SecondName, teacher.MiddleName, teacher.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, teacher.FirstName, teacher.SecondName, teacher.MiddleName, teacher.UUID)
 	if err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return fmt.Errorf("%s: %w", op, repository.ErrExist)
+		}
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -153,7 +196,7 @@ func (r *TeacherRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 
 	query := `DELETE FROM teachers WHERE uuid = $1`
 
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
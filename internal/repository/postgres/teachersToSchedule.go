@@ -23,7 +23,7 @@ func (r *TeachersToScheduleRepository) Create(ctx context.Context, teachersToSch
 
 	query := `INSERT INTO teachers_to_schedule (teacher_uuid, schedule_uuid) 
 			  VALUES ($1, $2)`
-	_, err := r.db.Exec(ctx, query, teachersToSchedule.TeacherUUID, teachersToSchedule.ScheduleUUID)
+	_, err := conn(ctx, r.db).Exec(ctx, query, teachersToSchedule.TeacherUUID, teachersToSchedule.ScheduleUUID)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -41,7 +41,7 @@ func (r *TeachersToScheduleRepository) Get(ctx context.Context) ([]*models.Teach
 
 	query := `SELECT teacher_uuid, schedule_uuid
 			  FROM teachers_to_schedule`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -66,7 +66,7 @@ func (r *TeachersToScheduleRepository) GetByTeacherUUID(ctx context.Context, tea
 	query := `SELECT teacher_uuid, schedule_uuid
 			  FROM teachers_to_schedule
 			  WHERE teacher_uuid = $1`
-	rows, err := r.db.Query(ctx, query, teacherUUID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, teacherUUID)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -95,7 +95,7 @@ func (r *TeachersToScheduleRepository) GetByScheduleUUID(ctx context.Context, sc
 	query := `SELECT teacher_uuid, schedule_uuid
 			  FROM teachers_to_schedule
 			  WHERE schedule_uuid = $1`
-	rows, err := r.db.Query(ctx, query, scheduleUUID)
+	rows, err := conn(ctx, r.db).Query(ctx, query, scheduleUUID)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -123,7 +123,7 @@ func (r *TeachersToScheduleRepository) Delete(ctx context.Context, teachersToSch
 	const op = "repository.postgres.TeachersToScheduleRepository.Delete"
 	query := `DELETE FROM teachers_to_schedule
 			  WHERE teacher_uuid = $1 AND schedule_uuid = $2`
-	result, err := r.db.Exec(ctx, query, teachersToSchedule.TeacherUUID, teachersToSchedule.ScheduleUUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, teachersToSchedule.TeacherUUID, teachersToSchedule.ScheduleUUID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -23,9 +23,9 @@ func NewUserRepository(db *pgxpool.Pool) *UserRepository {
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	const op = "repository.postgres.UserRepository.Create"
 
-	query := `INSERT INTO users (uuid, username, password_hash, access_level)
+	query := `INSERT INTO users (uuid, username, password_hash, role)
 			  VALUES ($1, $2, $3, $4)`
-	_, err := r.db.Exec(ctx, query, user.UUID, user.Username, user.PasswordHash, user.AccessLevel)
+	_, err := conn(ctx, r.db).Exec(ctx, query, user.UUID, user.Username, user.PasswordHash, user.Role)
 	if err != nil {
 		if strings.Contains(err.Error(), "23505") {
 			return fmt.Errorf("%s: %w", op, repository.ErrExist)
@@ -39,9 +39,9 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 func (r *UserRepository) Get(ctx context.Context) ([]*models.User, error) {
 	const op = "repository.postgres.UserRepository.Get"
 
-	query := `SELECT uuid, username, password_hash, access_level
+	query := `SELECT uuid, username, password_hash, role
 			  FROM users`
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -50,7 +50,7 @@ func (r *UserRepository) Get(ctx context.Context) ([]*models.User, error) {
 	var users []*models.User
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.AccessLevel)
+		err := rows.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.Role)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -61,15 +61,49 @@ func (r *UserRepository) Get(ctx context.Context) ([]*models.User, error) {
 	return users, nil
 }
 
+// userListColumns are columns of list fields
+var userListColumns = map[string]string{"username": "username", "role": "role"}
+
+// List returns page of users filtered and sorted by query
+func (r *UserRepository) List(ctx context.Context, query *models.ListQuery) (*models.ListPage[*models.User], error) {
+	const op = "repository.postgres.UserRepository.List"
+
+	statement, args := listStatement(`SELECT uuid, username, password_hash, role FROM users`, query, models.UserListFields, userListColumns)
+	rows, err := conn(ctx, r.db).Query(ctx, statement, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	var sortValues []string
+	for rows.Next() {
+		var user models.User
+		var sortValue string
+		err := rows.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.Role, &sortValue)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, &user)
+		sortValues = append(sortValues, sortValue)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listPage(users, sortValues, query.Limit, func(user *models.User) uuid.UUID { return user.UUID }), nil
+}
+
 func (r *UserRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.User, error) {
 	const op = "repository.postgres.UserRepository.GetByUUID"
 
-	query := `SELECT uuid, username, password_hash, access_level
+	query := `SELECT uuid, username, password_hash, role
 			  FROM users
 			  WHERE uuid = $1`
-	row := r.db.QueryRow(ctx, query, uuid)
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
 	var user models.User
-	err := row.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.AccessLevel)
+	err := row.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
@@ -83,12 +117,12 @@ func (r *UserRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models
 func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	const op = "repository.postgres.UserRepository.GetByUsername"
 
-	query := `SELECT uuid, username, password_hash, access_level
+	query := `SELECT uuid, username, password_hash, role
 			  FROM users
 			  WHERE username = $1`
-	row := r.db.QueryRow(ctx, query, username)
+	row := conn(ctx, r.db).QueryRow(ctx, query, username)
 	var user models.User
-	err := row.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.AccessLevel)
+	err := row.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.Role)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
@@ -99,13 +133,13 @@ func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*m
 	return &user, nil
 }
 
-func (r *UserRepository) GetByAccessLevel(ctx context.Context, accessLevel int) ([]*models.User, error) {
-	const op = "repository.postgres.UserRepository.AccessLevel"
+func (r *UserRepository) GetByRole(ctx context.Context, role string) ([]*models.User, error) {
+	const op = "repository.postgres.UserRepository.GetByRole"
 
-	query := `SELECT uuid, username, password_hash, access_level
+	query := `SELECT uuid, username, password_hash, role
 			  FROM users
-			  WHERE access_level <= $1`
-	rows, err := r.db.Query(ctx, query, accessLevel)
+			  WHERE role = $1`
+	rows, err := conn(ctx, r.db).Query(ctx, query, role)
 	defer rows.Close()
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
@@ -114,7 +148,7 @@ func (r *UserRepository) GetByAccessLevel(ctx context.Context, accessLevel int)
 	var users []*models.User
 	for rows.Next() {
 		var user models.User
-		err := rows.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.AccessLevel)
+		err := rows.Scan(&user.UUID, &user.Username, &user.PasswordHash, &user.Role)
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
@@ -133,9 +167,9 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	const op = "repository.postgres.UserRepository.Update"
 
 	query := `UPDATE users
-			  SET access_level = $1
+			  SET role = $1
 			  WHERE uuid = $2`
-	result, err := r.db.Exec(ctx, query, user.AccessLevel, user.UUID)
+	result, err := conn(ctx, r.db).Exec(ctx, query, user.Role, user.UUID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -152,7 +186,7 @@ func (r *UserRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
 	const op = "repository.postgres.UserRepository.Delete"
 
 	query := `DELETE FROM users WHERE uuid = $1`
-	result, err := r.db.Exec(ctx, query, uuid)
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
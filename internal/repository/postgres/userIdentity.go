@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+	"strings"
+)
+
+type UserIdentityRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserIdentityRepository(db *pgxpool.Pool) *UserIdentityRepository {
+	return &UserIdentityRepository{db: db}
+}
+
+func (r *UserIdentityRepository) Create(ctx context.Context, identity *models.UserIdentity) error {
+	const op = "repository.postgres.UserIdentityRepository.Create"
+
+	query := `INSERT INTO user_identities (issuer, subject, user_uuid, created_at)
+			  VALUES ($1, $2, $3, $4)`
+	_, err := conn(ctx, r.db).Exec(ctx, query, identity.Issuer, identity.Subject, identity.UserUUID, identity.CreatedAt)
+	if err != nil {
+		if strings.Contains(err.Error(), "23505") {
+			return fmt.Errorf("%s: %w", op, repository.ErrExist)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *UserIdentityRepository) GetByIssuerSubject(ctx context.Context, issuer, subject string) (*models.UserIdentity, error) {
+	const op = "repository.postgres.UserIdentityRepository.GetByIssuerSubject"
+
+	query := `SELECT issuer, subject, user_uuid, created_at
+			  FROM user_identities
+			  WHERE issuer = $1 AND subject = $2`
+	var identity models.UserIdentity
+	err := conn(ctx, r.db).QueryRow(ctx, query, issuer, subject).Scan(
+		&identity.Issuer, &identity.Subject, &identity.UserUUID, &identity.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &identity, nil
+}
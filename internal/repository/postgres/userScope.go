@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+	"strings"
+)
+
+type UserScopeRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewUserScopeRepository(db *pgxpool.Pool) *UserScopeRepository {
+	return &UserScopeRepository{db: db}
+}
+
+func (r *UserScopeRepository) GetByUser(ctx context.Context, userUUID uuid.UUID) ([]*models.UserScope, error) {
+	const op = "repository.postgres.UserScopeRepository.GetByUser"
+
+	query := `SELECT uuid, user_uuid, group_uuid, location_uuid, COALESCE(group_prefix, '')
+			  FROM user_scopes
+			  WHERE user_uuid = $1`
+	rows, err := conn(ctx, r.db).Query(ctx, query, userUUID)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var scopes []*models.UserScope
+	for rows.Next() {
+		var scope models.UserScope
+		err := rows.Scan(&scope.UUID, &scope.UserUUID, &scope.GroupUUID, &scope.LocationUUID, &scope.GroupPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		scopes = append(scopes, &scope)
+	}
+
+	return scopes, rows.Err()
+}
+
+func (r *UserScopeRepository) Replace(ctx context.Context, userUUID uuid.UUID, scopes []*models.UserScope) error {
+	const op = "repository.postgres.UserScopeRepository.Replace"
+
+	_, err := conn(ctx, r.db).Exec(ctx, `DELETE FROM user_scopes WHERE user_uuid = $1`, userUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO user_scopes (uuid, user_uuid, group_uuid, location_uuid, group_prefix)
+			  VALUES ($1, $2, $3, $4, NULLIF($5, ''))`
+	for _, scope := range scopes {
+		_, err := conn(ctx, r.db).Exec(ctx, query, scope.UUID, userUUID, scope.GroupUUID, scope.LocationUUID, scope.GroupPrefix)
+		if err != nil {
+			if strings.Contains(err.Error(), "23503") {
+				return fmt.Errorf("%s: %w", op, repository.ErrNotExist)
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
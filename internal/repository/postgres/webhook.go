@@ -0,0 +1,132 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+)
+
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+var webhookSelectStatement = `
+	SELECT uuid, url, secret, kind, resource, owner, created_at
+	FROM webhooks`
+
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	const op = "repository.postgres.WebhookRepository.Create"
+
+	query := `INSERT INTO webhooks (uuid, url, secret, kind, resource, owner)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, webhook.UUID, webhook.URL, webhook.Secret,
+		webhook.Kind, webhook.Resource, webhook.Owner,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetByUUID(ctx context.Context, uuid uuid.UUID) (*models.Webhook, error) {
+	const op = "repository.postgres.WebhookRepository.GetByUUID"
+
+	query := webhookSelectStatement + ` WHERE uuid = $1`
+	row := conn(ctx, r.db).QueryRow(ctx, query, uuid)
+
+	var webhook models.Webhook
+	err := row.Scan(
+		&webhook.UUID, &webhook.URL, &webhook.Secret, &webhook.Kind,
+		&webhook.Resource, &webhook.Owner, &webhook.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) GetByOwner(ctx context.Context, owner string) ([]*models.Webhook, error) {
+	const op = "repository.postgres.WebhookRepository.GetByOwner"
+
+	query := webhookSelectStatement + ` WHERE owner = $1 ORDER BY created_at`
+	webhooks, err := r.query(ctx, query, owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// GetByResources returns webhooks subscribed to any of given groups, teachers or rooms
+func (r *WebhookRepository) GetByResources(ctx context.Context, groups, teachers, rooms []string) ([]*models.Webhook, error) {
+	const op = "repository.postgres.WebhookRepository.GetByResources"
+
+	query := webhookSelectStatement + `
+		WHERE (kind = $1 AND resource = ANY($2))
+			OR (kind = $3 AND resource = ANY($4))
+			OR (kind = $5 AND resource = ANY($6))`
+	webhooks, err := r.query(
+		ctx, query, models.ResourceGroup, groups,
+		models.ResourceTeacher, teachers, models.ResourceRoom, rooms,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) Delete(ctx context.Context, uuid uuid.UUID, owner string) error {
+	const op = "repository.postgres.WebhookRepository.Delete"
+
+	query := `DELETE FROM webhooks WHERE uuid = $1 AND owner = $2`
+	result, err := conn(ctx, r.db).Exec(ctx, query, uuid, owner)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...any) ([]*models.Webhook, error) {
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	defer rows.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		err := rows.Scan(
+			&webhook.UUID, &webhook.URL, &webhook.Secret, &webhook.Kind,
+			&webhook.Resource, &webhook.Owner, &webhook.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, &webhook)
+	}
+
+	return webhooks, rows.Err()
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+type WebhookDeliveryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookDeliveryRepository(db *pgxpool.Pool) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *models.WebhookDelivery) error {
+	const op = "repository.postgres.WebhookDeliveryRepository.Create"
+
+	query := `INSERT INTO webhook_deliveries (uuid, webhook_uuid, event, payload, status, next_attempt_at)
+			  VALUES ($1, $2, $3, $4, $5, $6)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, delivery.UUID, delivery.WebhookUUID, delivery.Event,
+		delivery.Payload, delivery.Status, delivery.NextAttemptAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// GetByWebhookUUID returns last deliveries of webhook with their attempts
+func (r *WebhookDeliveryRepository) GetByWebhookUUID(ctx context.Context, webhookUUID uuid.UUID, limit int) ([]*models.WebhookDelivery, error) {
+	const op = "repository.postgres.WebhookDeliveryRepository.GetByWebhookUUID"
+
+	query := `SELECT uuid, webhook_uuid, event, payload, status, attempts, next_attempt_at,
+				last_status_code, last_error, created_at, delivered_at
+			  FROM webhook_deliveries
+			  WHERE webhook_uuid = $1
+			  ORDER BY created_at DESC
+			  LIMIT $2`
+	rows, err := conn(ctx, r.db).Query(ctx, query, webhookUUID, limit)
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deliveries := []*models.WebhookDelivery{}
+	byUUID := make(map[uuid.UUID]*models.WebhookDelivery)
+	var UUIDs []uuid.UUID
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.UUID, &delivery.WebhookUUID, &delivery.Event, &delivery.Payload,
+			&delivery.Status, &delivery.Attempts, &delivery.NextAttemptAt,
+			&delivery.LastStatusCode, &delivery.LastError, &delivery.CreatedAt, &delivery.DeliveredAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+		byUUID[delivery.UUID] = &delivery
+		UUIDs = append(UUIDs, delivery.UUID)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(UUIDs) == 0 {
+		return deliveries, nil
+	}
+
+	// Adding attempts to deliveries
+	query = `SELECT uuid, delivery_uuid, status_code, error, attempted_at
+			 FROM webhook_attempts
+			 WHERE delivery_uuid = ANY($1)
+			 ORDER BY attempted_at`
+	attemptRows, err := conn(ctx, r.db).Query(ctx, query, UUIDs)
+	defer attemptRows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for attemptRows.Next() {
+		var attempt models.WebhookAttempt
+		err := attemptRows.Scan(
+			&attempt.UUID, &attempt.DeliveryUUID, &attempt.StatusCode,
+			&attempt.Error, &attempt.AttemptedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		delivery := byUUID[attempt.DeliveryUUID]
+		delivery.History = append(delivery.History, &attempt)
+	}
+	if err = attemptRows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	const op = "repository.postgres.WebhookDeliveryRepository.ClaimDue"
+
+	query := `UPDATE webhook_deliveries
+			  SET next_attempt_at = now() + make_interval(secs => $3)
+			  FROM webhooks
+			  WHERE webhook_deliveries.webhook_uuid = webhooks.uuid
+				AND webhook_deliveries.uuid IN (
+					SELECT uuid
+					FROM webhook_deliveries
+					WHERE status = $1 AND next_attempt_at <= now()
+					ORDER BY next_attempt_at
+					LIMIT $2
+					FOR UPDATE SKIP LOCKED
+				)
+			  RETURNING webhook_deliveries.uuid, webhook_deliveries.webhook_uuid, webhooks.url, webhooks.secret,
+				webhook_deliveries.event, webhook_deliveries.payload, webhook_deliveries.status,
+				webhook_deliveries.attempts, webhook_deliveries.next_attempt_at, webhook_deliveries.created_at`
+	rows, err := conn(ctx, r.db).Query(ctx, query, models.DeliveryPending, limit, lease.Seconds())
+	defer rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var deliveries []*models.WebhookDelivery
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		err := rows.Scan(
+			&delivery.UUID, &delivery.WebhookUUID, &delivery.URL, &delivery.Secret,
+			&delivery.Event, &delivery.Payload, &delivery.Status,
+			&delivery.Attempts, &delivery.NextAttemptAt, &delivery.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		deliveries = append(deliveries, &delivery)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *models.WebhookDelivery) error {
+	const op = "repository.postgres.WebhookDeliveryRepository.Update"
+
+	query := `UPDATE webhook_deliveries
+			  SET status = $2, attempts = $3, next_attempt_at = $4,
+				last_status_code = $5, last_error = $6, delivered_at = $7
+			  WHERE uuid = $1`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, delivery.UUID, delivery.Status, delivery.Attempts, delivery.NextAttemptAt,
+		delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) CreateAttempt(ctx context.Context, attempt *models.WebhookAttempt) error {
+	const op = "repository.postgres.WebhookDeliveryRepository.CreateAttempt"
+
+	query := `INSERT INTO webhook_attempts (uuid, delivery_uuid, status_code, error, attempted_at)
+			  VALUES ($1, $2, $3, $4, $5)`
+	_, err := conn(ctx, r.db).Exec(
+		ctx, query, attempt.UUID, attempt.DeliveryUUID,
+		attempt.StatusCode, attempt.Error, attempt.AttemptedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"time"
+)
+
+// bellsKey is key of cached bells, bells carry names of their locations,
+// so renamed or deleted location drops them too
+const bellsKey = "bells:all"
+
+// CachedBellScheduleRepository caches bells of wrapped repository, they are read by every schedule query.
+// Writes drop cached bells after they are done
+type CachedBellScheduleRepository struct {
+	interfaces.BellScheduleRepository
+	cache interfaces.Cache
+	ttl   time.Duration
+}
+
+func NewCachedBellScheduleRepository(repo interfaces.BellScheduleRepository, cache interfaces.Cache, ttl time.Duration) *CachedBellScheduleRepository {
+	return &CachedBellScheduleRepository{BellScheduleRepository: repo, cache: cache, ttl: ttl}
+}
+
+func (r *CachedBellScheduleRepository) Get(ctx context.Context) ([]*models.BellSchedule, error) {
+	if r.ttl <= 0 {
+		return r.BellScheduleRepository.Get(ctx)
+	}
+
+	if data, err := r.cache.Get(ctx, bellsKey); err == nil {
+		var bells []*models.BellSchedule
+		if json.Unmarshal([]byte(data), &bells) == nil {
+			return bells, nil
+		}
+	}
+
+	bells, err := r.BellScheduleRepository.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(bells); err == nil {
+		_ = r.cache.Set(ctx, bellsKey, string(data), r.ttl)
+	}
+
+	return bells, nil
+}
+
+func (r *CachedBellScheduleRepository) Create(ctx context.Context, bell *models.BellSchedule) error {
+	if err := r.BellScheduleRepository.Create(ctx, bell); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedBellScheduleRepository) Update(ctx context.Context, bell *models.BellSchedule) error {
+	if err := r.BellScheduleRepository.Update(ctx, bell); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+func (r *CachedBellScheduleRepository) Delete(ctx context.Context, uuid uuid.UUID) error {
+	if err := r.BellScheduleRepository.Delete(ctx, uuid); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx)
+	return nil
+}
+
+// invalidate drops cached bells, error is ignored as they expire by TTL
+func (r *CachedBellScheduleRepository) invalidate(ctx context.Context) {
+	_ = r.cache.Delete(ctx, bellsKey)
+}
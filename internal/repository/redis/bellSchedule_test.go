@@ -0,0 +1,70 @@
+package redis
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// countingBellScheduleRepository keeps bells in memory and counts reads
+type countingBellScheduleRepository struct {
+	interfaces.BellScheduleRepository
+	bells   []*models.BellSchedule
+	queries int
+}
+
+func (r *countingBellScheduleRepository) Get(_ context.Context) ([]*models.BellSchedule, error) {
+	r.queries++
+	return r.bells, nil
+}
+
+func (r *countingBellScheduleRepository) Create(_ context.Context, bell *models.BellSchedule) error {
+	r.bells = append(r.bells, bell)
+	return nil
+}
+
+func TestCachedBellScheduleRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingBellScheduleRepository{bells: []*models.BellSchedule{{UUID: uuid.New(), PairNum: 1}}}
+	cache := mapCache{}
+	cached := NewCachedBellScheduleRepository(repo, cache, time.Hour)
+
+	get := func(want int) {
+		t.Helper()
+		bells, err := cached.Get(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bells) != want {
+			t.Fatalf("expected %v bells, got %v", want, len(bells))
+		}
+	}
+
+	get(1)
+	get(1)
+	if repo.queries != 1 {
+		t.Fatalf("expected bells to be cached, got %v queries", repo.queries)
+	}
+
+	// Write drops cached bells
+	if err := cached.Create(ctx, &models.BellSchedule{UUID: uuid.New(), PairNum: 2}); err != nil {
+		t.Fatal(err)
+	}
+	get(2)
+	if repo.queries != 2 {
+		t.Fatalf("expected bells to be read again, got %v queries", repo.queries)
+	}
+
+	// Renamed location drops cached bells too
+	schedules := NewCachedScheduleRepository(nil, cache, nil, nil, nil, nil, nil, time.Hour, time.Minute)
+	if err := schedules.InvalidateResource(ctx, models.ResourceLocation, []string{"Автозаводская"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	get(2)
+	if repo.queries != 3 {
+		t.Fatalf("expected bells to be read again, got %v queries", repo.queries)
+	}
+}
@@ -0,0 +1,40 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+type TokenDenylist struct {
+	client *redis.Client
+}
+
+func NewTokenDenylist(client *redis.Client) *TokenDenylist {
+	return &TokenDenylist{client: client}
+}
+
+func denylistKey(jti string) string {
+	return "jwt:denylist:" + jti
+}
+
+// Deny keeps jti for ttl, it is enough to outlive token
+func (d *TokenDenylist) Deny(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return d.client.Set(ctx, denylistKey(jti), "1", ttl).Err()
+}
+
+func (d *TokenDenylist) IsDenied(ctx context.Context, jti string) (bool, error) {
+	err := d.client.Get(ctx, denylistKey(jti)).Err()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"github.com/redis/go-redis/v9"
+	"time"
+)
+
+type OIDCStateStore struct {
+	client *redis.Client
+}
+
+func NewOIDCStateStore(client *redis.Client) *OIDCStateStore {
+	return &OIDCStateStore{client: client}
+}
+
+func oidcStateKey(state string) string {
+	return "oidc:state:" + state
+}
+
+func (s *OIDCStateStore) Save(ctx context.Context, state, nonce string, ttl time.Duration) error {
+	return s.client.Set(ctx, oidcStateKey(state), nonce, ttl).Err()
+}
+
+func (s *OIDCStateStore) Pop(ctx context.Context, state string) (string, error) {
+	nonce, err := s.client.GetDel(ctx, oidcStateKey(state)).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return nonce, nil
+}
@@ -0,0 +1,99 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"github.com/redis/go-redis/v9"
+	"raspyx/internal/domain/models"
+	"strconv"
+	"time"
+)
+
+// gcra is generic cell rate algorithm: key holds theoretical arrival time of the next request.
+// Time of redis is used, so every replica of app shares the same buckets.
+// Returns allowed (0 or 1), remaining requests, retry after and reset after in seconds
+var gcra = redis.NewScript(`
+redis.replicate_commands()
+
+local key = KEYS[1]
+local burst = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+
+local emission_interval = 1 / rate
+local burst_offset = emission_interval * burst
+
+-- Seconds since 2017 keep precision of floats
+local time = redis.call("TIME")
+local now = (tonumber(time[1]) - 1483228800) + tonumber(time[2]) / 1000000
+
+local tat = tonumber(redis.call("GET", key))
+if not tat or tat < now then
+	tat = now
+end
+
+local new_tat = tat + emission_interval
+local diff = now - (new_tat - burst_offset)
+
+if diff < 0 then
+	return {0, 0, tostring(-diff), tostring(tat - now)}
+end
+
+local reset_after = new_tat - now
+redis.call("SET", key, new_tat, "EX", math.ceil(reset_after))
+
+return {1, math.floor(diff / emission_interval), "0", tostring(reset_after)}
+`)
+
+type RateLimiter struct {
+	client *redis.Client
+}
+
+func NewRateLimiter(client *redis.Client) *RateLimiter {
+	return &RateLimiter{client: client}
+}
+
+func rateLimitKey(policy, identity string) string {
+	return "ratelimit:" + policy + ":" + identity
+}
+
+func (l *RateLimiter) Allow(ctx context.Context, policy models.RateLimitPolicy, identity string) (*models.RateLimitResult, error) {
+	const op = "repository.redis.RateLimiter.Allow"
+
+	values, err := gcra.Run(ctx, l.client, []string{rateLimitKey(policy.Name, identity)}, policy.Burst, policy.Limit).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if len(values) != 4 {
+		return nil, fmt.Errorf("%s: unexpected result %v", op, values)
+	}
+
+	allowed, _ := values[0].(int64)
+	remaining, _ := values[1].(int64)
+	retryAfter, err := parseSeconds(values[2])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	resetAfter, err := parseSeconds(values[3])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &models.RateLimitResult{
+		Allowed:    allowed == 1,
+		Remaining:  int(remaining),
+		ResetAfter: resetAfter,
+		RetryAfter: retryAfter,
+	}, nil
+}
+
+func parseSeconds(v any) (time.Duration, error) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected value %v", v)
+	}
+	seconds, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
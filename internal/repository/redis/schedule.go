@@ -0,0 +1,378 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/repository"
+	"strings"
+	"time"
+)
+
+// CachedScheduleRepository caches every schedule query of wrapped repository.
+// Each query depends on tags (group, teacher, room, subject, location or all pairs),
+// every tag has generation kept in cache and generation is part of query key.
+// Invalidate drops generations of tags, so queries cached before it are never read again
+type CachedScheduleRepository struct {
+	interfaces.ScheduleRepository
+	cache        interfaces.Cache
+	repoGroup    interfaces.GroupRepository
+	repoTeacher  interfaces.TeacherRepository
+	repoRoom     interfaces.RoomRepository
+	repoSubject  interfaces.SubjectRepository
+	repoLocation interfaces.LocationRepository
+	// TTL of queries by group, teacher, room, subject and location
+	ttl time.Duration
+	// TTL of query of all pairs
+	allTTL time.Duration
+}
+
+func NewCachedScheduleRepository(
+	repo interfaces.ScheduleRepository,
+	cache interfaces.Cache,
+	repoGroup interfaces.GroupRepository,
+	repoTeacher interfaces.TeacherRepository,
+	repoRoom interfaces.RoomRepository,
+	repoSubject interfaces.SubjectRepository,
+	repoLocation interfaces.LocationRepository,
+	ttl, allTTL time.Duration,
+) *CachedScheduleRepository {
+	return &CachedScheduleRepository{
+		ScheduleRepository: repo,
+		cache:              cache,
+		repoGroup:          repoGroup,
+		repoTeacher:        repoTeacher,
+		repoRoom:           repoRoom,
+		repoSubject:        repoSubject,
+		repoLocation:       repoLocation,
+		ttl:                ttl,
+		allTTL:             allTTL,
+	}
+}
+
+const tagAll = "all"
+
+func scheduleTagKey(tag string) string {
+	return "schedule:tag:" + tag
+}
+
+func groupTag(number string) string {
+	return "group:" + number
+}
+
+func teacherTag(fullName string) string {
+	return "teacher:" + fullName
+}
+
+// teacherFullName is built the same way as teachers of ScheduleData
+func teacherFullName(firstName, secondName, middleName string) string {
+	return strings.TrimSpace(secondName + " " + firstName + " " + middleName)
+}
+
+func roomTag(number string) string {
+	return "room:" + number
+}
+
+func subjectTag(name string) string {
+	return "subject:" + name
+}
+
+func locationTag(name string) string {
+	return "location:" + name
+}
+
+// resourceTags are tags of resource kinds
+var resourceTags = map[string]func(string) string{
+	models.ResourceGroup:    groupTag,
+	models.ResourceTeacher:  teacherTag,
+	models.ResourceRoom:     roomTag,
+	models.ResourceSubject:  subjectTag,
+	models.ResourceLocation: locationTag,
+}
+
+func sessionFlag(isSession bool) string {
+	if isSession {
+		return "1"
+	}
+	return "0"
+}
+
+// generation returns generation of tag, new one is saved if tag has none
+func (r *CachedScheduleRepository) generation(ctx context.Context, tag string) (string, error) {
+	gen, err := r.cache.Get(ctx, scheduleTagKey(tag))
+	if err == nil {
+		return gen, nil
+	}
+
+	// Generation outlives queries cached with it
+	gen = uuid.NewString()
+	err = r.cache.Set(ctx, scheduleTagKey(tag), gen, max(r.ttl, r.allTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return gen, nil
+}
+
+// cached returns result of query from cache, on miss query is loaded and saved.
+// Generation is taken before loading, so result loaded before invalidation is saved under old generation
+func (r *CachedScheduleRepository) cached(
+	ctx context.Context, tag, query string, ttl time.Duration,
+	load func() ([]*models.ScheduleData, error),
+) ([]*models.ScheduleData, error) {
+	if ttl <= 0 {
+		return load()
+	}
+
+	gen, err := r.generation(ctx, tag)
+	if err != nil {
+		// Cache is unavailable, schedule is still served from db
+		return load()
+	}
+	key := "schedule:" + query + ":" + gen
+
+	if data, err := r.cache.Get(ctx, key); err == nil {
+		var schedules []*models.ScheduleData
+		if json.Unmarshal([]byte(data), &schedules) == nil {
+			return schedules, nil
+		}
+	}
+
+	schedules, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err := json.Marshal(schedules); err == nil {
+		_ = r.cache.Set(ctx, key, string(data), ttl)
+	}
+
+	return schedules, nil
+}
+
+func (r *CachedScheduleRepository) Get(ctx context.Context) ([]*models.ScheduleData, error) {
+	return r.cached(ctx, tagAll, tagAll, r.allTTL, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.Get(ctx)
+	})
+}
+
+func (r *CachedScheduleRepository) GetByTeacher(ctx context.Context, firstName, secondName, middleName string, isSession bool) ([]*models.ScheduleData, error) {
+	tag := teacherTag(teacherFullName(firstName, secondName, middleName))
+	return r.cached(ctx, tag, tag+":"+sessionFlag(isSession), r.ttl, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByTeacher(ctx, firstName, secondName, middleName, isSession)
+	})
+}
+
+func (r *CachedScheduleRepository) GetByTeacherUUID(ctx context.Context, teacherUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	load := func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByTeacherUUID(ctx, teacherUUID, isSession)
+	}
+
+	teacher, err := r.repoTeacher.GetByUUID(ctx, teacherUUID)
+	if err != nil {
+		return load()
+	}
+
+	tag := teacherTag(teacherFullName(teacher.FirstName, teacher.SecondName, teacher.MiddleName))
+	return r.cached(ctx, tag, "teacher:"+teacherUUID.String()+":"+sessionFlag(isSession), r.ttl, load)
+}
+
+func (r *CachedScheduleRepository) GetByGroup(ctx context.Context, groupNumber string, isSession bool) ([]*models.ScheduleData, error) {
+	tag := groupTag(groupNumber)
+	return r.cached(ctx, tag, tag+":"+sessionFlag(isSession), r.ttl, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByGroup(ctx, groupNumber, isSession)
+	})
+}
+
+func (r *CachedScheduleRepository) GetByGroupUUID(ctx context.Context, groupUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	load := func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByGroupUUID(ctx, groupUUID, isSession)
+	}
+
+	group, err := r.repoGroup.GetByUUID(ctx, groupUUID)
+	if err != nil {
+		return load()
+	}
+
+	return r.cached(ctx, groupTag(group.Number), "group:"+groupUUID.String()+":"+sessionFlag(isSession), r.ttl, load)
+}
+
+func (r *CachedScheduleRepository) GetByRoom(ctx context.Context, roomNumber string, isSession bool) ([]*models.ScheduleData, error) {
+	tag := roomTag(roomNumber)
+	return r.cached(ctx, tag, tag+":"+sessionFlag(isSession), r.ttl, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByRoom(ctx, roomNumber, isSession)
+	})
+}
+
+func (r *CachedScheduleRepository) GetByRoomUUID(ctx context.Context, roomUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	load := func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByRoomUUID(ctx, roomUUID, isSession)
+	}
+
+	room, err := r.repoRoom.GetByUUID(ctx, roomUUID)
+	if err != nil {
+		return load()
+	}
+
+	return r.cached(ctx, roomTag(room.Number), "room:"+roomUUID.String()+":"+sessionFlag(isSession), r.ttl, load)
+}
+
+func (r *CachedScheduleRepository) GetBySubject(ctx context.Context, subjectName string, isSession bool) ([]*models.ScheduleData, error) {
+	tag := subjectTag(subjectName)
+	return r.cached(ctx, tag, tag+":"+sessionFlag(isSession), r.ttl, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetBySubject(ctx, subjectName, isSession)
+	})
+}
+
+func (r *CachedScheduleRepository) GetBySubjectUUID(ctx context.Context, subjectUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	load := func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetBySubjectUUID(ctx, subjectUUID, isSession)
+	}
+
+	subject, err := r.repoSubject.GetByUUID(ctx, subjectUUID)
+	if err != nil {
+		return load()
+	}
+
+	return r.cached(ctx, subjectTag(subject.Name), "subject:"+subjectUUID.String()+":"+sessionFlag(isSession), r.ttl, load)
+}
+
+func (r *CachedScheduleRepository) GetByLocation(ctx context.Context, locationName string, isSession bool) ([]*models.ScheduleData, error) {
+	tag := locationTag(locationName)
+	return r.cached(ctx, tag, tag+":"+sessionFlag(isSession), r.ttl, func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByLocation(ctx, locationName, isSession)
+	})
+}
+
+func (r *CachedScheduleRepository) GetByLocationUUID(ctx context.Context, locationUUID uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	load := func() ([]*models.ScheduleData, error) {
+		return r.ScheduleRepository.GetByLocationUUID(ctx, locationUUID, isSession)
+	}
+
+	location, err := r.repoLocation.GetByUUID(ctx, locationUUID)
+	if err != nil {
+		return load()
+	}
+
+	return r.cached(ctx, locationTag(location.Name), "location:"+locationUUID.String()+":"+sessionFlag(isSession), r.ttl, load)
+}
+
+// Invalidate drops cached queries that may contain given pairs
+func (r *CachedScheduleRepository) Invalidate(ctx context.Context, schedules ...*models.ScheduleData) error {
+	const op = "repository.redis.CachedScheduleRepository.Invalidate"
+
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	tags := map[string]struct{}{tagAll: {}}
+	add := func(value string, tag func(string) string) {
+		if value != "" {
+			tags[tag(value)] = struct{}{}
+		}
+	}
+	for _, schedule := range schedules {
+		if schedule == nil {
+			continue
+		}
+		add(schedule.Group, groupTag)
+		add(schedule.Subject, subjectTag)
+		add(schedule.Location, locationTag)
+		for _, teacher := range schedule.Teachers {
+			add(teacher, teacherTag)
+		}
+		for _, room := range schedule.Rooms {
+			add(room, roomTag)
+		}
+	}
+
+	var errDelete error
+	for tag := range tags {
+		if err := r.cache.Delete(ctx, scheduleTagKey(tag)); err != nil && errDelete == nil {
+			errDelete = err
+		}
+	}
+	if errDelete != nil {
+		return fmt.Errorf("%s: %w", op, errDelete)
+	}
+
+	return nil
+}
+
+// SchedulesOf returns regular and session pairs of resource from db, they are not cached
+func (r *CachedScheduleRepository) SchedulesOf(ctx context.Context, kind string, uuid uuid.UUID) ([]*models.ScheduleData, error) {
+	const op = "repository.redis.CachedScheduleRepository.SchedulesOf"
+
+	fetch := map[string]func(isSession bool) ([]*models.ScheduleData, error){
+		models.ResourceGroup: func(isSession bool) ([]*models.ScheduleData, error) {
+			return r.ScheduleRepository.GetByGroupUUID(ctx, uuid, isSession)
+		},
+		models.ResourceTeacher: func(isSession bool) ([]*models.ScheduleData, error) {
+			return r.ScheduleRepository.GetByTeacherUUID(ctx, uuid, isSession)
+		},
+		models.ResourceRoom: func(isSession bool) ([]*models.ScheduleData, error) {
+			return r.ScheduleRepository.GetByRoomUUID(ctx, uuid, isSession)
+		},
+		models.ResourceSubject: func(isSession bool) ([]*models.ScheduleData, error) {
+			return r.ScheduleRepository.GetBySubjectUUID(ctx, uuid, isSession)
+		},
+		models.ResourceLocation: func(isSession bool) ([]*models.ScheduleData, error) {
+			return r.ScheduleRepository.GetByLocationUUID(ctx, uuid, isSession)
+		},
+	}[kind]
+	if fetch == nil {
+		return nil, fmt.Errorf("%s: unknown kind %q", op, kind)
+	}
+
+	var schedules []*models.ScheduleData
+	for _, isSession := range []bool{false, true} {
+		s, err := fetch(isSession)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		schedules = append(schedules, s...)
+	}
+
+	return schedules, nil
+}
+
+// InvalidateResource drops cached queries of resource with given names, e.g. old and new number of renamed group,
+// and queries containing its pairs, as names of resource are part of them
+func (r *CachedScheduleRepository) InvalidateResource(ctx context.Context, kind string, names []string, schedules []*models.ScheduleData) error {
+	const op = "repository.redis.CachedScheduleRepository.InvalidateResource"
+
+	tag := resourceTags[kind]
+	if tag == nil {
+		return fmt.Errorf("%s: unknown kind %q", op, kind)
+	}
+
+	keys := []string{scheduleTagKey(tagAll)}
+	for _, name := range names {
+		if name != "" {
+			keys = append(keys, scheduleTagKey(tag(name)))
+		}
+	}
+	// Bells carry names of their locations
+	if kind == models.ResourceLocation {
+		keys = append(keys, bellsKey)
+	}
+
+	var errDelete error
+	for _, key := range keys {
+		if err := r.cache.Delete(ctx, key); err != nil && errDelete == nil {
+			errDelete = err
+		}
+	}
+	if errDelete != nil {
+		return fmt.Errorf("%s: %w", op, errDelete)
+	}
+
+	return r.Invalidate(ctx, schedules...)
+}
@@ -0,0 +1,137 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"testing"
+	"time"
+)
+
+// mapCache keeps values in memory, expiration is ignored
+type mapCache map[string]string
+
+func (c mapCache) Set(_ context.Context, key string, value string, _ time.Duration) error {
+	c[key] = value
+	return nil
+}
+
+func (c mapCache) Get(_ context.Context, key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", errors.New("cache miss")
+	}
+	return value, nil
+}
+
+func (c mapCache) Delete(_ context.Context, key string) error {
+	delete(c, key)
+	return nil
+}
+
+// countingScheduleRepository returns one pair of group and counts queries
+type countingScheduleRepository struct {
+	interfaces.ScheduleRepository
+	queries int
+}
+
+func (r *countingScheduleRepository) GetByGroup(_ context.Context, groupNumber string, _ bool) ([]*models.ScheduleData, error) {
+	r.queries++
+	return []*models.ScheduleData{{Group: groupNumber, Teachers: []string{"Фамилия Имя"}, Subject: "Иностранный язык"}}, nil
+}
+
+func (r *countingScheduleRepository) GetByTeacher(_ context.Context, _, _, _ string, _ bool) ([]*models.ScheduleData, error) {
+	r.queries++
+	return []*models.ScheduleData{{Group: "221-352", Teachers: []string{"Фамилия Имя"}}}, nil
+}
+
+func TestCachedScheduleRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingScheduleRepository{}
+	cached := NewCachedScheduleRepository(repo, mapCache{}, nil, nil, nil, nil, nil, time.Hour, time.Minute)
+
+	get := func(group string) {
+		t.Helper()
+		schedules, err := cached.GetByGroup(ctx, group, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(schedules) != 1 || schedules[0].Group != group {
+			t.Fatalf("unexpected schedule of %v: %+v", group, schedules)
+		}
+	}
+
+	get("221-352")
+	get("221-352")
+	if repo.queries != 1 {
+		t.Fatalf("expected second query to be cached, got %v queries", repo.queries)
+	}
+
+	// Change of other group keeps query
+	_ = cached.Invalidate(ctx, &models.ScheduleData{Group: "221-351"})
+	get("221-352")
+	if repo.queries != 1 {
+		t.Fatalf("expected query to stay cached, got %v queries", repo.queries)
+	}
+
+	_ = cached.Invalidate(ctx, &models.ScheduleData{Group: "221-352"})
+	get("221-352")
+	if repo.queries != 2 {
+		t.Fatalf("expected query to be dropped, got %v queries", repo.queries)
+	}
+
+	// Teacher query is dropped by change of any pair of teacher
+	for range 2 {
+		if _, err := cached.GetByTeacher(ctx, "Имя", "Фамилия", "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.queries != 3 {
+		t.Fatalf("expected teacher query to be cached, got %v queries", repo.queries)
+	}
+	_ = cached.Invalidate(ctx, &models.ScheduleData{Group: "221-351", Teachers: []string{"Фамилия Имя"}})
+	if _, err := cached.GetByTeacher(ctx, "Имя", "Фамилия", "", false); err != nil {
+		t.Fatal(err)
+	}
+	if repo.queries != 4 {
+		t.Fatalf("expected teacher query to be dropped, got %v queries", repo.queries)
+	}
+}
+
+func TestCachedScheduleRepository_InvalidateResource(t *testing.T) {
+	ctx := context.Background()
+	repo := &countingScheduleRepository{}
+	cached := NewCachedScheduleRepository(repo, mapCache{}, nil, nil, nil, nil, nil, time.Hour, time.Minute)
+
+	for range 2 {
+		if _, err := cached.GetByGroup(ctx, "221-352", false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cached.GetByTeacher(ctx, "Имя", "Фамилия", "", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.queries != 2 {
+		t.Fatalf("expected queries to be cached, got %v queries", repo.queries)
+	}
+
+	// Renamed group drops query by old number and queries containing its pairs
+	pairs := []*models.ScheduleData{{Group: "221-352", Teachers: []string{"Фамилия Имя"}}}
+	if err := cached.InvalidateResource(ctx, models.ResourceGroup, []string{"221-352", "221-353"}, pairs); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.GetByGroup(ctx, "221-352", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cached.GetByTeacher(ctx, "Имя", "Фамилия", "", false); err != nil {
+		t.Fatal(err)
+	}
+	if repo.queries != 4 {
+		t.Fatalf("expected queries to be dropped, got %v queries", repo.queries)
+	}
+
+	if err := cached.InvalidateResource(ctx, "unknown", nil, nil); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"raspyx/internal/repository"
+	"slices"
+	"strings"
+	"time"
+)
+
+// apiKeyTouchInterval limits writes of last usage time of keys used on every request
+const apiKeyTouchInterval = time.Minute
+
+type APIKeyUseCase struct {
+	repo interfaces.APIKeyRepository
+	svc  services.APIKeyService
+}
+
+func NewAPIKeyUseCase(repo interfaces.APIKeyRepository, svc services.APIKeyService) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		repo: repo,
+		svc:  svc,
+	}
+}
+
+// Create issues new key created by current user, secret of key is returned only here
+func (uc *APIKeyUseCase) Create(ctx context.Context, keyDTO *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	const op = "usecase.apiKey.Create"
+
+	if keyDTO.ExpiresIn < 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	// Duplicated scopes are dropped
+	scopes := slices.Clone(keyDTO.Scopes)
+	slices.Sort(scopes)
+	key := &models.APIKey{
+		Name:      strings.TrimSpace(keyDTO.Name),
+		Scopes:    slices.Compact(scopes),
+		CreatedAt: time.Now(),
+	}
+
+	// Validation name and scopes
+	if valid := uc.svc.Validate(key); !valid {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	if keyDTO.ExpiresIn > 0 {
+		expiresAt := key.CreatedAt.AddDate(0, 0, keyDTO.ExpiresIn)
+		key.ExpiresAt = &expiresAt
+	}
+	if userUUID := contextUserUUID(ctx); userUUID != uuid.Nil {
+		key.CreatedBy = &userUUID
+	}
+
+	// Generating new uuid
+	newUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+	key.UUID = newUUID
+
+	secret, prefix, err := uc.svc.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	key.Prefix = prefix
+	key.KeyHash = uc.svc.HashKey(secret)
+
+	// Adding key to db
+	err = uc.repo.Create(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.CreateAPIKeyResponse{
+		UUID:      key.UUID,
+		Key:       secret,
+		Prefix:    key.Prefix,
+		ExpiresAt: key.ExpiresAt,
+	}, nil
+}
+
+func (uc *APIKeyUseCase) Get(ctx context.Context) ([]*models.APIKey, error) {
+	const op = "usecase.apiKey.Get"
+
+	keys, err := uc.repo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (uc *APIKeyUseCase) Revoke(ctx context.Context, UUID string) error {
+	const op = "usecase.apiKey.Revoke"
+
+	// Parsing key uuid
+	keyUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	err = uc.repo.Revoke(ctx, keyUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Authenticate returns active key by its secret and records its usage
+func (uc *APIKeyUseCase) Authenticate(ctx context.Context, secret string) (*models.APIKey, error) {
+	const op = "usecase.apiKey.Authenticate"
+
+	key, err := uc.repo.GetByHash(ctx, uc.svc.HashKey(secret))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	if !key.Active(now) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidAPIKey)
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > apiKeyTouchInterval {
+		err = uc.repo.TouchLastUsed(ctx, key.UUID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return key, nil
+}
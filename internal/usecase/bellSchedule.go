@@ -0,0 +1,181 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"strings"
+	"time"
+)
+
+type BellScheduleUseCase struct {
+	repo         interfaces.BellScheduleRepository
+	repoLocation interfaces.LocationRepository
+	svc          services.BellScheduleService
+}
+
+func NewBellScheduleUseCase(
+	repo interfaces.BellScheduleRepository,
+	repoLocation interfaces.LocationRepository,
+	svc services.BellScheduleService,
+) *BellScheduleUseCase {
+	return &BellScheduleUseCase{repo: repo, repoLocation: repoLocation, svc: svc}
+}
+
+func (uc *BellScheduleUseCase) bellDTOToBellModel(ctx context.Context, bellDTO *dto.BellScheduleRequest) (*models.BellSchedule, error) {
+	const op = "usecase.bellSchedule.bellDTOToBellModel"
+
+	bell := &models.BellSchedule{PairNum: bellDTO.PairNum}
+
+	// Adding locationUUID to model, bell without location is default for all locations
+	if location := strings.TrimSpace(bellDTO.Location); location != "" {
+		l, err := uc.repoLocation.GetByName(ctx, location)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		bell.LocationUUID = &l.UUID
+		bell.Location = l.Name
+	}
+
+	// Adding startDate to model
+	if bellDTO.StartDate != "" {
+		startDate, err := time.Parse(time.DateOnly, bellDTO.StartDate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid start date"))
+		}
+		bell.StartDate = &startDate
+	}
+
+	// Adding endDate to model
+	if bellDTO.EndDate != "" {
+		endDate, err := time.Parse(time.DateOnly, bellDTO.EndDate)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid end date"))
+		}
+		bell.EndDate = &endDate
+	}
+
+	// Adding startTime to model
+	startTime, err := time.Parse(time.TimeOnly, bellDTO.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("invalid start time"))
+	}
+	bell.StartTime = startTime
+
+	// Adding endTime to model
+	endTime, err := time.Parse(time.TimeOnly, bellDTO.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("invalid end time"))
+	}
+	bell.EndTime = endTime
+
+	// Validation pair num, times and dates
+	if valid := uc.svc.Validate(bell); !valid {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidBellSchedule)
+	}
+
+	return bell, nil
+}
+
+func (uc *BellScheduleUseCase) Create(ctx context.Context, bellDTO *dto.BellScheduleRequest) (*dto.CreateBellScheduleResponse, error) {
+	const op = "usecase.bellSchedule.Create"
+
+	// DTO to model
+	bell, err := uc.bellDTOToBellModel(ctx, bellDTO)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Generating new uuid
+	newUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+	bell.UUID = newUUID
+
+	// Adding bell to db
+	err = uc.repo.Create(ctx, bell)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.CreateBellScheduleResponse{UUID: bell.UUID}, nil
+}
+
+func (uc *BellScheduleUseCase) Get(ctx context.Context) ([]*models.BellSchedule, error) {
+	const op = "usecase.bellSchedule.Get"
+
+	// Getting all bells from db
+	bells, err := uc.repo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bells, nil
+}
+
+func (uc *BellScheduleUseCase) GetByUUID(ctx context.Context, UUID string) (*models.BellSchedule, error) {
+	const op = "usecase.bellSchedule.GetByUUID"
+
+	// Parsing bell uuid
+	bellUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Getting bell from db with given uuid
+	bell, err := uc.repo.GetByUUID(ctx, bellUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return bell, nil
+}
+
+func (uc *BellScheduleUseCase) Update(ctx context.Context, UUID string, bellDTO *dto.BellScheduleRequest) error {
+	const op = "usecase.bellSchedule.Update"
+
+	// Parsing bell uuid
+	bellUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// DTO to model
+	bell, err := uc.bellDTOToBellModel(ctx, bellDTO)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	bell.UUID = bellUUID
+
+	// Updating bell in db
+	err = uc.repo.Update(ctx, bell)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (uc *BellScheduleUseCase) Delete(ctx context.Context, UUID string) error {
+	const op = "usecase.bellSchedule.Delete"
+
+	// Parsing bell uuid
+	bellUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Deleting bell from db with given uuid
+	err = uc.repo.Delete(ctx, bellUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -3,9 +3,29 @@ package usecase
 import "errors"
 
 var (
-	ErrInvalidUUID    = errors.New("invalid uuid")
-	ErrGeneratingUUID = errors.New("failed to generate uuid")
-	ErrInvalidUser    = errors.New("invalid user")
-	ErrInvalidCreds   = errors.New("invalid creds")
-	ErrInvalidGroup   = errors.New("group is invalid")
+	ErrInvalidUUID         = errors.New("invalid uuid")
+	ErrGeneratingUUID      = errors.New("failed to generate uuid")
+	ErrInvalidUser         = errors.New("invalid user")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrInvalidCreds        = errors.New("invalid creds")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrInvalidAPIKey       = errors.New("api key is invalid")
+	ErrInvalidOIDCLogin    = errors.New("invalid oidc login")
+	ErrInvalidGroup        = errors.New("group is invalid")
+	ErrInvalidDate         = errors.New("invalid date")
+	ErrInvalidPeriod       = errors.New("invalid period")
+	ErrInvalidRange        = errors.New("invalid range")
+	ErrInvalidBellSchedule = errors.New("bell schedule is invalid")
+	ErrScheduleConflict    = errors.New("schedule conflict")
+	ErrOutOfScope          = errors.New("out of editing scopes")
+	ErrInvalidScope        = errors.New("invalid scope")
+	ErrInvalidWebhook      = errors.New("webhook is invalid")
+	ErrInvalidLimit        = errors.New("invalid limit")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrInvalidSort         = errors.New("invalid sort")
+	ErrInvalidFilter       = errors.New("invalid filter")
+	ErrInvalidSearchQuery  = errors.New("invalid search query")
+	ErrInvalidSearchType   = errors.New("invalid search type")
+	ErrParserBusy          = errors.New("parser is busy")
+	ErrParserNotStarted    = errors.New("parser is not started")
 )
@@ -11,12 +11,13 @@ import (
 )
 
 type GroupUseCase struct {
-	repo interfaces.GroupRepository
-	svc  services.GroupService
+	repo  interfaces.GroupRepository
+	svc   services.GroupService
+	cache interfaces.ScheduleCacheInvalidator
 }
 
-func NewGroupUseCase(repo interfaces.GroupRepository, svc services.GroupService) *GroupUseCase {
-	return &GroupUseCase{repo: repo, svc: svc}
+func NewGroupUseCase(repo interfaces.GroupRepository, svc services.GroupService, cache interfaces.ScheduleCacheInvalidator) *GroupUseCase {
+	return &GroupUseCase{repo: repo, svc: svc, cache: cache}
 }
 
 func (uc *GroupUseCase) Create(ctx context.Context, groupDTO *dto.CreateGroupRequest) (*dto.CreateGroupResponse, error) {
@@ -45,16 +46,21 @@ func (uc *GroupUseCase) Create(ctx context.Context, groupDTO *dto.CreateGroupReq
 	return &dto.CreateGroupResponse{UUID: group.UUID}, nil
 }
 
-func (uc *GroupUseCase) Get(ctx context.Context) ([]*models.Group, error) {
+func (uc *GroupUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetGroupsResponse, error) {
 	const op = "usecase.group.Get"
 
-	// Getting all groups from db
-	groups, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.GroupListFields, "number")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return groups, nil
+	// Getting page of groups from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetGroupsResponse{Groups: page.Items, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *GroupUseCase) GetByUUID(ctx context.Context, UUID string) (*models.Group, error) {
@@ -110,8 +116,16 @@ func (uc *GroupUseCase) Update(ctx context.Context, UUID string, groupDTO *dto.U
 		return fmt.Errorf("%s: %w", op, ErrInvalidGroup)
 	}
 
+	// Old number is needed to drop cached schedule of it
+	old, err := uc.repo.GetByUUID(ctx, groupUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Updating group in db with given group
-	err = uc.repo.Update(ctx, group)
+	err = writeResource(ctx, uc.cache, models.ResourceGroup, groupUUID, []string{old.Number, group.Number}, func() error {
+		return uc.repo.Update(ctx, group)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -128,8 +142,15 @@ func (uc *GroupUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	old, err := uc.repo.GetByUUID(ctx, groupUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Deleting groups from db with given uuid
-	err = uc.repo.Delete(ctx, groupUUID)
+	err = writeResource(ctx, uc.cache, models.ResourceGroup, groupUUID, []string{old.Number}, func() error {
+		return uc.repo.Delete(ctx, groupUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
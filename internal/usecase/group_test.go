@@ -70,7 +70,7 @@ func TestGroupUseCase_Create(t *testing.T) {
 			}
 
 			// Creating UseCase
-			uc := NewGroupUseCase(mockRepo, *mockService)
+			uc := NewGroupUseCase(mockRepo, *mockService, nil)
 
 			// Execute testing function
 			_, err := uc.Create(context.Background(), tt.groupDTO)
@@ -93,30 +93,60 @@ func TestGroupUseCase_Create(t *testing.T) {
 }
 
 func TestGroupUseCase_Get(t *testing.T) {
+	next := &models.ListCursor{Value: "321-123", UUID: uuid.New()}
 	tests := []struct {
 		name           string
-		mockReturn     []*models.Group
+		params         *dto.ListParams
+		mockReturn     *models.ListPage[*models.Group]
 		mockError      error
+		expectRepoCall bool
 		expectedResult []*models.Group
+		expectedMore   bool
 		expectedError  error
 	}{
 		{
 			name: "Successful fetch",
-			mockReturn: []*models.Group{
+			mockReturn: &models.ListPage[*models.Group]{Items: []*models.Group{
 				{UUID: uuid.New(), Number: "221-352"},
 				{UUID: uuid.New(), Number: "321-123"},
-			},
-			mockError: nil,
+			}},
+			mockError:      nil,
+			expectRepoCall: true,
 			expectedResult: []*models.Group{
 				{UUID: uuid.Nil, Number: "221-352"},
 				{UUID: uuid.Nil, Number: "321-123"},
 			},
 			expectedError: nil,
 		},
+		{
+			name:   "Page with next one",
+			params: &dto.ListParams{Limit: "2", Sort: "-number", Filters: []string{"number~=2"}},
+			mockReturn: &models.ListPage[*models.Group]{Items: []*models.Group{
+				{UUID: uuid.New(), Number: "321-123"},
+				{UUID: uuid.New(), Number: "221-352"},
+			}, Next: next},
+			expectRepoCall: true,
+			expectedResult: []*models.Group{
+				{UUID: uuid.Nil, Number: "321-123"},
+				{UUID: uuid.Nil, Number: "221-352"},
+			},
+			expectedMore: true,
+		},
+		{
+			name:          "Invalid filter",
+			params:        &dto.ListParams{Filters: []string{"uuid=1"}},
+			expectedError: ErrInvalidFilter,
+		},
+		{
+			name:          "Invalid limit",
+			params:        &dto.ListParams{Limit: "0"},
+			expectedError: ErrInvalidLimit,
+		},
 		{
 			name:           "Error fetching groups",
 			mockReturn:     nil,
 			mockError:      assert.AnError,
+			expectRepoCall: true,
 			expectedResult: nil,
 			expectedError:  assert.AnError,
 		},
@@ -128,21 +158,25 @@ func TestGroupUseCase_Get(t *testing.T) {
 			mockRepo := new(mocks.GroupRepository)
 			mockService := new(services.GroupService)
 
-			mockRepo.On("Get", mock.Anything).Return(tt.mockReturn, tt.mockError)
+			if tt.expectRepoCall {
+				mockRepo.On("List", mock.Anything, mock.Anything).Return(tt.mockReturn, tt.mockError)
+			}
 
-			uc := NewGroupUseCase(mockRepo, *mockService)
+			uc := NewGroupUseCase(mockRepo, *mockService, nil)
 
-			result, err := uc.Get(context.Background())
+			result, err := uc.Get(context.Background(), tt.params)
 
 			if tt.expectedError != nil {
 				assert.ErrorIs(t, err, tt.expectedError)
 			} else {
 				assert.NoError(t, err)
 
-				assert.Equal(t, len(tt.expectedResult), len(result))
+				assert.Equal(t, len(tt.expectedResult), len(result.Groups))
 				for i := range tt.expectedResult {
-					assert.Equal(t, tt.mockReturn[i].Number, result[i].Number)
+					assert.Equal(t, tt.expectedResult[i].Number, result.Groups[i].Number)
 				}
+				assert.Equal(t, tt.expectedMore, result.Pagination.HasMore)
+				assert.Equal(t, tt.expectedMore, result.Pagination.NextCursor != "")
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -187,7 +221,7 @@ func TestGroupUseCase_GetByUUID(t *testing.T) {
 			mockRepo := new(mocks.GroupRepository)
 			mockService := new(services.GroupService)
 
-			uc := NewGroupUseCase(mockRepo, *mockService)
+			uc := NewGroupUseCase(mockRepo, *mockService, nil)
 
 			parsedUUID, err := uuid.Parse(tt.inputUUID)
 			if err == nil {
@@ -208,3 +242,54 @@ func TestGroupUseCase_GetByUUID(t *testing.T) {
 		})
 	}
 }
+
+// recordingInvalidator returns pairs of resource and records names it dropped
+type recordingInvalidator struct {
+	schedules []*models.ScheduleData
+	names     []string
+	dropped   []*models.ScheduleData
+}
+
+func (r *recordingInvalidator) SchedulesOf(_ context.Context, _ string, _ uuid.UUID) ([]*models.ScheduleData, error) {
+	return r.schedules, nil
+}
+
+func (r *recordingInvalidator) InvalidateResource(_ context.Context, _ string, names []string, schedules []*models.ScheduleData) error {
+	r.names = append(r.names, names...)
+	r.dropped = append(r.dropped, schedules...)
+	return nil
+}
+
+func TestGroupUseCase_UpdateInvalidatesSchedule(t *testing.T) {
+	groupUUID := uuid.New()
+	pairs := []*models.ScheduleData{{Group: "221-351", Teachers: []string{"Фамилия Имя"}}}
+
+	t.Run("Renamed group drops both numbers", func(t *testing.T) {
+		mockRepo := new(mocks.GroupRepository)
+		cache := &recordingInvalidator{schedules: pairs}
+		uc := NewGroupUseCase(mockRepo, *services.NewGroupService(), cache)
+
+		mockRepo.On("GetByUUID", mock.Anything, groupUUID).Return(&models.Group{UUID: groupUUID, Number: "221-351"}, nil)
+		mockRepo.On("Update", mock.Anything, &models.Group{UUID: groupUUID, Number: "221-352"}).Return(nil)
+
+		err := uc.Update(context.Background(), groupUUID.String(), &dto.UpdateGroupRequest{Group: "221-352"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"221-351", "221-352"}, cache.names)
+		assert.Equal(t, pairs, cache.dropped)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failed delete keeps cache", func(t *testing.T) {
+		mockRepo := new(mocks.GroupRepository)
+		cache := &recordingInvalidator{schedules: pairs}
+		uc := NewGroupUseCase(mockRepo, *services.NewGroupService(), cache)
+
+		mockRepo.On("GetByUUID", mock.Anything, groupUUID).Return(&models.Group{UUID: groupUUID, Number: "221-351"}, nil)
+		mockRepo.On("Delete", mock.Anything, groupUUID).Return(assert.AnError)
+
+		err := uc.Delete(context.Background(), groupUUID.String())
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, cache.names)
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/dto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	listLimit    = 50
+	listMaxLimit = 500
+)
+
+// filterOperators are checked in order, so two-char operators go first
+var filterOperators = []string{
+	models.FilterContains,
+	models.FilterNe,
+	models.FilterGe,
+	models.FilterLe,
+	models.FilterEq,
+	models.FilterGt,
+	models.FilterLt,
+}
+
+// parseListQuery validates limit, cursor, sort and filters of list route against fields of entity
+func parseListQuery(params *dto.ListParams, fields models.ListFields, defaultSort string) (*models.ListQuery, error) {
+	const op = "usecase.list.parseListQuery"
+
+	query := &models.ListQuery{Limit: listLimit, SortField: defaultSort}
+	if params == nil {
+		return query, nil
+	}
+
+	if params.Limit != "" {
+		limit, err := strconv.Atoi(params.Limit)
+		if err != nil || limit < 1 || limit > listMaxLimit {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidLimit)
+		}
+		query.Limit = limit
+	}
+
+	if params.Sort != "" {
+		field := strings.TrimPrefix(params.Sort, "-")
+		fieldType, ok := fields[field]
+		if !ok || fieldType == models.ListFieldArray {
+			return nil, fmt.Errorf("%s: %w %q", op, ErrInvalidSort, params.Sort)
+		}
+		query.SortField, query.SortDesc = field, strings.HasPrefix(params.Sort, "-")
+	}
+
+	for _, raw := range params.Filters {
+		filter, ok := parseFilter(raw, fields)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w %q", op, ErrInvalidFilter, raw)
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+
+	if params.Cursor != "" {
+		cursor, ok := decodeCursor(params.Cursor)
+		// Cursor is valid only for sort it was made for
+		if !ok || cursor.Sort != sortOf(query) || !validListValue(fields[query.SortField], cursor.Value) {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+		}
+		query.After = cursor
+	}
+
+	return query, nil
+}
+
+// parseFilter parses filter like "number~=221-", field name is followed by operator and value
+func parseFilter(raw string, fields models.ListFields) (models.ListFilter, bool) {
+	i := strings.IndexAny(raw, "=!~<>")
+	if i < 1 {
+		return models.ListFilter{}, false
+	}
+
+	filter := models.ListFilter{Field: raw[:i]}
+	for _, op := range filterOperators {
+		if strings.HasPrefix(raw[i:], op) {
+			filter.Op, filter.Value = op, raw[i+len(op):]
+			break
+		}
+	}
+
+	fieldType, ok := fields[filter.Field]
+	switch {
+	case !ok || filter.Op == "":
+		return filter, false
+	case filter.Op == models.FilterContains:
+		return filter, fieldType == models.ListFieldString || fieldType == models.ListFieldArray
+	case fieldType == models.ListFieldArray:
+		return filter, filter.Op == models.FilterEq
+	}
+
+	return filter, validListValue(fieldType, filter.Value)
+}
+
+// validListValue tells if value can be compared with field of type
+func validListValue(fieldType, value string) bool {
+	var err error
+	switch fieldType {
+	case models.ListFieldInt:
+		_, err = strconv.Atoi(value)
+	case models.ListFieldBool:
+		_, err = strconv.ParseBool(value)
+	case models.ListFieldDate:
+		_, err = time.Parse(time.DateOnly, value)
+	case models.ListFieldTime:
+		_, err = time.Parse(time.TimeOnly, value)
+	}
+
+	return err == nil
+}
+
+func sortOf(query *models.ListQuery) string {
+	if query.SortDesc {
+		return "-" + query.SortField
+	}
+	return query.SortField
+}
+
+func encodeCursor(cursor *models.ListCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string) (*models.ListCursor, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, false
+	}
+
+	var cursor models.ListCursor
+	if json.Unmarshal(data, &cursor) != nil {
+		return nil, false
+	}
+
+	return &cursor, true
+}
+
+// makePagination returns pagination of page listed by query
+func makePagination[T any](page *models.ListPage[T], query *models.ListQuery) dto.Pagination {
+	pagination := dto.Pagination{Limit: query.Limit}
+	if page.Next != nil {
+		page.Next.Sort = sortOf(query)
+		pagination.NextCursor = encodeCursor(page.Next)
+		pagination.HasMore = true
+	}
+
+	return pagination
+}
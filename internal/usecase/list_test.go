@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/dto"
+	"testing"
+)
+
+func TestParseListQuery(t *testing.T) {
+	cursor := encodeCursor(&models.ListCursor{Sort: "-weekday", Value: "3", UUID: uuid.New()})
+
+	tests := []struct {
+		name          string
+		params        *dto.ListParams
+		expected      []models.ListFilter
+		expectedError error
+	}{
+		{
+			name: "Filters of every type",
+			params: &dto.ListParams{Filters: []string{
+				"group~=221-", "weekday>=3", "start_date<2025-06-01", "is_session=false", "teacher=Фамилия Имя", "subject!=Физика",
+			}},
+			expected: []models.ListFilter{
+				{Field: "group", Op: models.FilterContains, Value: "221-"},
+				{Field: "weekday", Op: models.FilterGe, Value: "3"},
+				{Field: "start_date", Op: models.FilterLt, Value: "2025-06-01"},
+				{Field: "is_session", Op: models.FilterEq, Value: "false"},
+				{Field: "teacher", Op: models.FilterEq, Value: "Фамилия Имя"},
+				{Field: "subject", Op: models.FilterNe, Value: "Физика"},
+			},
+		},
+		{
+			name:          "Unknown field",
+			params:        &dto.ListParams{Filters: []string{"link=https://rasp.dmami.ru"}},
+			expectedError: ErrInvalidFilter,
+		},
+		{
+			name:          "Value of other type",
+			params:        &dto.ListParams{Filters: []string{"weekday>=monday"}},
+			expectedError: ErrInvalidFilter,
+		},
+		{
+			name:          "Comparison of list",
+			params:        &dto.ListParams{Filters: []string{"room>ав4805"}},
+			expectedError: ErrInvalidFilter,
+		},
+		{
+			name:          "Sort by list",
+			params:        &dto.ListParams{Sort: "teacher"},
+			expectedError: ErrInvalidSort,
+		},
+		{
+			name:   "Cursor of same sort",
+			params: &dto.ListParams{Sort: "-weekday", Cursor: cursor},
+		},
+		{
+			name:          "Cursor of other sort",
+			params:        &dto.ListParams{Sort: "weekday", Cursor: cursor},
+			expectedError: ErrInvalidCursor,
+		},
+		{
+			name:          "Broken cursor",
+			params:        &dto.ListParams{Cursor: "not a cursor"},
+			expectedError: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := parseListQuery(tt.params, models.ScheduleListFields, "group")
+
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, query.Filters)
+			assert.Equal(t, listLimit, query.Limit)
+		})
+	}
+}
@@ -11,12 +11,13 @@ import (
 )
 
 type LocationUseCase struct {
-	repo interfaces.LocationRepository
-	svc  services.LocationService
+	repo  interfaces.LocationRepository
+	svc   services.LocationService
+	cache interfaces.ScheduleCacheInvalidator
 }
 
-func NewLocationUseCase(repo interfaces.LocationRepository, svc services.LocationService) *LocationUseCase {
-	return &LocationUseCase{repo: repo, svc: svc}
+func NewLocationUseCase(repo interfaces.LocationRepository, svc services.LocationService, cache interfaces.ScheduleCacheInvalidator) *LocationUseCase {
+	return &LocationUseCase{repo: repo, svc: svc, cache: cache}
 }
 func (uc *LocationUseCase) Create(ctx context.Context, locationDTO *dto.CreateLocationRequest) (*dto.CreateLocationResponse, error) {
 	const op = "usecase.location.Create"
@@ -39,16 +40,21 @@ func (uc *LocationUseCase) Create(ctx context.Context, locationDTO *dto.CreateLo
 	return &dto.CreateLocationResponse{UUID: location.UUID}, nil
 }
 
-func (uc *LocationUseCase) Get(ctx context.Context) ([]*models.Location, error) {
+func (uc *LocationUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetLocationsResponse, error) {
 	const op = "usecase.location.Get"
 
-	// Getting all locations from db
-	locations, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.LocationListFields, "name")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return locations, nil
+	// Getting page of locations from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetLocationsResponse{Locations: page.Items, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *LocationUseCase) GetByUUID(ctx context.Context, UUID string) (*models.Location, error) {
@@ -90,8 +96,18 @@ func (uc *LocationUseCase) Update(ctx context.Context, UUID string, locationDTO
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	location := &models.Location{UUID: locationUUID, Name: locationDTO.Name}
+
+	// Old name is needed to drop cached schedule of it
+	old, err := uc.repo.GetByUUID(ctx, locationUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Updating location in db with given location
-	err = uc.repo.Update(ctx, &models.Location{UUID: locationUUID, Name: locationDTO.Name})
+	err = writeResource(ctx, uc.cache, models.ResourceLocation, locationUUID, []string{old.Name, location.Name}, func() error {
+		return uc.repo.Update(ctx, location)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -108,8 +124,15 @@ func (uc *LocationUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	old, err := uc.repo.GetByUUID(ctx, locationUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Deleting location from db with given uuid
-	err = uc.repo.Delete(ctx, locationUUID)
+	err = writeResource(ctx, uc.cache, models.ResourceLocation, locationUUID, []string{old.Name}, func() error {
+		return uc.repo.Delete(ctx, locationUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"raspyx/internal/repository"
+	"time"
+)
+
+// oidcStateTTL is time user has to log in at provider
+const oidcStateTTL = 10 * time.Minute
+
+type OIDCUseCase struct {
+	provider    interfaces.OIDCProvider
+	states      interfaces.OIDCStateStore
+	repo        interfaces.UserIdentityRepository
+	repoUser    interfaces.UserRepository
+	users       *UserUseCase
+	svc         services.UserService
+	roleMapping map[string]string
+	txManager   interfaces.TxManager
+}
+
+func NewOIDCUseCase(
+	provider interfaces.OIDCProvider,
+	states interfaces.OIDCStateStore,
+	repo interfaces.UserIdentityRepository,
+	repoUser interfaces.UserRepository,
+	users *UserUseCase,
+	svc services.UserService,
+	roleMapping map[string]string,
+	txManager interfaces.TxManager,
+) *OIDCUseCase {
+	return &OIDCUseCase{
+		provider:    provider,
+		states:      states,
+		repo:        repo,
+		repoUser:    repoUser,
+		users:       users,
+		svc:         svc,
+		roleMapping: roleMapping,
+		txManager:   txManager,
+	}
+}
+
+// Start begins login at provider and returns url user is redirected to
+func (uc *OIDCUseCase) Start(ctx context.Context) (string, error) {
+	const op = "usecase.oidc.Start"
+
+	state, err := uc.svc.GenerateState()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+	nonce, err := uc.svc.GenerateState()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = uc.states.Save(ctx, state, nonce, oidcStateTTL)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return uc.provider.AuthCodeURL(state, nonce), nil
+}
+
+// Callback finishes login with code returned by provider, user is provisioned on first login
+func (uc *OIDCUseCase) Callback(ctx context.Context, state, code string) (*dto.LoginUserResponse, error) {
+	const op = "usecase.oidc.Callback"
+
+	// State is used once, so callback can not be replayed
+	nonce, err := uc.states.Pop(ctx, state)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if nonce == "" {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidOIDCLogin)
+	}
+
+	claims, err := uc.provider.Exchange(ctx, code, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w: %v", op, ErrInvalidOIDCLogin, err)
+	}
+
+	user, err := uc.findOrProvision(ctx, claims)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Starting new session
+	family, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+
+	resp, err := uc.users.issueTokens(ctx, user, family)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// findOrProvision returns user linked to account of provider, creating user with mapped role on first login.
+// Role is mapped only once, later it is changed by admins
+func (uc *OIDCUseCase) findOrProvision(ctx context.Context, claims *models.OIDCClaims) (*models.User, error) {
+	identity, err := uc.repo.GetByIssuerSubject(ctx, claims.Issuer, claims.Subject)
+	if err == nil {
+		return uc.repoUser.GetByUUID(ctx, identity.UserUUID)
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	newUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, ErrGeneratingUUID
+	}
+
+	// Users of provider have no password, so they can not log in with /users/login
+	user := &models.User{
+		UUID:     newUUID,
+		Username: claims.Username,
+		Role:     uc.svc.MapRole(claims.Groups, uc.roleMapping),
+	}
+	if !uc.svc.Validate(user) || user.Username == "" {
+		return nil, ErrInvalidUser
+	}
+
+	// Local user with the same username is not linked, it fails with user exists
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := uc.repoUser.Create(ctx, user); err != nil {
+			return err
+		}
+		return uc.repo.Create(ctx, &models.UserIdentity{
+			Issuer:    claims.Issuer,
+			Subject:   claims.Subject,
+			UserUUID:  user.UUID,
+			CreatedAt: time.Now(),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
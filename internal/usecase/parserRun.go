@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/dto"
+	"strconv"
+	"strings"
+)
+
+// Number of last parser runs returned by default and at most
+const (
+	parserRunsLimit    = 20
+	parserRunsMaxLimit = 100
+)
+
+type ParserRunUseCase struct {
+	repo      interfaces.ParserRunRepository
+	repoGroup interfaces.GroupRepository
+	runner    interfaces.ParserRunner
+}
+
+func NewParserRunUseCase(
+	repo interfaces.ParserRunRepository,
+	repoGroup interfaces.GroupRepository,
+	runner interfaces.ParserRunner,
+) *ParserRunUseCase {
+	return &ParserRunUseCase{
+		repo:      repo,
+		repoGroup: repoGroup,
+		runner:    runner,
+	}
+}
+
+// Get returns last runs of parser, newest first
+func (uc *ParserRunUseCase) Get(ctx context.Context, limit string) ([]*models.ParserRun, error) {
+	const op = "usecase.parserRun.Get"
+
+	n := parserRunsLimit
+	if limit != "" {
+		var err error
+		n, err = strconv.Atoi(limit)
+		if err != nil || n < 1 || n > parserRunsMaxLimit {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidLimit)
+		}
+	}
+
+	runs, err := uc.repo.Get(ctx, n)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return runs, nil
+}
+
+func (uc *ParserRunUseCase) GetByUUID(ctx context.Context, UUID string) (*models.ParserRun, error) {
+	const op = "usecase.parserRun.GetByUUID"
+
+	runUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	run, err := uc.repo.GetByUUID(ctx, runUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return run, nil
+}
+
+// Run starts parser for group from request or for every group. It fails with ErrParserBusy while parser is running
+func (uc *ParserRunUseCase) Run(ctx context.Context, runDTO *dto.ParserRunRequest) (*dto.ParserRunResponse, error) {
+	const op = "usecase.parserRun.Run"
+
+	var groups []string
+	if group := strings.TrimSpace(runDTO.Group); group != "" {
+		_, err := uc.repoGroup.GetByNumber(ctx, group)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, group)
+	}
+
+	runUUID, err := uc.runner.Start(ctx, groups)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.ParserRunResponse{UUID: runUUID}, nil
+}
@@ -2,21 +2,34 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"raspyx/internal/domain/interfaces"
 	"raspyx/internal/domain/models"
 	"raspyx/internal/domain/services"
 	"raspyx/internal/dto"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type RoomUseCase struct {
-	repo interfaces.RoomRepository
-	svc  services.RoomService
+	repo     interfaces.RoomRepository
+	repoBell interfaces.BellScheduleRepository
+	svc      services.RoomService
+	svcBell  services.BellScheduleService
+	cache    interfaces.ScheduleCacheInvalidator
 }
 
-func NewRoomUseCase(repo interfaces.RoomRepository, svc services.RoomService) *RoomUseCase {
-	return &RoomUseCase{repo: repo, svc: svc}
+func NewRoomUseCase(
+	repo interfaces.RoomRepository,
+	repoBell interfaces.BellScheduleRepository,
+	svc services.RoomService,
+	svcBell services.BellScheduleService,
+	cache interfaces.ScheduleCacheInvalidator,
+) *RoomUseCase {
+	return &RoomUseCase{repo: repo, repoBell: repoBell, svc: svc, svcBell: svcBell, cache: cache}
 }
 func (uc *RoomUseCase) Create(ctx context.Context, roomDTO *dto.CreateRoomRequest) (*dto.CreateRoomResponse, error) {
 	const op = "usecase.room.Create"
@@ -39,16 +52,21 @@ func (uc *RoomUseCase) Create(ctx context.Context, roomDTO *dto.CreateRoomReques
 	return &dto.CreateRoomResponse{UUID: room.UUID}, nil
 }
 
-func (uc *RoomUseCase) Get(ctx context.Context) ([]*models.Room, error) {
+func (uc *RoomUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetRoomsResponse, error) {
 	const op = "usecase.room.Get"
 
-	// Getting all rooms from db
-	rooms, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.RoomListFields, "number")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return rooms, nil
+	// Getting page of rooms from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetRoomsResponse{Rooms: page.Items, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *RoomUseCase) GetByUUID(ctx context.Context, UUID string) (*models.Room, error) {
@@ -90,8 +108,18 @@ func (uc *RoomUseCase) Update(ctx context.Context, UUID string, roomDTO *dto.Upd
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	room := &models.Room{UUID: roomUUID, Number: roomDTO.Number}
+
+	// Old number is needed to drop cached schedule of it
+	old, err := uc.repo.GetByUUID(ctx, roomUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Updating room in db with given room
-	err = uc.repo.Update(ctx, &models.Room{UUID: roomUUID, Number: roomDTO.Number})
+	err = writeResource(ctx, uc.cache, models.ResourceRoom, roomUUID, []string{old.Number, room.Number}, func() error {
+		return uc.repo.Update(ctx, room)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -108,10 +136,77 @@ func (uc *RoomUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	// Deleting room from db with given uuid
-	err = uc.repo.Delete(ctx, roomUUID)
+	old, err := uc.repo.GetByUUID(ctx, roomUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Deleting room from db with given uuid, its pairs lose it
+	err = writeResource(ctx, uc.cache, models.ResourceRoom, roomUUID, []string{old.Number}, func() error {
+		return uc.repo.Delete(ctx, roomUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
 	return nil
 }
+
+// GetFree returns rooms that are not occupied by any pair in requested slot on requested date.
+// Slot is given either by pair number from bell schedule or by time range.
+func (uc *RoomUseCase) GetFree(ctx context.Context, filter *dto.FreeRoomsRequest) ([]*models.Room, error) {
+	const op = "usecase.room.GetFree"
+
+	location := strings.TrimSpace(filter.Location)
+
+	// Parsing date
+	date, err := time.Parse(time.DateOnly, strings.TrimSpace(filter.Date))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidDate)
+	}
+
+	// Getting slot times
+	var st, et time.Time
+	if filter.Pair != "" {
+		pairNum, err := strconv.Atoi(filter.Pair)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid pair num"))
+		}
+
+		bells, err := uc.repoBell.Get(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		bell, ok := uc.svcBell.Slot(bells, location, date, pairNum)
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid pair num"))
+		}
+		st, et = bell.StartTime, bell.EndTime
+	} else {
+		st, err = parseClock(filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid start time"))
+		}
+		et, err = parseClock(filter.To)
+		if err != nil || !st.Before(et) {
+			return nil, fmt.Errorf("%s: %w", op, errors.New("invalid end time"))
+		}
+	}
+
+	// Getting free rooms from db
+	rooms, err := uc.repo.GetFree(ctx, location, strings.TrimSpace(filter.Prefix), date, st, et)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return rooms, nil
+}
+
+// parseClock parses time of day with or without seconds
+func parseClock(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.Parse(time.TimeOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("15:04", s)
+}
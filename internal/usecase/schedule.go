@@ -2,7 +2,6 @@ package usecase
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
@@ -11,6 +10,7 @@ import (
 	"raspyx/internal/domain/services"
 	"raspyx/internal/dto"
 	"raspyx/internal/repository"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +18,7 @@ import (
 
 type ScheduleUseCase struct {
 	repo         interfaces.ScheduleRepository
+	cached       interfaces.CachedScheduleRepository
 	repoGroup    interfaces.GroupRepository
 	repoSubject  interfaces.SubjectRepository
 	repoType     interfaces.SubjectTypeRepository
@@ -26,12 +27,20 @@ type ScheduleUseCase struct {
 	repoRoom     interfaces.RoomRepository
 	repoTToS     interfaces.TeachersToScheduleRepository
 	repoRToS     interfaces.RoomsToScheduleRepository
+	repoBell     interfaces.BellScheduleRepository
+	repoChange   interfaces.ScheduleChangeRepository
+	repoWebhook  interfaces.WebhookRepository
+	repoDelivery interfaces.WebhookDeliveryRepository
+	repoScope    interfaces.UserScopeRepository
 	svc          services.ScheduleService
-	cache        interfaces.Cache
+	svcBell      services.BellScheduleService
+	svcWebhook   services.WebhookService
+	txManager    interfaces.TxManager
 }
 
 func NewScheduleUseCase(
 	repo interfaces.ScheduleRepository,
+	cached interfaces.CachedScheduleRepository,
 	repoGroup interfaces.GroupRepository,
 	repoSubject interfaces.SubjectRepository,
 	repoType interfaces.SubjectTypeRepository,
@@ -40,11 +49,19 @@ func NewScheduleUseCase(
 	repoRoom interfaces.RoomRepository,
 	repoTToS interfaces.TeachersToScheduleRepository,
 	repoRToS interfaces.RoomsToScheduleRepository,
+	repoBell interfaces.BellScheduleRepository,
+	repoChange interfaces.ScheduleChangeRepository,
+	repoWebhook interfaces.WebhookRepository,
+	repoDelivery interfaces.WebhookDeliveryRepository,
+	repoScope interfaces.UserScopeRepository,
 	svc services.ScheduleService,
-	cache interfaces.Cache,
+	svcBell services.BellScheduleService,
+	svcWebhook services.WebhookService,
+	txManager interfaces.TxManager,
 ) *ScheduleUseCase {
 	return &ScheduleUseCase{
 		repo:         repo,
+		cached:       cached,
 		repoGroup:    repoGroup,
 		repoSubject:  repoSubject,
 		repoType:     repoType,
@@ -53,8 +70,15 @@ func NewScheduleUseCase(
 		repoRoom:     repoRoom,
 		repoTToS:     repoTToS,
 		repoRToS:     repoRToS,
+		repoBell:     repoBell,
+		repoChange:   repoChange,
+		repoWebhook:  repoWebhook,
+		repoDelivery: repoDelivery,
+		repoScope:    repoScope,
 		svc:          svc,
-		cache:        cache,
+		svcBell:      svcBell,
+		svcWebhook:   svcWebhook,
+		txManager:    txManager,
 	}
 }
 
@@ -138,45 +162,113 @@ func (uc *ScheduleUseCase) scheduleDTOToScheduleModel(ctx context.Context, sched
 	return schedule, nil
 }
 
-func makeWeek(schedules []*models.ScheduleData) *dto.Week {
-	week := &dto.Week{}
+// period is a closed range of dates
+type period struct {
+	from time.Time
+	to   time.Time
+}
 
-	for _, schedule := range schedules {
-		pair := mapScheduleToPair(schedule)
+// parsePeriod converts date or ISO week from filter to period, nil period means the whole schedule
+func (uc *ScheduleUseCase) parsePeriod(filter *dto.DateFilter) (*period, error) {
+	if filter == nil || (filter.Date == "" && filter.Week == "") {
+		return nil, nil
+	}
 
-		var day string
-		if !schedule.IsSession {
-			day = numToDay(schedule.Weekday)
-		} else {
-			day = schedule.StartDate.Format(time.DateOnly)
+	if filter.Date != "" && filter.Week != "" {
+		return nil, ErrInvalidPeriod
+	}
+
+	// Single day
+	if filter.Date != "" {
+		date, err := time.Parse(time.DateOnly, strings.TrimSpace(filter.Date))
+		if err != nil {
+			return nil, ErrInvalidDate
 		}
+		return &period{from: date, to: date}, nil
+	}
 
-		if (*week)[day] == nil {
-			(*week)[day] = &dto.Day{}
+	// ISO week in format 2025-W11
+	var year, week int
+	if _, err := fmt.Sscanf(strings.TrimSpace(filter.Week), "%d-W%d", &year, &week); err != nil {
+		return nil, services.InvalidWeek
+	}
+	monday, err := uc.svc.ISOWeekStart(year, week)
+	if err != nil {
+		return nil, err
+	}
+
+	return &period{from: monday, to: monday.AddDate(0, 0, 6)}, nil
+}
+
+// makeWeek makes week keyed by weekday, or by real dates when period is given.
+// Pairs are put to slots by bell schedule, pairs that do not start on a bell go to other slot.
+func (uc *ScheduleUseCase) makeWeek(ctx context.Context, schedules []*models.ScheduleData, p *period) (*dto.Week, error) {
+	const op = "usecase.schedule.makeWeek"
+
+	// Getting bells from db
+	bells, err := uc.repoBell.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week := &dto.Week{}
+
+	if p == nil {
+		for _, schedule := range schedules {
+			var day string
+			if !schedule.IsSession {
+				day = numToDay(schedule.Weekday)
+			} else {
+				day = schedule.StartDate.Format(time.DateOnly)
+			}
+
+			pairNum := uc.svcBell.PairNum(bells, schedule.Location, schedule.StartDate, schedule.StartTime)
+			appendToWeek(week, mapScheduleToPair(schedule), day, pairNum)
 		}
 
-		appendToWeek(week, pair, day, schedule.StartTime)
+		return week, nil
 	}
 
-	return week
+	for date := p.from; !date.After(p.to); date = date.AddDate(0, 0, 1) {
+		day := date.Format(time.DateOnly)
+		for _, schedule := range schedules {
+			if !uc.svc.TakesPlace(schedule, date) {
+				continue
+			}
+
+			pair := mapScheduleToPair(schedule)
+			pair.Date = day
+
+			pairNum := uc.svcBell.PairNum(bells, schedule.Location, date, schedule.StartTime)
+			appendToWeek(week, pair, day, pairNum)
+		}
+	}
+
+	return week, nil
 }
 
-func appendToWeek(week *dto.Week, pair *dto.Pair, day string, time time.Time) {
-	switch time.Format("15:04") {
-	case "09:00":
+func appendToWeek(week *dto.Week, pair *dto.Pair, day string, pairNum int) {
+	if (*week)[day] == nil {
+		(*week)[day] = &dto.Day{}
+	}
+
+	switch pairNum {
+	case 1:
 		(*week)[day].First = append((*week)[day].First, *pair)
-	case "10:40":
+	case 2:
 		(*week)[day].Second = append((*week)[day].Second, *pair)
-	case "12:20":
+	case 3:
 		(*week)[day].Third = append((*week)[day].Third, *pair)
-	case "14:30":
+	case 4:
 		(*week)[day].Fourth = append((*week)[day].Fourth, *pair)
-	case "16:10":
+	case 5:
 		(*week)[day].Fifth = append((*week)[day].Fifth, *pair)
-	case "17:50":
+	case 6:
 		(*week)[day].Sixth = append((*week)[day].Sixth, *pair)
-	case "19:30":
+	case 7:
 		(*week)[day].Seventh = append((*week)[day].Seventh, *pair)
+	default:
+		(*week)[day].Other = append((*week)[day].Other, *pair)
 	}
 }
 
@@ -185,6 +277,8 @@ func mapScheduleToPair(schedule *models.ScheduleData) *dto.Pair {
 		Group:     schedule.Group,
 		Subject:   schedule.Subject,
 		Teachers:  schedule.Teachers,
+		StartTime: schedule.StartTime.Format(time.TimeOnly),
+		EndTime:   schedule.EndTime.Format(time.TimeOnly),
 		StartDate: schedule.StartDate.Format(time.DateOnly),
 		EndDate:   schedule.EndDate.Format(time.DateOnly),
 		Rooms:     schedule.Rooms,
@@ -205,81 +299,234 @@ func numToDay(num int) string {
 	}[num]
 }
 
-func (uc *ScheduleUseCase) Create(ctx context.Context, scheduleDTO *dto.ScheduleRequest) (*dto.CreateScheduleResponse, error) {
-	const op = "usecase.schedule.Create"
+// findConflicts returns existing pairs of the same group, teachers or rooms that take place at the same time.
+// It is called within transaction after lockConflicts, so concurrent writes do not miss each other
+func (uc *ScheduleUseCase) findConflicts(ctx context.Context, schedule *models.Schedule, scheduleDTO *dto.ScheduleRequest) ([]*models.ScheduleConflict, error) {
+	const op = "usecase.schedule.findConflicts"
 
-	// DTO to model
-	schedule, err := uc.scheduleDTOToScheduleModel(ctx, scheduleDTO)
+	// Subject is compared by name to tell lesson of several groups
+	subject, err := uc.repoSubject.GetByUUID(ctx, schedule.SubjectUUID)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Generating new uuid
-	newUUID, err := uuid.NewUUID()
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	candidate := &models.ScheduleData{
+		UUID:      schedule.UUID,
+		Subject:   subject.Name,
+		Type:      scheduleDTO.Type,
+		StartTime: schedule.StartTime,
+		EndTime:   schedule.EndTime,
+		StartDate: schedule.StartDate,
+		EndDate:   schedule.EndDate,
+		Weekday:   schedule.Weekday,
+		IsSession: schedule.IsSession,
 	}
-	schedule.UUID = newUUID
 
-	// Adding schedule to db
-	err = uc.repo.Create(ctx, schedule)
+	var conflicts []*models.ScheduleConflict
+	check := func(kind, resource string, fetch func(isSession bool) ([]*models.ScheduleData, error)) error {
+		schedules, err := collectSchedules(fetch)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		for _, s := range schedules {
+			// Teacher or room of one lesson of several groups is booked once
+			if kind != models.ResourceGroup && uc.svc.SameLesson(candidate, s) {
+				continue
+			}
+			if s.UUID != candidate.UUID && uc.svc.Overlaps(candidate, s) {
+				conflicts = append(conflicts, &models.ScheduleConflict{
+					Kind:      kind,
+					Resource:  resource,
+					Schedules: []*models.ScheduleData{s},
+				})
+			}
+		}
+
+		return nil
+	}
+
+	// Checking group
+	err = check(models.ResourceGroup, scheduleDTO.Group, func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.repo.GetByGroupUUID(ctx, schedule.GroupUUID, isSession)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Adding teachers to created schedule
+	// Checking teachers, unknown teachers are reported when linking them to schedule
 	for _, UUID := range scheduleDTO.TeachersUUID {
 		teacherUUID, err := uuid.Parse(UUID)
 		if err != nil {
-			_ = uc.repo.Delete(ctx, schedule.UUID)
+			continue
+		}
+		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+		if err != nil {
+			continue
+		}
+
+		name := strings.TrimSpace(fmt.Sprintf("%v %v %v", teacher.SecondName, teacher.FirstName, teacher.MiddleName))
+		err = check(models.ResourceTeacher, name, func(isSession bool) ([]*models.ScheduleData, error) {
+			return uc.repo.GetByTeacherUUID(ctx, teacher.UUID, isSession)
+		})
+		if err != nil {
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+	}
 
-		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+	// Checking rooms
+	for _, roomNumber := range scheduleDTO.Rooms {
+		err = check(models.ResourceRoom, roomNumber, func(isSession bool) ([]*models.ScheduleData, error) {
+			return uc.repo.GetByRoom(ctx, roomNumber, isSession)
+		})
 		if err != nil {
-			_ = uc.repo.Delete(ctx, schedule.UUID)
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
+	}
+
+	return conflicts, nil
+}
+
+// lockConflicts serializes writes of pairs which may clash with schedule until the end of transaction.
+// Pairs clash only on the same weekday, so writes of other weekdays are not blocked
+func (uc *ScheduleUseCase) lockConflicts(ctx context.Context, schedule *models.Schedule) error {
+	return uc.txManager.Lock(ctx, fmt.Sprintf("schedule:weekday:%d", schedule.Weekday))
+}
+
+// linkSchedule adds teachers and rooms from DTO to schedule with given uuid
+func (uc *ScheduleUseCase) linkSchedule(ctx context.Context, scheduleUUID uuid.UUID, scheduleDTO *dto.ScheduleRequest) error {
+	const op = "usecase.schedule.linkSchedule"
+
+	// Adding teachers to schedule
+	for _, UUID := range scheduleDTO.TeachersUUID {
+		teacherUUID, err := uuid.Parse(UUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
 
 		err = uc.repoTToS.Create(ctx, &models.TeachersToSchedule{
-			TeacherUUID: teacher.UUID, ScheduleUUID: schedule.UUID,
+			TeacherUUID: teacher.UUID, ScheduleUUID: scheduleUUID,
 		})
 		if err != nil {
-			_ = uc.repo.Delete(ctx, schedule.UUID)
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	// Adding rooms to created schedule
+	// Adding rooms to schedule
 	for _, roomNumber := range scheduleDTO.Rooms {
 		room, err := uc.repoRoom.GetByNumber(ctx, roomNumber)
 		if err != nil {
-			_ = uc.repo.Delete(ctx, schedule.UUID)
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return fmt.Errorf("%s: %w", op, err)
 		}
 
 		err = uc.repoRToS.Create(ctx, &models.RoomsToSchedule{
-			RoomUUID: room.UUID, ScheduleUUID: schedule.UUID,
+			RoomUUID: room.UUID, ScheduleUUID: scheduleUUID,
 		})
 		if err != nil {
-			_ = uc.repo.Delete(ctx, schedule.UUID)
-			return nil, fmt.Errorf("%s: %w", op, err)
+			return fmt.Errorf("%s: %w", op, err)
 		}
 	}
 
-	return &dto.CreateScheduleResponse{UUID: schedule.UUID}, nil
+	return nil
 }
 
-func (uc *ScheduleUseCase) Get(ctx context.Context) (*dto.Week, error) {
+// Create adds schedule to db. Pair that clashes with existing ones is rejected with ErrScheduleConflict
+// unless force is set, conflicts are returned in response in both cases.
+func (uc *ScheduleUseCase) Create(ctx context.Context, scheduleDTO *dto.ScheduleRequest, force bool) (*dto.CreateScheduleResponse, error) {
+	const op = "usecase.schedule.Create"
+
+	// DTO to model
+	schedule, err := uc.scheduleDTOToScheduleModel(ctx, scheduleDTO)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Checking user can edit schedule of group at location
+	err = uc.checkScope(ctx, scheduleDTO.Group, scheduleDTO.Location)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Generating new uuid
+	newUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+	schedule.UUID = newUUID
+
+	var conflicts []*models.ScheduleConflict
+	var created *models.ScheduleData
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Checking for double-booking
+		err := uc.lockConflicts(ctx, schedule)
+		if err != nil {
+			return err
+		}
+		conflicts, err = uc.findConflicts(ctx, schedule, scheduleDTO)
+		if err != nil {
+			return err
+		}
+		if len(conflicts) > 0 && !force {
+			return ErrScheduleConflict
+		}
+
+		// Adding schedule with its teachers and rooms to db
+		err = uc.repo.Create(ctx, schedule)
+		if err != nil {
+			return err
+		}
+
+		err = uc.linkSchedule(ctx, schedule.UUID, scheduleDTO)
+		if err != nil {
+			return err
+		}
+
+		// Recording change
+		created, err = uc.repo.GetByUUID(ctx, schedule.UUID)
+		if err != nil {
+			return err
+		}
+		return uc.recordChange(ctx, models.ChangeCreate, nil, created)
+	})
+	if errors.Is(err, ErrScheduleConflict) {
+		return &dto.CreateScheduleResponse{Conflicts: conflicts}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	uc.invalidate(ctx, created)
+
+	return &dto.CreateScheduleResponse{UUID: schedule.UUID, Conflicts: conflicts}, nil
+}
+
+func (uc *ScheduleUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetSchedulesResponse, error) {
 	const op = "usecase.schedule.Get"
 
-	// Getting all schedules from db
-	schedules, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.ScheduleListFields, "group")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting page of pairs from db
+	page, err := uc.repo.List(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	week, err := uc.makeWeek(ctx, page.Items, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetSchedulesResponse{Week: week, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *ScheduleUseCase) GetByUUID(ctx context.Context, UUID string) (*dto.Week, error) {
@@ -297,12 +544,23 @@ func (uc *ScheduleUseCase) GetByUUID(ctx context.Context, UUID string) (*dto.Wee
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek([]*models.ScheduleData{schedules}), nil
+	week, err := uc.makeWeek(ctx, []*models.ScheduleData{schedules}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByTeacher(ctx context.Context, fn string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByTeacher(ctx context.Context, fn string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByTeacher"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	fnArr := strings.Split(strings.TrimSpace(fn), " ")
 	for i := 0; i < len(fnArr); i++ {
 		if fnArr[i] == "" {
@@ -316,17 +574,28 @@ func (uc *ScheduleUseCase) GetByTeacher(ctx context.Context, fn string, isSessio
 
 	// Getting schedule from db with given teacher fullname
 	fnArr = append(fnArr, "")
-	schedules, err := uc.repo.GetByTeacher(ctx, fnArr[1], fnArr[0], fnArr[2], isSession)
+	schedules, err := uc.cached.GetByTeacher(ctx, fnArr[1], fnArr[0], fnArr[2], isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByTeacherUUID(ctx context.Context, UUID string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByTeacherUUID(ctx context.Context, UUID string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByTeacherUUID"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Parsing teacher uuid
 	teacherUUID, err := uuid.Parse(UUID)
 	if err != nil {
@@ -334,48 +603,53 @@ func (uc *ScheduleUseCase) GetByTeacherUUID(ctx context.Context, UUID string, is
 	}
 
 	// Getting schedule from db with given teacher uuid
-	schedules, err := uc.repo.GetByTeacherUUID(ctx, teacherUUID, isSession)
+	schedules, err := uc.cached.GetByTeacherUUID(ctx, teacherUUID, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByGroup(ctx context.Context, groupNumber string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByGroup(ctx context.Context, groupNumber string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByGroup"
-	cacheKey := "schedule:" + groupNumber
-	if isSession {
-		cacheKey += ":1"
-	} else {
-		cacheKey += ":0"
+
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	groupNumber = strings.TrimSpace(groupNumber)
 
-	if cached, err := uc.cache.Get(ctx, cacheKey); err == nil {
-		var week dto.Week
-		_ = json.Unmarshal([]byte(cached), &week)
-		return &week, nil
-	}
-
 	// Getting schedule from db with given group number
-	schedules, err := uc.repo.GetByGroup(ctx, groupNumber, isSession)
+	schedules, err := uc.cached.GetByGroup(ctx, groupNumber, isSession)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	week := makeWeek(schedules)
-
-	data, _ := json.Marshal(week)
-	_ = uc.cache.Set(ctx, cacheKey, string(data), 10*time.Second)
+	week, err := uc.makeWeek(ctx, schedules, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
 	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByGroupUUID(ctx context.Context, UUID string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByGroupUUID(ctx context.Context, UUID string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByGroupUUID"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Parsing group uuid
 	groupUUID, err := uuid.Parse(UUID)
 	if err != nil {
@@ -383,31 +657,53 @@ func (uc *ScheduleUseCase) GetByGroupUUID(ctx context.Context, UUID string, isSe
 	}
 
 	// Getting schedule from db with given group uuid
-	schedules, err := uc.repo.GetByGroupUUID(ctx, groupUUID, isSession)
+	schedules, err := uc.cached.GetByGroupUUID(ctx, groupUUID, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByRoom(ctx context.Context, roomNumber string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByRoom(ctx context.Context, roomNumber string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByRoom"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	roomNumber = strings.TrimSpace(roomNumber)
 
 	// Getting schedule from db with given room number
-	schedules, err := uc.repo.GetByRoom(ctx, roomNumber, isSession)
+	schedules, err := uc.cached.GetByRoom(ctx, roomNumber, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByRoomUUID(ctx context.Context, UUID string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByRoomUUID(ctx context.Context, UUID string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByRoomUUID"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Parsing room uuid
 	roomUUID, err := uuid.Parse(UUID)
 	if err != nil {
@@ -415,31 +711,53 @@ func (uc *ScheduleUseCase) GetByRoomUUID(ctx context.Context, UUID string, isSes
 	}
 
 	// Getting schedule from db with given room uuid
-	schedules, err := uc.repo.GetByRoomUUID(ctx, roomUUID, isSession)
+	schedules, err := uc.cached.GetByRoomUUID(ctx, roomUUID, isSession)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	week, err := uc.makeWeek(ctx, schedules, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetBySubject(ctx context.Context, subjectName string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetBySubject(ctx context.Context, subjectName string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetBySubject"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	subjectName = strings.TrimSpace(subjectName)
 
 	// Getting schedule from db with given subject name
-	schedules, err := uc.repo.GetBySubject(ctx, subjectName, isSession)
+	schedules, err := uc.cached.GetBySubject(ctx, subjectName, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetBySubjectUUID(ctx context.Context, UUID string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetBySubjectUUID(ctx context.Context, UUID string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetBySubjectUUID"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Parsing subject uuid
 	subjectUUID, err := uuid.Parse(UUID)
 	if err != nil {
@@ -447,31 +765,53 @@ func (uc *ScheduleUseCase) GetBySubjectUUID(ctx context.Context, UUID string, is
 	}
 
 	// Getting schedule from db with given subject uuid
-	schedules, err := uc.repo.GetBySubjectUUID(ctx, subjectUUID, isSession)
+	schedules, err := uc.cached.GetBySubjectUUID(ctx, subjectUUID, isSession)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	week, err := uc.makeWeek(ctx, schedules, p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByLocation(ctx context.Context, locationName string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByLocation(ctx context.Context, locationName string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByLocation"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	locationName = strings.TrimSpace(locationName)
 
 	// Getting schedule from db with given location name
-	schedules, err := uc.repo.GetByLocation(ctx, locationName, isSession)
+	schedules, err := uc.cached.GetByLocation(ctx, locationName, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) GetByLocationUUID(ctx context.Context, UUID string, isSession bool) (*dto.Week, error) {
+func (uc *ScheduleUseCase) GetByLocationUUID(ctx context.Context, UUID string, isSession bool, filter *dto.DateFilter) (*dto.Week, error) {
 	const op = "usecase.schedule.GetByLocationUUID"
 
+	// Parsing requested period
+	p, err := uc.parsePeriod(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Parsing location uuid
 	locationUUID, err := uuid.Parse(UUID)
 	if err != nil {
@@ -479,154 +819,391 @@ func (uc *ScheduleUseCase) GetByLocationUUID(ctx context.Context, UUID string, i
 	}
 
 	// Getting schedule from db with given location uuid
-	schedules, err := uc.repo.GetByLocationUUID(ctx, locationUUID, isSession)
+	schedules, err := uc.cached.GetByLocationUUID(ctx, locationUUID, isSession)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	week, err := uc.makeWeek(ctx, schedules, p)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return makeWeek(schedules), nil
+	return week, nil
 }
 
-func (uc *ScheduleUseCase) rollbackUpdate(
-	ctx context.Context,
-	schedule *models.Schedule,
-	TToS []*models.TeachersToSchedule,
-	RToS []*models.RoomsToSchedule,
-) error {
-	const op = "usecase.schedule.rollbackUpdate"
+func (uc *ScheduleUseCase) GetConflicts(ctx context.Context) (*dto.ScheduleConflictsResponse, error) {
+	const op = "usecase.schedule.GetConflicts"
 
-	err := uc.repo.Delete(ctx, schedule.UUID)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+	// Getting all schedules from db
+	schedules, err := uc.cached.Get(ctx)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = uc.repo.Create(ctx, schedule)
-	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+	conflicts := uc.svc.Conflicts(schedules)
+	if conflicts == nil {
+		conflicts = []*models.ScheduleConflict{}
+	}
+
+	return &dto.ScheduleConflictsResponse{Conflicts: conflicts}, nil
+}
+
+// maxAvailabilityDays limits availability range to about one quarter
+const maxAvailabilityDays = 92
+
+// GetTeachersAvailability returns pair slots between from and to in which none of given teachers has a pair.
+// Range defaults to a week starting today.
+func (uc *ScheduleUseCase) GetTeachersAvailability(ctx context.Context, UUIDs []string, from, to string) (*dto.AvailabilityResponse, error) {
+	const op = "usecase.schedule.GetTeachersAvailability"
+
+	if len(UUIDs) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	for _, t := range TToS {
-		err = uc.repoTToS.Create(ctx, t)
+	// Parsing range
+	now := time.Now().UTC()
+	fromDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if from != "" {
+		d, err := time.Parse(time.DateOnly, strings.TrimSpace(from))
 		if err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidDate)
 		}
+		fromDate = d
+	}
+	toDate := fromDate.AddDate(0, 0, 6)
+	if to != "" {
+		d, err := time.Parse(time.DateOnly, strings.TrimSpace(to))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidDate)
+		}
+		toDate = d
+	}
+	if toDate.Before(fromDate) || toDate.Sub(fromDate) > maxAvailabilityDays*24*time.Hour {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidRange)
 	}
 
-	for _, r := range RToS {
-		err = uc.repoRToS.Create(ctx, r)
+	// Getting regular and session pairs of every teacher
+	resp := &dto.AvailabilityResponse{}
+	var schedules []*models.ScheduleData
+	for _, UUID := range UUIDs {
+		teacherUUID, err := uuid.Parse(UUID)
 		if err != nil {
-			return fmt.Errorf("%s: %w", op, err)
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 		}
+
+		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		resp.Teachers = append(resp.Teachers, strings.TrimSpace(
+			fmt.Sprintf("%v %v %v", teacher.SecondName, teacher.FirstName, teacher.MiddleName),
+		))
+
+		s, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+			return uc.cached.GetByTeacherUUID(ctx, teacherUUID, isSession)
+		})
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		schedules = append(schedules, s...)
 	}
 
-	return nil
+	// Getting bells from db
+	bells, err := uc.repoBell.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Locations with own bells, their slots may differ from default ones
+	var locations []string
+	for _, bell := range bells {
+		if bell.LocationUUID != nil && !slices.Contains(locations, bell.Location) {
+			locations = append(locations, bell.Location)
+		}
+	}
+	slices.Sort(locations)
+
+	// Collecting free default slots and free slots of locations of every working day
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Sunday {
+			continue
+		}
+
+		defaultSlots := uc.svcBell.Slots(bells, "", date)
+		day := dto.DayAvailability{Date: date.Format(time.DateOnly), Free: uc.freeSlots(schedules, date, defaultSlots)}
+		for _, location := range locations {
+			// Bells of location may be limited by dates, default slots are given already
+			slots := uc.svcBell.Slots(bells, location, date)
+			if slices.Equal(slots, defaultSlots) {
+				continue
+			}
+			day.Locations = append(day.Locations, dto.LocationAvailability{
+				Location: location,
+				Free:     uc.freeSlots(schedules, date, slots),
+			})
+		}
+		resp.Days = append(resp.Days, day)
+	}
+
+	return resp, nil
 }
 
-func (uc *ScheduleUseCase) Update(ctx context.Context, UUID string, scheduleDTO *dto.ScheduleRequest) error {
-	const op = "usecase.schedule.Update"
+// freeSlots returns slots on date in which none of schedules has a pair
+func (uc *ScheduleUseCase) freeSlots(schedules []*models.ScheduleData, date time.Time, slots []*models.BellSchedule) []dto.FreeSlot {
+	free := []dto.FreeSlot{}
+	for _, slot := range slots {
+		if uc.svc.IsBusy(schedules, date, slot.StartTime, slot.EndTime) {
+			continue
+		}
+		free = append(free, dto.FreeSlot{
+			PairNum:   slot.PairNum,
+			StartTime: slot.StartTime.Format(time.TimeOnly),
+			EndTime:   slot.EndTime.Format(time.TimeOnly),
+		})
+	}
 
-	// Parsing schedule UUID
-	scheduleUUID, err := uuid.Parse(UUID)
+	return free
+}
+
+// collectSchedules merges regular and session schedules, schedule is not found only if both are missing
+func collectSchedules(fetch func(isSession bool) ([]*models.ScheduleData, error)) ([]*models.ScheduleData, error) {
+	var schedules []*models.ScheduleData
+	for _, isSession := range []bool{false, true} {
+		s, err := fetch(isSession)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		schedules = append(schedules, s...)
+	}
+
+	if len(schedules) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	return schedules, nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByGroup(ctx context.Context, groupNumber string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByGroup"
+
+	groupNumber = strings.TrimSpace(groupNumber)
+
+	// Getting regular and session schedules from db with given group number
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByGroup(ctx, groupNumber, isSession)
+	})
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
 	}
 
-	// Getting old schedule
-	oldSchedule, err := uc.repo.GetForUpdate(ctx, scheduleUUID)
+	return uc.svc.MakeCalendar(groupNumber, schedules, time.Now()), nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByGroupUUID(ctx context.Context, UUID string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByGroupUUID"
+
+	// Parsing group uuid
+	groupUUID, err := uuid.Parse(UUID)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	// Getting old TToS
-	oldTTos, err := uc.repoTToS.GetByScheduleUUID(ctx, scheduleUUID)
-	if err != nil && !strings.Contains(err.Error(), "not found") {
-		return fmt.Errorf("%s: %w", op, err)
+	// Getting group for calendar name
+	group, err := uc.repoGroup.GetByUUID(ctx, groupUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	// Getting old RToS
-	oldRToS, err := uc.repoRToS.GetByScheduleUUID(ctx, scheduleUUID)
-	if err != nil && !strings.Contains(err.Error(), "not found") {
-		return fmt.Errorf("%s: %w", op, err)
+	// Getting regular and session schedules from db with given group uuid
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByGroupUUID(ctx, groupUUID, isSession)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
 	}
 
-	// DTO to model
-	newSchedule, err := uc.scheduleDTOToScheduleModel(ctx, scheduleDTO)
+	return uc.svc.MakeCalendar(group.Number, schedules, time.Now()), nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByTeacher(ctx context.Context, fn string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByTeacher"
+
+	fnArr := strings.Fields(fn)
+	if len(fnArr) < 2 || len(fnArr) > 3 {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("invalid fullname"))
+	}
+
+	// Getting regular and session schedules from db with given teacher fullname
+	fnArr = append(fnArr, "")
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByTeacher(ctx, fnArr[1], fnArr[0], fnArr[2], isSession)
+	})
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
+	}
+
+	return uc.svc.MakeCalendar(strings.TrimSpace(strings.Join(fnArr, " ")), schedules, time.Now()), nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByTeacherUUID(ctx context.Context, UUID string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByTeacherUUID"
+
+	// Parsing teacher uuid
+	teacherUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
-	newSchedule.UUID = scheduleUUID
 
-	// Updating schedule
-	err = uc.repo.Delete(ctx, scheduleUUID)
+	// Getting teacher for calendar name
+	teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = uc.repo.Create(ctx, newSchedule)
+	// Getting regular and session schedules from db with given teacher uuid
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByTeacherUUID(ctx, teacherUUID, isSession)
+	})
 	if err != nil {
-		er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-		if er != nil {
-			return fmt.Errorf("%s: %w, %w", op, err, er)
-		}
-		return fmt.Errorf("%s: %w", op, err)
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
 	}
 
-	// Adding teachers to created schedule
-	for _, UUID := range scheduleDTO.TeachersUUID {
-		teacherUUID, err := uuid.Parse(UUID)
+	name := strings.TrimSpace(fmt.Sprintf("%v %v %v", teacher.SecondName, teacher.FirstName, teacher.MiddleName))
+	return uc.svc.MakeCalendar(name, schedules, time.Now()), nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByRoom(ctx context.Context, roomNumber string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByRoom"
+
+	roomNumber = strings.TrimSpace(roomNumber)
+
+	// Getting regular and session schedules from db with given room number
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByRoom(ctx, roomNumber, isSession)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
+	}
+
+	return uc.svc.MakeCalendar(roomNumber, schedules, time.Now()), nil
+}
+
+func (uc *ScheduleUseCase) GetCalendarByRoomUUID(ctx context.Context, UUID string) ([]byte, error) {
+	const op = "usecase.schedule.GetCalendarByRoomUUID"
+
+	// Parsing room uuid
+	roomUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Getting room for calendar name
+	room, err := uc.repoRoom.GetByUUID(ctx, roomUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting regular and session schedules from db with given room uuid
+	schedules, err := collectSchedules(func(isSession bool) ([]*models.ScheduleData, error) {
+		return uc.cached.GetByRoomUUID(ctx, roomUUID, isSession)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: schedule %w", op, err)
+	}
+
+	return uc.svc.MakeCalendar(room.Number, schedules, time.Now()), nil
+}
+
+// Update replaces schedule with given uuid, conflicts are handled the same way as in Create
+func (uc *ScheduleUseCase) Update(ctx context.Context, UUID string, scheduleDTO *dto.ScheduleRequest, force bool) (*dto.ScheduleConflictsResponse, error) {
+	const op = "usecase.schedule.Update"
+
+	// Parsing schedule UUID
+	scheduleUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	var conflicts []*models.ScheduleConflict
+	var oldSchedule, updated *models.ScheduleData
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Locking old schedule
+		_, err := uc.repo.GetForUpdate(ctx, scheduleUUID)
 		if err != nil {
-			er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-			if er != nil {
-				return fmt.Errorf("%s: %w, %w", op, err, er)
-			}
-			return fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+		oldSchedule, err = uc.repo.GetByUUID(ctx, scheduleUUID)
 		if err != nil {
-			er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-			if er != nil {
-				return fmt.Errorf("%s: %w, %w", op, err, er)
-			}
-			return fmt.Errorf("%s: %w", op, err)
+			return err
 		}
 
-		err = uc.repoTToS.Create(ctx, &models.TeachersToSchedule{
-			TeacherUUID: teacher.UUID, ScheduleUUID: scheduleUUID,
-		})
+		// DTO to model
+		newSchedule, err := uc.scheduleDTOToScheduleModel(ctx, scheduleDTO)
 		if err != nil {
-			er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-			if er != nil {
-				return fmt.Errorf("%s: %w, %w", op, err, er)
-			}
-			return fmt.Errorf("%s: %w", op, err)
+			return err
 		}
-	}
+		newSchedule.UUID = scheduleUUID
 
-	// Adding rooms to created schedule
-	for _, roomNumber := range scheduleDTO.Rooms {
-		room, err := uc.repoRoom.GetByNumber(ctx, roomNumber)
+		// Pair can not be moved out of scopes of user or into them
+		err = uc.checkScope(ctx, oldSchedule.Group, oldSchedule.Location)
 		if err != nil {
-			er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-			if er != nil {
-				return fmt.Errorf("%s: %w, %w", op, err, er)
-			}
-			return fmt.Errorf("%s: %w", op, err)
+			return err
+		}
+		err = uc.checkScope(ctx, scheduleDTO.Group, scheduleDTO.Location)
+		if err != nil {
+			return err
 		}
 
-		err = uc.repoRToS.Create(ctx, &models.RoomsToSchedule{
-			RoomUUID: room.UUID, ScheduleUUID: scheduleUUID,
-		})
+		// Checking for double-booking
+		err = uc.lockConflicts(ctx, newSchedule)
 		if err != nil {
-			er := uc.rollbackUpdate(ctx, oldSchedule, oldTTos, oldRToS)
-			if er != nil {
-				return fmt.Errorf("%s: %w, %w", op, err, er)
-			}
-			return fmt.Errorf("%s: %w", op, err)
+			return err
+		}
+		conflicts, err = uc.findConflicts(ctx, newSchedule, scheduleDTO)
+		if err != nil {
+			return err
 		}
+		if len(conflicts) > 0 && !force {
+			return ErrScheduleConflict
+		}
+
+		// Replacing schedule with its teachers and rooms
+		err = uc.repo.Delete(ctx, scheduleUUID)
+		if err != nil {
+			return err
+		}
+
+		err = uc.repo.Create(ctx, newSchedule)
+		if err != nil {
+			return err
+		}
+
+		err = uc.linkSchedule(ctx, scheduleUUID, scheduleDTO)
+		if err != nil {
+			return err
+		}
+
+		// Recording change
+		updated, err = uc.repo.GetByUUID(ctx, scheduleUUID)
+		if err != nil {
+			return err
+		}
+		return uc.recordChange(ctx, models.ChangeUpdate, oldSchedule, updated)
+	})
+	if errors.Is(err, ErrScheduleConflict) {
+		return &dto.ScheduleConflictsResponse{Conflicts: conflicts}, fmt.Errorf("%s: %w", op, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	uc.invalidate(ctx, oldSchedule, updated)
 
-	return nil
+	return &dto.ScheduleConflictsResponse{Conflicts: conflicts}, nil
 }
 
 func (uc *ScheduleUseCase) Delete(ctx context.Context, UUID string) error {
@@ -638,11 +1215,32 @@ func (uc *ScheduleUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	// Deleting schedule from db with given uuid
-	err = uc.repo.Delete(ctx, scheduleUUID)
+	var schedule *models.ScheduleData
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Getting schedule for history
+		schedule, err = uc.repo.GetByUUID(ctx, scheduleUUID)
+		if err != nil {
+			return err
+		}
+
+		// Checking user can edit schedule of group at location
+		err = uc.checkScope(ctx, schedule.Group, schedule.Location)
+		if err != nil {
+			return err
+		}
+
+		// Deleting schedule from db with given uuid
+		err = uc.repo.Delete(ctx, scheduleUUID)
+		if err != nil {
+			return err
+		}
+
+		return uc.recordChange(ctx, models.ChangeDelete, schedule, nil)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	uc.invalidate(ctx, schedule)
 
 	return nil
 }
@@ -664,32 +1262,64 @@ func (uc *ScheduleUseCase) DeletePairsByGroupWeekdayTime(ctx context.Context, da
 		return fmt.Errorf("%s: invalid weekday", op)
 	}
 
-	// Parsing pair start time
-	var startTime time.Time
-	switch data.PairNum {
-	case 1:
-		startTime, _ = time.Parse(time.TimeOnly, "09:00:00")
-	case 2:
-		startTime, _ = time.Parse(time.TimeOnly, "10:40:00")
-	case 3:
-		startTime, _ = time.Parse(time.TimeOnly, "12:20:00")
-	case 4:
-		startTime, _ = time.Parse(time.TimeOnly, "14:30:00")
-	case 5:
-		startTime, _ = time.Parse(time.TimeOnly, "16:10:00")
-	case 6:
-		startTime, _ = time.Parse(time.TimeOnly, "17:50:00")
-	case 7:
-		startTime, _ = time.Parse(time.TimeOnly, "19:30:00")
-	default:
+	// Validation pair num
+	if data.PairNum < 1 {
 		return fmt.Errorf("%s: invalid pair num", op)
 	}
 
-	// Deleting schedule from db with given data
-	err = uc.repo.DeletePairsByGroupWeekdayTime(ctx, group.UUID, data.Weekday, startTime, data.StartDate, isSession)
+	// Getting group schedule from db
+	schedules, err := uc.repo.GetByGroupUUID(ctx, group.UUID, isSession)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting bells from db
+	bells, err := uc.repoBell.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Deleting pairs that start on bell of given pair num
+	var deleted []*models.ScheduleData
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		for _, schedule := range schedules {
+			if schedule.Weekday != data.Weekday ||
+				schedule.StartDate.Format(time.DateOnly) != data.StartDate.Format(time.DateOnly) {
+				continue
+			}
+
+			if uc.svcBell.PairNum(bells, schedule.Location, schedule.StartDate, schedule.StartTime) != data.PairNum {
+				continue
+			}
+
+			err := uc.checkScope(ctx, group.Number, schedule.Location)
+			if err != nil {
+				return err
+			}
+
+			err = uc.repo.Delete(ctx, schedule.UUID)
+			if err != nil {
+				return err
+			}
+
+			// Group schedule is selected without group number
+			schedule.Group = group.Number
+			err = uc.recordChange(ctx, models.ChangeDelete, schedule, nil)
+			if err != nil {
+				return err
+			}
+			deleted = append(deleted, schedule)
+		}
+
+		if len(deleted) == 0 {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	uc.invalidate(ctx, deleted...)
 
 	return nil
 }
@@ -737,21 +1367,69 @@ func (uc *ScheduleUseCase) DeleteByParams(ctx context.Context, params *dto.Delet
 		wd = int(t.Weekday())
 	}
 
-	err = uc.repo.DeleteByParams(ctx, &models.ScheduleData{
-		Group:     params.Group,
-		Subject:   params.Subject,
-		Type:      params.Type,
-		Location:  params.Location,
-		StartTime: st,
-		EndTime:   et,
-		StartDate: sd,
-		EndDate:   ed,
-		Weekday:   wd,
-		IsSession: params.IsSession,
+	var deletedSchedules []*models.ScheduleData
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		// Getting pairs that may be deleted for history
+		var schedules []*models.ScheduleData
+		var err error
+		if params.Group != "" {
+			schedules, err = uc.repo.GetByGroup(ctx, params.Group, params.IsSession)
+		} else {
+			schedules, err = uc.repo.Get(ctx)
+		}
+		if err != nil {
+			return err
+		}
+
+		deleted, err := uc.repo.DeleteByParams(ctx, &models.ScheduleData{
+			Group:     params.Group,
+			Subject:   params.Subject,
+			Type:      params.Type,
+			Location:  params.Location,
+			StartTime: st,
+			EndTime:   et,
+			StartDate: sd,
+			EndDate:   ed,
+			Weekday:   wd,
+			IsSession: params.IsSession,
+		})
+		if err != nil {
+			return err
+		}
+
+		byUUID := make(map[uuid.UUID]*models.ScheduleData, len(schedules))
+		for _, schedule := range schedules {
+			if params.Group != "" {
+				schedule.Group = params.Group
+			}
+			byUUID[schedule.UUID] = schedule
+		}
+		for _, UUID := range deleted {
+			schedule, ok := byUUID[UUID]
+			if !ok {
+				schedule = &models.ScheduleData{UUID: UUID, Group: params.Group}
+			}
+
+			err = uc.recordChange(ctx, models.ChangeDelete, schedule, nil)
+			if err != nil {
+				return err
+			}
+			deletedSchedules = append(deletedSchedules, schedule)
+		}
+
+		return nil
 	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
+	uc.invalidate(ctx, deletedSchedules...)
 
 	return nil
 }
+
+// invalidate drops cached queries with changed pairs, it is called after transaction is committed,
+// so queries can not be cached again with data before the change.
+// Error is ignored, queries that were not dropped expire by TTL
+func (uc *ScheduleUseCase) invalidate(ctx context.Context, schedules ...*models.ScheduleData) {
+	_ = uc.cached.Invalidate(ctx, schedules...)
+}
@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+// writeResource runs write of group, teacher, room, subject or location and drops cached schedule of it.
+// Names are names of resource before and after write, pairs are taken before write as pairs of deleted
+// teacher or room lose it. Cache is nil in parser, it neither renames nor deletes resources.
+// Errors of cache are ignored, queries that were not dropped expire by TTL
+func writeResource(
+	ctx context.Context, cache interfaces.ScheduleCacheInvalidator,
+	kind string, resourceUUID uuid.UUID, names []string, write func() error,
+) error {
+	if cache == nil {
+		return write()
+	}
+
+	schedules, _ := cache.SchedulesOf(ctx, kind, resourceUUID)
+
+	if err := write(); err != nil {
+		return err
+	}
+
+	_ = cache.InvalidateResource(ctx, kind, names, schedules)
+	return nil
+}
+
+// teacherName is name of teacher in schedule
+func teacherName(teacher *models.Teacher) string {
+	return strings.TrimSpace(fmt.Sprintf("%v %v %v", teacher.SecondName, teacher.FirstName, teacher.MiddleName))
+}
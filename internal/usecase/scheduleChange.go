@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"strings"
+	"time"
+)
+
+type changeSourceKey struct{}
+
+// WithChangeSource sets who makes schedule changes with given context, e.g. "parser:<run id>"
+func WithChangeSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, changeSourceKey{}, source)
+}
+
+// changeSource returns source set by WithChangeSource, falling back to username set by auth middleware
+func changeSource(ctx context.Context) string {
+	if source, ok := ctx.Value(changeSourceKey{}).(string); ok && source != "" {
+		return source
+	}
+	if username := contextUsername(ctx); username != "" {
+		return "user:" + username
+	}
+	return "unknown"
+}
+
+// contextUsername returns username set by auth middleware
+func contextUsername(ctx context.Context) string {
+	username, _ := ctx.Value("username").(string)
+	return username
+}
+
+// recordChange appends change of pair to schedule history, before is nil for created pairs and after for deleted ones
+func (uc *ScheduleUseCase) recordChange(ctx context.Context, action string, before, after *models.ScheduleData) error {
+	const op = "usecase.schedule.recordChange"
+
+	changeUUID, err := uuid.NewUUID()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+
+	change := &models.ScheduleChange{
+		UUID:      changeUUID,
+		Action:    action,
+		Before:    before,
+		After:     after,
+		Source:    changeSource(ctx),
+		CreatedAt: time.Now(),
+	}
+	if after != nil {
+		change.ScheduleUUID, change.Group = after.UUID, after.Group
+	} else {
+		change.ScheduleUUID, change.Group = before.UUID, before.Group
+	}
+
+	err = uc.repoChange.Create(ctx, change)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = uc.notifyWebhooks(ctx, change)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// notifyWebhooks queues change for delivery to webhooks subscribed to its group, teachers or rooms
+func (uc *ScheduleUseCase) notifyWebhooks(ctx context.Context, change *models.ScheduleChange) error {
+	const op = "usecase.schedule.notifyWebhooks"
+
+	groups, teachers, rooms := uc.svcWebhook.Resources(change)
+	webhooks, err := uc.repoWebhook.GetByResources(ctx, groups, teachers, rooms)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, webhook := range webhooks {
+		deliveryUUID, err := uuid.NewUUID()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+		}
+
+		payload, err := json.Marshal(&dto.WebhookPayload{
+			Event:    models.EventScheduleChanged,
+			Delivery: deliveryUUID,
+			Webhook:  webhook.UUID,
+			Change:   change,
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		err = uc.repoDelivery.Create(ctx, &models.WebhookDelivery{
+			UUID:          deliveryUUID,
+			WebhookUUID:   webhook.UUID,
+			Event:         models.EventScheduleChanged,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return nil
+}
+
+// GetChanges returns schedule changes of group made since given date or RFC 3339 time, last 24 hours by default.
+// Date is start of day in time zone of schedule. Changes of every group are returned if group number is empty.
+// Without withSource usernames of editors are hidden, source tells only whether user or parser made change
+func (uc *ScheduleUseCase) GetChanges(ctx context.Context, groupNumber, since string, withSource bool) (*dto.ScheduleChangesResponse, error) {
+	const op = "usecase.schedule.GetChanges"
+
+	from := time.Now().Add(-24 * time.Hour)
+	if since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			t, err = time.ParseInLocation(time.DateOnly, since, services.ScheduleZone)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, ErrInvalidDate)
+			}
+		}
+		from = t
+	}
+
+	if groupNumber != "" {
+		_, err := uc.repoGroup.GetByNumber(ctx, groupNumber)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	changes, err := uc.repoChange.GetByGroup(ctx, groupNumber, from)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if changes == nil {
+		changes = []*models.ScheduleChange{}
+	}
+	if !withSource {
+		for _, change := range changes {
+			change.Source = sourceKind(change.Source)
+		}
+	}
+
+	return &dto.ScheduleChangesResponse{Changes: changes}, nil
+}
+
+// sourceKind returns kind of change source without username or run, e.g. "user" for "user:<username>"
+func sourceKind(source string) string {
+	kind, _, _ := strings.Cut(source, ":")
+	return kind
+}
@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"raspyx/internal/repository"
+	"testing"
+	"time"
+)
+
+type stubTeacherRepository struct {
+	interfaces.TeacherRepository
+	teacher *models.Teacher
+}
+
+func (r stubTeacherRepository) GetByUUID(_ context.Context, _ uuid.UUID) (*models.Teacher, error) {
+	return r.teacher, nil
+}
+
+type stubBellScheduleRepository struct {
+	interfaces.BellScheduleRepository
+	bells []*models.BellSchedule
+}
+
+func (r stubBellScheduleRepository) Get(_ context.Context) ([]*models.BellSchedule, error) {
+	return r.bells, nil
+}
+
+// stubCachedScheduleRepository returns regular pairs of teacher, teacher has no session pairs
+type stubCachedScheduleRepository struct {
+	interfaces.CachedScheduleRepository
+	schedules []*models.ScheduleData
+}
+
+func (r stubCachedScheduleRepository) GetByTeacherUUID(_ context.Context, _ uuid.UUID, isSession bool) ([]*models.ScheduleData, error) {
+	if isSession {
+		return nil, repository.ErrNotFound
+	}
+	return r.schedules, nil
+}
+
+func TestScheduleUseCase_GetTeachersAvailabilityByLocation(t *testing.T) {
+	clock := func(value string) time.Time {
+		tm, _ := time.Parse(time.TimeOnly, value)
+		return tm
+	}
+	bell := func(location string, pairNum int, start, end string) *models.BellSchedule {
+		b := &models.BellSchedule{PairNum: pairNum, StartTime: clock(start), EndTime: clock(end)}
+		if location != "" {
+			locationUUID := uuid.New()
+			b.LocationUUID, b.Location = &locationUUID, location
+		}
+		return b
+	}
+
+	// Teacher has pair in second default slot, second slot of other location is later and free
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	uc := &ScheduleUseCase{
+		cached: stubCachedScheduleRepository{schedules: []*models.ScheduleData{{
+			Location:  "Автозаводская",
+			StartTime: clock("10:40:00"),
+			EndTime:   clock("12:10:00"),
+			StartDate: monday,
+			EndDate:   monday.AddDate(0, 3, 0),
+			Weekday:   int(time.Monday),
+		}}},
+		repoTeacher: stubTeacherRepository{teacher: &models.Teacher{FirstName: "Имя", SecondName: "Фамилия"}},
+		repoBell: stubBellScheduleRepository{bells: []*models.BellSchedule{
+			bell("", 1, "09:00:00", "10:30:00"),
+			bell("", 2, "10:40:00", "12:10:00"),
+			bell("Прянишникова", 2, "12:20:00", "13:50:00"),
+		}},
+		svc:     *services.NewScheduleService(),
+		svcBell: *services.NewBellScheduleService(),
+	}
+
+	resp, err := uc.GetTeachersAvailability(context.Background(), []string{uuid.NewString()}, "2025-03-10", "2025-03-10")
+	require.NoError(t, err)
+	require.Len(t, resp.Days, 1)
+
+	day := resp.Days[0]
+	assert.Equal(t, []dto.FreeSlot{{PairNum: 1, StartTime: "09:00:00", EndTime: "10:30:00"}}, day.Free)
+	assert.Equal(t, []dto.LocationAvailability{{
+		Location: "Прянишникова",
+		Free: []dto.FreeSlot{
+			{PairNum: 1, StartTime: "09:00:00", EndTime: "10:30:00"},
+			{PairNum: 2, StartTime: "12:20:00", EndTime: "13:50:00"},
+		},
+	}}, day.Locations)
+}
+
+// stubScheduleChangeRepository returns given changes and records time they are requested since
+type stubScheduleChangeRepository struct {
+	interfaces.ScheduleChangeRepository
+	changes []*models.ScheduleChange
+	since   time.Time
+}
+
+func (r *stubScheduleChangeRepository) GetByGroup(_ context.Context, _ string, since time.Time) ([]*models.ScheduleChange, error) {
+	r.since = since
+	changes := make([]*models.ScheduleChange, 0, len(r.changes))
+	for _, change := range r.changes {
+		c := *change
+		changes = append(changes, &c)
+	}
+	return changes, nil
+}
+
+func TestScheduleUseCase_GetChanges(t *testing.T) {
+	repo := &stubScheduleChangeRepository{changes: []*models.ScheduleChange{
+		{Source: "user:editor"},
+		{Source: "parser:c555b9e8-0d7a-11f0-adcd-20114d2008d9"},
+	}}
+	uc := &ScheduleUseCase{repoChange: repo}
+
+	resp, err := uc.GetChanges(context.Background(), "", "2025-03-12", false)
+	require.NoError(t, err)
+	assert.Equal(t, "user", resp.Changes[0].Source)
+	assert.Equal(t, "parser", resp.Changes[1].Source)
+
+	// Date starts at midnight of Moscow
+	assert.True(t, repo.since.Equal(time.Date(2025, 3, 11, 21, 0, 0, 0, time.UTC)), "since = %v", repo.since)
+
+	resp, err = uc.GetChanges(context.Background(), "", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "user:editor", resp.Changes[0].Source)
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/models"
+	"strings"
+)
+
+// ScopeError is returned when user edits pair of group and location not covered by editing scopes of user
+type ScopeError struct {
+	Group    string
+	Location string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("%v: group %v at location %v", ErrOutOfScope, e.Group, e.Location)
+}
+
+func (e *ScopeError) Unwrap() error {
+	return ErrOutOfScope
+}
+
+// contextUserUUID returns uuid of user set by auth middleware, uuid.Nil if changes are made not by user
+func contextUserUUID(ctx context.Context) uuid.UUID {
+	userUUID, _ := ctx.Value("user_uuid").(string)
+	parsed, err := uuid.Parse(userUUID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return parsed
+}
+
+// checkScope checks that user of ctx can edit pair of group at location.
+// Parser and users without scopes edit every schedule
+func (uc *ScheduleUseCase) checkScope(ctx context.Context, groupNumber, locationName string) error {
+	userUUID := contextUserUUID(ctx)
+	if userUUID == uuid.Nil {
+		return nil
+	}
+
+	scopes, err := uc.repoScope.GetByUser(ctx, userUUID)
+	if err != nil {
+		return err
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	// Group and location are got from db once they are needed
+	var group *models.Group
+	var location *models.Location
+	for _, scope := range scopes {
+		switch {
+		case scope.GroupPrefix != "":
+			if strings.HasPrefix(groupNumber, scope.GroupPrefix) {
+				return nil
+			}
+		case scope.GroupUUID != nil:
+			if group == nil {
+				group, err = uc.repoGroup.GetByNumber(ctx, groupNumber)
+				if err != nil {
+					return err
+				}
+			}
+			if group.UUID == *scope.GroupUUID {
+				return nil
+			}
+		case scope.LocationUUID != nil:
+			if location == nil {
+				location, err = uc.repoLocation.GetByName(ctx, locationName)
+				if err != nil {
+					return err
+				}
+			}
+			if location.UUID == *scope.LocationUUID {
+				return nil
+			}
+		}
+	}
+
+	return &ScopeError{Group: groupNumber, Location: locationName}
+}
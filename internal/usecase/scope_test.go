@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"raspyx/internal/domain/interfaces/mocks"
+	"raspyx/internal/domain/models"
+	"testing"
+)
+
+type stubScopeRepository struct {
+	scopes []*models.UserScope
+}
+
+func (r *stubScopeRepository) GetByUser(_ context.Context, _ uuid.UUID) ([]*models.UserScope, error) {
+	return r.scopes, nil
+}
+
+func (r *stubScopeRepository) Replace(_ context.Context, _ uuid.UUID, _ []*models.UserScope) error {
+	return nil
+}
+
+func TestScheduleUseCase_checkScope(t *testing.T) {
+	groupUUID := uuid.New()
+	userUUID := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		userUUID  string
+		scopes    []*models.UserScope
+		group     string
+		wantScope bool
+	}{
+		{
+			name:     "Changes without user",
+			userUUID: "",
+			scopes:   []*models.UserScope{{GroupPrefix: "231"}},
+			group:    "221-352",
+		},
+		{
+			name:     "User without scopes",
+			userUUID: userUUID,
+			group:    "221-352",
+		},
+		{
+			name:     "Group prefix in scope",
+			userUUID: userUUID,
+			scopes:   []*models.UserScope{{GroupPrefix: "221"}},
+			group:    "221-352",
+		},
+		{
+			name:     "Group in scope",
+			userUUID: userUUID,
+			scopes:   []*models.UserScope{{GroupPrefix: "231"}, {GroupUUID: &groupUUID}},
+			group:    "221-352",
+		},
+		{
+			name:      "Group out of scope",
+			userUUID:  userUUID,
+			scopes:    []*models.UserScope{{GroupPrefix: "231"}, {GroupUUID: &groupUUID}},
+			group:     "221-353",
+			wantScope: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockGroupRepo := new(mocks.GroupRepository)
+			mockGroupRepo.On("GetByNumber", mock.Anything, "221-352").
+				Return(&models.Group{UUID: groupUUID, Number: "221-352"}, nil).Maybe()
+			mockGroupRepo.On("GetByNumber", mock.Anything, "221-353").
+				Return(&models.Group{UUID: uuid.New(), Number: "221-353"}, nil).Maybe()
+
+			uc := &ScheduleUseCase{
+				repoGroup: mockGroupRepo,
+				repoScope: &stubScopeRepository{scopes: tt.scopes},
+			}
+
+			ctx := context.WithValue(context.Background(), "user_uuid", tt.userUUID)
+			err := uc.checkScope(ctx, tt.group, "Пр")
+
+			if !tt.wantScope {
+				assert.NoError(t, err)
+				return
+			}
+
+			var scopeErr *ScopeError
+			assert.True(t, errors.As(err, &scopeErr))
+			assert.ErrorIs(t, err, ErrOutOfScope)
+			assert.Equal(t, tt.group, scopeErr.Group)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"slices"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Number of search hits returned by default and at most, and longest query
+const (
+	searchLimit     = 10
+	searchMaxLimit  = 50
+	searchMaxLength = 100
+)
+
+type SearchUseCase struct {
+	repo interfaces.SearchRepository
+	svc  services.SearchService
+}
+
+func NewSearchUseCase(repo interfaces.SearchRepository, svc services.SearchService) *SearchUseCase {
+	return &SearchUseCase{repo: repo, svc: svc}
+}
+
+// Search returns groups, teachers, rooms and subjects ranked by similarity to query
+func (uc *SearchUseCase) Search(ctx context.Context, params *dto.SearchParams) (*dto.SearchResponse, error) {
+	const op = "usecase.search.Search"
+
+	text := uc.svc.Normalize(params.Query)
+	if text == "" || utf8.RuneCountInString(text) > searchMaxLength {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidSearchQuery)
+	}
+
+	query := &models.SearchQuery{Terms: uc.svc.Terms(text), Types: params.Types, Limit: searchLimit}
+	if len(query.Types) == 0 {
+		query.Types = models.SearchTypes
+	}
+	for _, typ := range query.Types {
+		if !slices.Contains(models.SearchTypes, typ) {
+			return nil, fmt.Errorf("%s: %w %q", op, ErrInvalidSearchType, typ)
+		}
+	}
+
+	if params.Limit != "" {
+		limit, err := strconv.Atoi(params.Limit)
+		if err != nil || limit < 1 || limit > searchMaxLimit {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidLimit)
+		}
+		query.Limit = limit
+	}
+
+	// Every spelling of query may be written with initials, e.g. "Ivanov I.I."
+	for _, term := range query.Terms {
+		if initials, ok := uc.svc.Initials(term); ok && !slices.Contains(query.Initials, initials) {
+			query.Initials = append(query.Initials, initials)
+		}
+	}
+
+	hits, err := uc.repo.Search(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.SearchResponse{Hits: hits}, nil
+}
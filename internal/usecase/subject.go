@@ -11,12 +11,13 @@ import (
 )
 
 type SubjectUseCase struct {
-	repo interfaces.SubjectRepository
-	svc  services.SubjectService
+	repo  interfaces.SubjectRepository
+	svc   services.SubjectService
+	cache interfaces.ScheduleCacheInvalidator
 }
 
-func NewSubjectUseCase(repo interfaces.SubjectRepository, svc services.SubjectService) *SubjectUseCase {
-	return &SubjectUseCase{repo: repo, svc: svc}
+func NewSubjectUseCase(repo interfaces.SubjectRepository, svc services.SubjectService, cache interfaces.ScheduleCacheInvalidator) *SubjectUseCase {
+	return &SubjectUseCase{repo: repo, svc: svc, cache: cache}
 }
 func (uc *SubjectUseCase) Create(ctx context.Context, SubjectDTO *dto.CreateSubjectRequest) (*dto.CreateSubjectResponse, error) {
 	const op = "usecase.subject.Create"
@@ -39,16 +40,21 @@ func (uc *SubjectUseCase) Create(ctx context.Context, SubjectDTO *dto.CreateSubj
 	return &dto.CreateSubjectResponse{UUID: subject.UUID}, nil
 }
 
-func (uc *SubjectUseCase) Get(ctx context.Context) ([]*models.Subject, error) {
+func (uc *SubjectUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetSubjectsResponse, error) {
 	const op = "usecase.subject.Get"
 
-	// Getting all subjects from db
-	subjects, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.SubjectListFields, "name")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return subjects, nil
+	// Getting page of subjects from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetSubjectsResponse{Subjects: page.Items, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *SubjectUseCase) GetByUUID(ctx context.Context, UUID string) (*models.Subject, error) {
@@ -90,8 +96,18 @@ func (uc *SubjectUseCase) Update(ctx context.Context, UUID string, subjectDTO *d
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	subject := &models.Subject{UUID: subjectUUID, Name: subjectDTO.Name}
+
+	// Old name is needed to drop cached schedule of it
+	old, err := uc.repo.GetByUUID(ctx, subjectUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Updating subject in db with given subject
-	err = uc.repo.Update(ctx, &models.Subject{UUID: subjectUUID, Name: subjectDTO.Name})
+	err = writeResource(ctx, uc.cache, models.ResourceSubject, subjectUUID, []string{old.Name, subject.Name}, func() error {
+		return uc.repo.Update(ctx, subject)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -108,8 +124,15 @@ func (uc *SubjectUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	old, err := uc.repo.GetByUUID(ctx, subjectUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Deleting subject from db with given uuid
-	err = uc.repo.Delete(ctx, subjectUUID)
+	err = writeResource(ctx, uc.cache, models.ResourceSubject, subjectUUID, []string{old.Name}, func() error {
+		return uc.repo.Delete(ctx, subjectUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -39,16 +39,21 @@ func (uc *SubjectTypeUseCase) Create(ctx context.Context, subjectTypeDTO *dto.Cr
 	return &dto.CreateSubjectTypeResponse{UUID: subjectType.UUID}, nil
 }
 
-func (uc *SubjectTypeUseCase) Get(ctx context.Context) ([]*models.SubjectType, error) {
+func (uc *SubjectTypeUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetSubjectTypesResponse, error) {
 	const op = "usecase.subjectType.Get"
 
-	// Getting all subjectTypes from db
-	subjectTypes, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.SubjectTypeListFields, "type")
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return subjectTypes, nil
+	// Getting page of subjectTypes from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.GetSubjectTypesResponse{SubjectTypes: page.Items, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *SubjectTypeUseCase) GetByUUID(ctx context.Context, UUID string) (*models.SubjectType, error) {
@@ -11,12 +11,13 @@ import (
 )
 
 type TeacherUseCase struct {
-	repo interfaces.TeacherRepository
-	svc  services.TeacherService
+	repo  interfaces.TeacherRepository
+	svc   services.TeacherService
+	cache interfaces.ScheduleCacheInvalidator
 }
 
-func NewTeacherUseCase(repo interfaces.TeacherRepository, svc services.TeacherService) *TeacherUseCase {
-	return &TeacherUseCase{repo: repo, svc: svc}
+func NewTeacherUseCase(repo interfaces.TeacherRepository, svc services.TeacherService, cache interfaces.ScheduleCacheInvalidator) *TeacherUseCase {
+	return &TeacherUseCase{repo: repo, svc: svc, cache: cache}
 }
 func (uc *TeacherUseCase) Create(ctx context.Context, teacherDTO *dto.CreateTeacherRequest) (*dto.CreateTeacherResponse, error) {
 	const op = "usecase.teacher.Create"
@@ -44,13 +45,22 @@ func (uc *TeacherUseCase) Create(ctx context.Context, teacherDTO *dto.CreateTeac
 	return &dto.CreateTeacherResponse{UUID: teacher.UUID}, nil
 }
 
-func (uc *TeacherUseCase) Get(ctx context.Context) ([]*dto.TeacherDTO, error) {
+func (uc *TeacherUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetTeachersResponse, error) {
 	const op = "usecase.teacher.Get"
 
-	// Getting all teachers from db
-	teachers, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.TeacherListFields, "second_name")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting page of teachers from db
+	page, err := uc.repo.List(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	var teachersDTO []*dto.TeacherDTO
-	for _, teacher := range teachers {
+	for _, teacher := range page.Items {
 		teachersDTO = append(teachersDTO, &dto.TeacherDTO{
 			Name: fmt.Sprintf("%v %v %v",
 				teacher.SecondName,
@@ -58,11 +68,8 @@ func (uc *TeacherUseCase) Get(ctx context.Context) ([]*dto.TeacherDTO, error) {
 				teacher.MiddleName,
 			)})
 	}
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
 
-	return teachersDTO, nil
+	return &dto.GetTeachersResponse{Teachers: teachersDTO, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *TeacherUseCase) GetByUUID(ctx context.Context, UUID string) (*models.Teacher, error) {
@@ -104,12 +111,22 @@ func (uc *TeacherUseCase) Update(ctx context.Context, UUID string, teacherDTO *d
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	// Updating teacher in db with given teacher
-	err = uc.repo.Update(ctx, &models.Teacher{
+	teacher := &models.Teacher{
 		UUID:       teacherUUID,
 		FirstName:  teacherDTO.FirstName,
 		SecondName: teacherDTO.SecondName,
 		MiddleName: teacherDTO.MiddleName,
+	}
+
+	// Old name is needed to drop cached schedule of it
+	old, err := uc.repo.GetByUUID(ctx, teacherUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Updating teacher in db with given teacher
+	err = writeResource(ctx, uc.cache, models.ResourceTeacher, teacherUUID, []string{teacherName(old), teacherName(teacher)}, func() error {
+		return uc.repo.Update(ctx, teacher)
 	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
@@ -127,8 +144,15 @@ func (uc *TeacherUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
-	// Deleting teacher from db with given uuid
-	err = uc.repo.Delete(ctx, teacherUUID)
+	old, err := uc.repo.GetByUUID(ctx, teacherUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Deleting teacher from db with given uuid, its pairs lose it
+	err = writeResource(ctx, uc.cache, models.ResourceTeacher, teacherUUID, []string{teacherName(old)}, func() error {
+		return uc.repo.Delete(ctx, teacherUUID)
+	})
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
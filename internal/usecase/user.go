@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"raspyx/config"
@@ -9,17 +10,29 @@ import (
 	"raspyx/internal/domain/models"
 	"raspyx/internal/domain/services"
 	"raspyx/internal/dto"
-	"strconv"
+	"raspyx/internal/repository"
 	"strings"
+	"time"
 )
 
 type UserUseCase struct {
-	repo interfaces.UserRepository
-	svc  services.UserService
+	repo      interfaces.UserRepository
+	roleRepo  interfaces.RoleRepository
+	tokenRepo interfaces.RefreshTokenRepository
+	denylist  interfaces.TokenDenylist
+	svc       services.UserService
+	jwt       config.JWT
 }
 
-func NewUserUseCase(repo interfaces.UserRepository, svc services.UserService) *UserUseCase {
-	return &UserUseCase{repo: repo, svc: svc}
+func NewUserUseCase(
+	repo interfaces.UserRepository,
+	roleRepo interfaces.RoleRepository,
+	tokenRepo interfaces.RefreshTokenRepository,
+	denylist interfaces.TokenDenylist,
+	svc services.UserService,
+	jwt config.JWT,
+) *UserUseCase {
+	return &UserUseCase{repo: repo, roleRepo: roleRepo, tokenRepo: tokenRepo, denylist: denylist, svc: svc, jwt: jwt}
 }
 func (uc *UserUseCase) Create(ctx context.Context, userDTO *dto.RegisterUserRequest) (*dto.RegisterUserResponse, error) {
 	const op = "usecase.user.Create"
@@ -31,7 +44,7 @@ func (uc *UserUseCase) Create(ctx context.Context, userDTO *dto.RegisterUserRequ
 	}
 
 	// DTO to model
-	user := &models.User{UUID: newUUID, Username: userDTO.Username, AccessLevel: 0}
+	user := &models.User{UUID: newUUID, Username: userDTO.Username, Role: models.RoleViewer}
 
 	// User validation
 	valid := uc.svc.Validate(user)
@@ -55,7 +68,7 @@ func (uc *UserUseCase) Create(ctx context.Context, userDTO *dto.RegisterUserRequ
 	return &dto.RegisterUserResponse{UUID: user.UUID}, nil
 }
 
-func (uc *UserUseCase) Login(ctx context.Context, jwt config.JWT, userDTO *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
+func (uc *UserUseCase) Login(ctx context.Context, userDTO *dto.LoginUserRequest) (*dto.LoginUserResponse, error) {
 	const op = "usecase.user.Login"
 
 	// Getting user from db with given username
@@ -71,34 +84,226 @@ func (uc *UserUseCase) Login(ctx context.Context, jwt config.JWT, userDTO *dto.L
 		return nil, fmt.Errorf("%s: %w", op, ErrInvalidCreds)
 	}
 
-	token, err := uc.svc.CreateJWT(user.Username, user.AccessLevel, jwt)
+	// Starting new session
+	family, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+
+	resp, err := uc.issueTokens(ctx, user, family)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &dto.LoginUserResponse{Token: token, TokenType: "bearer"}, nil
+	return resp, nil
 }
 
-func (uc *UserUseCase) Get(ctx context.Context) ([]*dto.UserDTO, error) {
+// Refresh rotates refresh token: it is revoked and new pair of tokens is issued in the same session.
+// Reuse of rotated token means it leaked, so the whole session is revoked
+func (uc *UserUseCase) Refresh(ctx context.Context, refreshDTO *dto.RefreshTokenRequest) (*dto.LoginUserResponse, error) {
+	const op = "usecase.user.Refresh"
+
+	// Getting refresh token from db with given token
+	token, err := uc.tokenRepo.GetByHash(ctx, uc.svc.HashRefreshToken(refreshDTO.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if token.RevokedAt != nil {
+		if err := uc.revokeFamily(ctx, token.Family); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	// Revoking rotated token, it fails if concurrent request rotated it first
+	err = uc.tokenRepo.Revoke(ctx, token.UUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			if err := uc.revokeFamily(ctx, token.Family); err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Access token of rotated token is replaced by the new one
+	err = uc.denyAccessTokens(ctx, []uuid.UUID{token.AccessJTI})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Role of user could change since login, so claims are taken from db
+	user, err := uc.repo.GetByUUID(ctx, token.UserUUID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := uc.issueTokens(ctx, user, token.Family)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp, nil
+}
+
+// Logout revokes access token with given jti until it expires and session of given refresh token
+func (uc *UserUseCase) Logout(ctx context.Context, userUUID, jti string, exp time.Time, logoutDTO *dto.LogoutRequest) error {
+	const op = "usecase.user.Logout"
+
+	err := uc.denylist.Deny(ctx, jti, time.Until(exp))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if logoutDTO.RefreshToken == "" {
+		return nil
+	}
+
+	// Getting refresh token from db with given token
+	token, err := uc.tokenRepo.GetByHash(ctx, uc.svc.HashRefreshToken(logoutDTO.RefreshToken))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Users can not end sessions of each other
+	if token.UserUUID.String() != userUUID {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	err = uc.revokeFamily(ctx, token.Family)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// issueTokens creates access token and refresh token of session family
+func (uc *UserUseCase) issueTokens(ctx context.Context, user *models.User, family uuid.UUID) (*dto.LoginUserResponse, error) {
+	// Permissions of role are embedded in token
+	role, err := uc.roleRepo.GetByName(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	jti, err := uuid.NewRandom()
+	if err != nil {
+		return nil, ErrGeneratingUUID
+	}
+	access, err := uc.svc.CreateJWT(user, role.Permissions, jti, uc.jwt)
+	if err != nil {
+		return nil, err
+	}
+
+	refresh, err := uc.svc.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	tokenUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, ErrGeneratingUUID
+	}
+
+	now := time.Now()
+	err = uc.tokenRepo.Create(ctx, &models.RefreshToken{
+		UUID:      tokenUUID,
+		UserUUID:  user.UUID,
+		Family:    family,
+		TokenHash: uc.svc.HashRefreshToken(refresh),
+		AccessJTI: jti,
+		CreatedAt: now,
+		ExpiresAt: now.Add(uc.svc.RefreshTTL(uc.jwt)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginUserResponse{
+		Token:        access,
+		TokenType:    "bearer",
+		ExpiresIn:    int(uc.svc.AccessTTL(uc.jwt).Seconds()),
+		RefreshToken: refresh,
+	}, nil
+}
+
+// revokeFamily revokes session and access tokens issued in it
+func (uc *UserUseCase) revokeFamily(ctx context.Context, family uuid.UUID) error {
+	jtis, err := uc.tokenRepo.RevokeFamily(ctx, family)
+	if err != nil {
+		return err
+	}
+
+	return uc.denyAccessTokens(ctx, jtis)
+}
+
+// revokeAccessTokens denies every access token of user which may not be expired yet, so changes of user
+// take effect immediately. Access tokens are issued with refresh tokens, so they are found by refresh tokens
+// created within AccessTTL, revoked ones too. Sessions are kept, tokens refreshed in them get actual claims
+func (uc *UserUseCase) revokeAccessTokens(ctx context.Context, userUUID uuid.UUID) error {
+	tokens, err := uc.tokenRepo.GetCreatedSince(ctx, userUUID, time.Now().Add(-uc.svc.AccessTTL(uc.jwt)))
+	if err != nil {
+		return err
+	}
+
+	jtis := make([]uuid.UUID, 0, len(tokens))
+	for _, token := range tokens {
+		jtis = append(jtis, token.AccessJTI)
+	}
+
+	return uc.denyAccessTokens(ctx, jtis)
+}
+
+func (uc *UserUseCase) denyAccessTokens(ctx context.Context, jtis []uuid.UUID) error {
+	// Access token lives no longer than AccessTTL
+	for _, jti := range jtis {
+		err := uc.denylist.Deny(ctx, jti.String(), uc.svc.AccessTTL(uc.jwt))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uc *UserUseCase) Get(ctx context.Context, params *dto.ListParams) (*dto.GetUsersResponse, error) {
 	const op = "usecase.user.Get"
 
-	// Getting all users from db
-	users, err := uc.repo.Get(ctx)
+	query, err := parseListQuery(params, models.UserListFields, "username")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting page of users from db
+	page, err := uc.repo.List(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	// Model to DTO
 	var usersDTO []*dto.UserDTO
-	for _, user := range users {
+	for _, user := range page.Items {
 		usersDTO = append(usersDTO, &dto.UserDTO{
-			UUID:        user.UUID,
-			Username:    user.Username,
-			AccessLevel: user.AccessLevel,
+			UUID:     user.UUID,
+			Username: user.Username,
+			Role:     user.Role,
 		})
 	}
 
-	return usersDTO, nil
+	return &dto.GetUsersResponse{Users: usersDTO, Pagination: makePagination(page, query)}, nil
 }
 
 func (uc *UserUseCase) GetByUUID(ctx context.Context, UUID string) (*dto.UserDTO, error) {
@@ -118,9 +323,9 @@ func (uc *UserUseCase) GetByUUID(ctx context.Context, UUID string) (*dto.UserDTO
 
 	// Model to DTO
 	userDTO := &dto.UserDTO{
-		UUID:        user.UUID,
-		Username:    user.Username,
-		AccessLevel: user.AccessLevel,
+		UUID:     user.UUID,
+		Username: user.Username,
+		Role:     user.Role,
 	}
 
 	return userDTO, nil
@@ -137,25 +342,19 @@ func (uc *UserUseCase) GetByUsername(ctx context.Context, username string) (*dto
 
 	// Model to DTO
 	userDTO := &dto.UserDTO{
-		UUID:        user.UUID,
-		Username:    user.Username,
-		AccessLevel: user.AccessLevel,
+		UUID:     user.UUID,
+		Username: user.Username,
+		Role:     user.Role,
 	}
 
 	return userDTO, nil
 }
 
-func (uc *UserUseCase) GetByAccessLevel(ctx context.Context, accessLevel string) ([]*dto.UserDTO, error) {
-	const op = "usecase.user.GetByAccessLevel"
+func (uc *UserUseCase) GetByRole(ctx context.Context, role string) ([]*dto.UserDTO, error) {
+	const op = "usecase.user.GetByRole"
 
-	// Converting access level to int
-	al, err := strconv.Atoi(accessLevel)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
-	}
-
-	// Getting users from db with given AccessLevel
-	users, err := uc.repo.GetByAccessLevel(ctx, al)
+	// Getting users from db with given role
+	users, err := uc.repo.GetByRole(ctx, role)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -164,15 +363,27 @@ func (uc *UserUseCase) GetByAccessLevel(ctx context.Context, accessLevel string)
 	var usersDTO []*dto.UserDTO
 	for _, user := range users {
 		usersDTO = append(usersDTO, &dto.UserDTO{
-			UUID:        user.UUID,
-			Username:    user.Username,
-			AccessLevel: user.AccessLevel,
+			UUID:     user.UUID,
+			Username: user.Username,
+			Role:     user.Role,
 		})
 	}
 
 	return usersDTO, nil
 }
 
+func (uc *UserUseCase) GetRoles(ctx context.Context) ([]*models.Role, error) {
+	const op = "usecase.user.GetRoles"
+
+	// Getting roles with their permissions from db
+	roles, err := uc.roleRepo.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
 func (uc *UserUseCase) Update(ctx context.Context, UUID string, userDTO *dto.UpdateUserRequest) error {
 	const op = "usecase.user.Update"
 
@@ -183,7 +394,7 @@ func (uc *UserUseCase) Update(ctx context.Context, UUID string, userDTO *dto.Upd
 	}
 
 	// DTO to model
-	user := &models.User{AccessLevel: userDTO.AccessLevel}
+	user := &models.User{UUID: userUUID, Role: userDTO.Role}
 
 	// User validation
 	valid := uc.svc.Validate(user)
@@ -191,8 +402,23 @@ func (uc *UserUseCase) Update(ctx context.Context, UUID string, userDTO *dto.Upd
 		return fmt.Errorf("%s: %w", op, ErrInvalidUser)
 	}
 
+	// Checking role exists
+	_, err = uc.roleRepo.GetByName(ctx, user.Role)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Updating user in db with given user
-	err = uc.repo.Update(ctx, &models.User{UUID: userUUID, AccessLevel: userDTO.AccessLevel})
+	err = uc.repo.Update(ctx, user)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Tokens with permissions of old role are not accepted anymore
+	err = uc.revokeAccessTokens(ctx, userUUID)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -209,6 +435,12 @@ func (uc *UserUseCase) Delete(ctx context.Context, UUID string) error {
 		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
 	}
 
+	// Revoking tokens before sessions are deleted with user
+	err = uc.revokeAccessTokens(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	// Deleting user from db with given uuid
 	err = uc.repo.Delete(ctx, userUUID)
 	if err != nil {
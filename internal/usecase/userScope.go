@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/dto"
+	"slices"
+	"strings"
+)
+
+type UserScopeUseCase struct {
+	repo         interfaces.UserScopeRepository
+	repoUser     interfaces.UserRepository
+	repoGroup    interfaces.GroupRepository
+	repoLocation interfaces.LocationRepository
+	txManager    interfaces.TxManager
+}
+
+func NewUserScopeUseCase(
+	repo interfaces.UserScopeRepository,
+	repoUser interfaces.UserRepository,
+	repoGroup interfaces.GroupRepository,
+	repoLocation interfaces.LocationRepository,
+	txManager interfaces.TxManager,
+) *UserScopeUseCase {
+	return &UserScopeUseCase{
+		repo:         repo,
+		repoUser:     repoUser,
+		repoGroup:    repoGroup,
+		repoLocation: repoLocation,
+		txManager:    txManager,
+	}
+}
+
+func (uc *UserScopeUseCase) GetByUser(ctx context.Context, UUID string) (*dto.UserScopes, error) {
+	const op = "usecase.userScope.GetByUser"
+
+	// Parsing user uuid
+	userUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Checking user exists
+	_, err = uc.repoUser.GetByUUID(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Getting scopes of user from db
+	scopes, err := uc.repo.GetByUser(ctx, userUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Models to DTO
+	scopesDTO := &dto.UserScopes{Groups: []string{}, Locations: []string{}, GroupPrefixes: []string{}}
+	for _, scope := range scopes {
+		switch {
+		case scope.GroupUUID != nil:
+			scopesDTO.Groups = append(scopesDTO.Groups, scope.GroupUUID.String())
+		case scope.LocationUUID != nil:
+			scopesDTO.Locations = append(scopesDTO.Locations, scope.LocationUUID.String())
+		default:
+			scopesDTO.GroupPrefixes = append(scopesDTO.GroupPrefixes, scope.GroupPrefix)
+		}
+	}
+
+	return scopesDTO, nil
+}
+
+// Replace sets scopes of user, empty scopes let user edit every schedule
+func (uc *UserScopeUseCase) Replace(ctx context.Context, UUID string, scopesDTO *dto.UserScopes) error {
+	const op = "usecase.userScope.Replace"
+
+	// Parsing user uuid
+	userUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Checking user exists
+	_, err = uc.repoUser.GetByUUID(ctx, userUUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// DTO to models, duplicates are skipped
+	var scopes []*models.UserScope
+	var groups, locations []uuid.UUID
+	var prefixes []string
+	for _, UUID := range scopesDTO.Groups {
+		groupUUID, err := uuid.Parse(UUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+		}
+		if slices.Contains(groups, groupUUID) {
+			continue
+		}
+		_, err = uc.repoGroup.GetByUUID(ctx, groupUUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		groups = append(groups, groupUUID)
+		scopes = append(scopes, &models.UserScope{GroupUUID: &groupUUID})
+	}
+	for _, UUID := range scopesDTO.Locations {
+		locationUUID, err := uuid.Parse(UUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+		}
+		if slices.Contains(locations, locationUUID) {
+			continue
+		}
+		_, err = uc.repoLocation.GetByUUID(ctx, locationUUID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		locations = append(locations, locationUUID)
+		scopes = append(scopes, &models.UserScope{LocationUUID: &locationUUID})
+	}
+	for _, prefix := range scopesDTO.GroupPrefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			return fmt.Errorf("%s: %w", op, ErrInvalidScope)
+		}
+		if slices.Contains(prefixes, prefix) {
+			continue
+		}
+		prefixes = append(prefixes, prefix)
+		scopes = append(scopes, &models.UserScope{GroupPrefix: prefix})
+	}
+
+	for _, scope := range scopes {
+		scope.UUID, err = uuid.NewUUID()
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+		}
+		scope.UserUUID = userUUID
+	}
+
+	// Replacing scopes of user in db
+	err = uc.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		return uc.repo.Replace(ctx, userUUID, scopes)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"raspyx/config"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"raspyx/internal/repository"
+	"testing"
+	"time"
+)
+
+type memoryUserRepository struct {
+	users map[uuid.UUID]*models.User
+}
+
+func (r *memoryUserRepository) Create(_ context.Context, user *models.User) error {
+	r.users[user.UUID] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Get(_ context.Context) ([]*models.User, error) {
+	return nil, nil
+}
+
+func (r *memoryUserRepository) List(_ context.Context, _ *models.ListQuery) (*models.ListPage[*models.User], error) {
+	return &models.ListPage[*models.User]{}, nil
+}
+
+func (r *memoryUserRepository) GetByUUID(_ context.Context, uuid uuid.UUID) (*models.User, error) {
+	user, ok := r.users[uuid]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return user, nil
+}
+
+func (r *memoryUserRepository) GetByUsername(_ context.Context, username string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryUserRepository) GetByRole(_ context.Context, _ string) ([]*models.User, error) {
+	return nil, nil
+}
+
+func (r *memoryUserRepository) Update(_ context.Context, user *models.User) error {
+	r.users[user.UUID].Role = user.Role
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(_ context.Context, uuid uuid.UUID) error {
+	delete(r.users, uuid)
+	return nil
+}
+
+type stubRoleRepository struct{}
+
+func (r stubRoleRepository) Get(_ context.Context) ([]*models.Role, error) {
+	return nil, nil
+}
+
+func (r stubRoleRepository) GetByName(_ context.Context, name string) (*models.Role, error) {
+	return &models.Role{Name: name, Permissions: []string{models.PermScheduleRead}}, nil
+}
+
+type memoryRefreshTokenRepository struct {
+	tokens []*models.RefreshToken
+}
+
+func (r *memoryRefreshTokenRepository) Create(_ context.Context, token *models.RefreshToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryRefreshTokenRepository) GetByHash(_ context.Context, hash string) (*models.RefreshToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == hash {
+			found := *token
+			return &found, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (r *memoryRefreshTokenRepository) GetCreatedSince(_ context.Context, userUUID uuid.UUID, since time.Time) ([]*models.RefreshToken, error) {
+	var tokens []*models.RefreshToken
+	for _, token := range r.tokens {
+		if token.UserUUID == userUUID && token.CreatedAt.After(since) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *memoryRefreshTokenRepository) Revoke(_ context.Context, uuid uuid.UUID) error {
+	for _, token := range r.tokens {
+		if token.UUID == uuid && token.RevokedAt == nil {
+			now := time.Now()
+			token.RevokedAt = &now
+			return nil
+		}
+	}
+	return repository.ErrNotFound
+}
+
+func (r *memoryRefreshTokenRepository) RevokeFamily(_ context.Context, family uuid.UUID) ([]uuid.UUID, error) {
+	var jtis []uuid.UUID
+	for _, token := range r.tokens {
+		if token.Family == family {
+			now := time.Now()
+			token.RevokedAt = &now
+			jtis = append(jtis, token.AccessJTI)
+		}
+	}
+	return jtis, nil
+}
+
+type memoryDenylist map[string]bool
+
+func (d memoryDenylist) Deny(_ context.Context, jti string, _ time.Duration) error {
+	d[jti] = true
+	return nil
+}
+
+func (d memoryDenylist) IsDenied(_ context.Context, jti string) (bool, error) {
+	return d[jti], nil
+}
+
+func TestUserUseCase_UpdateRevokesRotatedAccessTokens(t *testing.T) {
+	ctx := context.Background()
+	svc := services.NewUserService()
+
+	passwordHash, err := svc.GeneratePasswordHash("password")
+	require.NoError(t, err)
+	user := &models.User{UUID: uuid.New(), Username: "moderator", PasswordHash: passwordHash, Role: models.RoleModerator}
+
+	denylist := memoryDenylist{}
+	uc := NewUserUseCase(
+		&memoryUserRepository{users: map[uuid.UUID]*models.User{user.UUID: user}},
+		stubRoleRepository{},
+		&memoryRefreshTokenRepository{},
+		denylist,
+		*svc,
+		config.JWT{JWTSecret: "secret", AccessTTL: 15, RefreshTTL: 24},
+	)
+
+	jtiOf := func(token string) string {
+		claims := jwt.MapClaims{}
+		_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+		require.NoError(t, err)
+		return claims["jti"].(string)
+	}
+
+	login, err := uc.Login(ctx, &dto.LoginUserRequest{Username: "moderator", Password: "password"})
+	require.NoError(t, err)
+	refreshed, err := uc.Refresh(ctx, &dto.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	require.NoError(t, err)
+
+	// Access token issued before refresh is replaced by the new one
+	assert.True(t, denylist[jtiOf(login.Token)])
+	assert.False(t, denylist[jtiOf(refreshed.Token)])
+
+	// Demoted user keeps no token with permissions of old role
+	delete(denylist, jtiOf(login.Token))
+	err = uc.Update(ctx, user.UUID.String(), &dto.UpdateUserRequest{Role: models.RoleViewer})
+	require.NoError(t, err)
+
+	assert.True(t, denylist[jtiOf(login.Token)])
+	assert.True(t, denylist[jtiOf(refreshed.Token)])
+}
@@ -0,0 +1,170 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"raspyx/internal/dto"
+	"raspyx/internal/repository"
+	"strings"
+)
+
+// Number of last deliveries returned for webhook
+const webhookDeliveriesLimit = 100
+
+type WebhookUseCase struct {
+	repo         interfaces.WebhookRepository
+	repoDelivery interfaces.WebhookDeliveryRepository
+	repoGroup    interfaces.GroupRepository
+	repoTeacher  interfaces.TeacherRepository
+	repoRoom     interfaces.RoomRepository
+	svc          services.WebhookService
+}
+
+func NewWebhookUseCase(
+	repo interfaces.WebhookRepository,
+	repoDelivery interfaces.WebhookDeliveryRepository,
+	repoGroup interfaces.GroupRepository,
+	repoTeacher interfaces.TeacherRepository,
+	repoRoom interfaces.RoomRepository,
+	svc services.WebhookService,
+) *WebhookUseCase {
+	return &WebhookUseCase{
+		repo:         repo,
+		repoDelivery: repoDelivery,
+		repoGroup:    repoGroup,
+		repoTeacher:  repoTeacher,
+		repoRoom:     repoRoom,
+		svc:          svc,
+	}
+}
+
+// resolveResource converts resource from request to the value pairs refer to it by
+func (uc *WebhookUseCase) resolveResource(ctx context.Context, kind, resource string) (string, error) {
+	const op = "usecase.webhook.resolveResource"
+
+	switch kind {
+	case models.ResourceGroup:
+		group, err := uc.repoGroup.GetByNumber(ctx, resource)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		return group.Number, nil
+	case models.ResourceTeacher:
+		teacherUUID, err := uuid.Parse(resource)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+		}
+		teacher, err := uc.repoTeacher.GetByUUID(ctx, teacherUUID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		return strings.TrimSpace(fmt.Sprintf("%v %v %v", teacher.SecondName, teacher.FirstName, teacher.MiddleName)), nil
+	case models.ResourceRoom:
+		room, err := uc.repoRoom.GetByNumber(ctx, resource)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		return room.Number, nil
+	}
+
+	return "", fmt.Errorf("%s: %w", op, ErrInvalidWebhook)
+}
+
+func (uc *WebhookUseCase) Create(ctx context.Context, webhookDTO *dto.WebhookRequest) (*dto.CreateWebhookResponse, error) {
+	const op = "usecase.webhook.Create"
+
+	webhook := &models.Webhook{
+		URL:      strings.TrimSpace(webhookDTO.URL),
+		Secret:   webhookDTO.Secret,
+		Kind:     webhookDTO.Kind,
+		Resource: strings.TrimSpace(webhookDTO.Resource),
+		Owner:    contextUsername(ctx),
+	}
+
+	// Validation url, secret and filter
+	if valid := uc.svc.Validate(webhook); !valid || webhook.Owner == "" {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidWebhook)
+	}
+
+	resource, err := uc.resolveResource(ctx, webhook.Kind, webhook.Resource)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	webhook.Resource = resource
+
+	// Generating new uuid
+	newUUID, err := uuid.NewUUID()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrGeneratingUUID)
+	}
+	webhook.UUID = newUUID
+
+	// Adding webhook to db
+	err = uc.repo.Create(ctx, webhook)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &dto.CreateWebhookResponse{UUID: webhook.UUID}, nil
+}
+
+// Get returns webhooks of current user
+func (uc *WebhookUseCase) Get(ctx context.Context) ([]*models.Webhook, error) {
+	const op = "usecase.webhook.Get"
+
+	webhooks, err := uc.repo.GetByOwner(ctx, contextUsername(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return webhooks, nil
+}
+
+// GetDeliveries returns last deliveries of webhook of current user with their attempts
+func (uc *WebhookUseCase) GetDeliveries(ctx context.Context, UUID string) ([]*models.WebhookDelivery, error) {
+	const op = "usecase.webhook.GetDeliveries"
+
+	// Parsing webhook uuid
+	webhookUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Webhooks of other users are not found
+	webhook, err := uc.repo.GetByUUID(ctx, webhookUUID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if webhook.Owner != contextUsername(ctx) {
+		return nil, fmt.Errorf("%s: webhook %w", op, repository.ErrNotFound)
+	}
+
+	deliveries, err := uc.repoDelivery.GetByWebhookUUID(ctx, webhookUUID, webhookDeliveriesLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deliveries, nil
+}
+
+func (uc *WebhookUseCase) Delete(ctx context.Context, UUID string) error {
+	const op = "usecase.webhook.Delete"
+
+	// Parsing webhook uuid
+	webhookUUID, err := uuid.Parse(UUID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+	}
+
+	// Deleting webhook of current user from db with given uuid
+	err = uc.repo.Delete(ctx, webhookUUID, contextUsername(ctx))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
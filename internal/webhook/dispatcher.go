@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"raspyx/config"
+	"raspyx/internal/domain/interfaces"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"syscall"
+	"time"
+)
+
+// Number of deliveries sent per check of the queue
+const batchSize = 50
+
+var (
+	errForbiddenAddress = errors.New("webhook address is not allowed")
+	errUnexpectedStatus = errors.New("unexpected status")
+)
+
+// Dispatcher sends queued webhook deliveries, failed ones are retried with exponential backoff
+type Dispatcher struct {
+	client *http.Client
+	repo   interfaces.WebhookDeliveryRepository
+	svc    services.WebhookService
+	log    *slog.Logger
+	cfg    config.Webhook
+}
+
+func NewDispatcher(
+	timeout time.Duration,
+	repo interfaces.WebhookDeliveryRepository,
+	svc services.WebhookService,
+	log *slog.Logger,
+	cfg config.Webhook,
+) *Dispatcher {
+	return &Dispatcher{
+		client: newClient(timeout, svc),
+		repo:   repo,
+		svc:    svc,
+		log:    log.With(slog.String("module", "WebhookDispatcher")),
+		cfg:    cfg,
+	}
+}
+
+// newClient returns client which only connects to public addresses and does not follow redirects.
+// Address is checked after resolving, so hostnames resolved to internal addresses are refused too
+func newClient(timeout time.Duration, svc services.WebhookService) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !svc.PublicIP(ip) {
+				return errForbiddenAddress
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: timeout,
+		// Proxy from environment would be dialed instead of webhook address
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     time.Minute,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run checks the queue every cfg.Interval seconds until ctx is done
+func (d *Dispatcher) Run(ctx context.Context) {
+	// Set interval to 10 seconds if it too small
+	if d.cfg.Interval < 1 {
+		d.cfg.Interval = 10
+	}
+
+	ticker := time.NewTicker(time.Duration(d.cfg.Interval) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.dispatch(ctx)
+			if err != nil {
+				d.log.Error(fmt.Sprintf("error dispatching webhooks: %v", err))
+			}
+		}
+	}
+}
+
+func (d *Dispatcher) dispatch(ctx context.Context) error {
+	// Deliveries are leased for longer than request may take
+	deliveries, err := d.repo.ClaimDue(ctx, batchSize, 2*d.client.Timeout+time.Minute)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+			err = d.deliver(ctx, delivery)
+			if err != nil {
+				d.log.Error(fmt.Sprintf("error saving delivery %v: %v", delivery.UUID, err))
+			}
+		}
+	}
+
+	return nil
+}
+
+// deliver sends delivery once, records the attempt and schedules retry if it failed
+func (d *Dispatcher) deliver(ctx context.Context, delivery *models.WebhookDelivery) error {
+	attemptUUID, err := uuid.NewUUID()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	statusCode, sendErr := d.send(ctx, delivery)
+
+	attempt := &models.WebhookAttempt{
+		UUID:         attemptUUID,
+		DeliveryUUID: delivery.UUID,
+		StatusCode:   statusCode,
+		AttemptedAt:  now,
+	}
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	delivery.LastError = ""
+
+	switch {
+	case sendErr == nil:
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.cfg.MaxAttempts:
+		attempt.Error, delivery.LastError = deliveryError(sendErr), deliveryError(sendErr)
+		delivery.Status = models.DeliveryFailed
+		d.log.Warn(fmt.Sprintf("giving up delivery %v after %v attempts: %v", delivery.UUID, delivery.Attempts, sendErr))
+	default:
+		attempt.Error, delivery.LastError = deliveryError(sendErr), deliveryError(sendErr)
+		delivery.NextAttemptAt = now.Add(d.svc.Backoff(delivery.Attempts))
+		d.log.Debug(fmt.Sprintf("delivery %v failed: %v", delivery.UUID, sendErr))
+	}
+
+	err = d.repo.CreateAttempt(ctx, attempt)
+	if err != nil {
+		return err
+	}
+
+	return d.repo.Update(ctx, delivery)
+}
+
+// send posts signed payload to webhook url, any status except 2xx is an error
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "raspyx-webhook")
+	req.Header.Set("X-Raspyx-Event", delivery.Event)
+	req.Header.Set("X-Raspyx-Delivery", delivery.UUID.String())
+	req.Header.Set("X-Raspyx-Signature", d.svc.Sign(delivery.Secret, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w %v", errUnexpectedStatus, resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// deliveryError is error shown to owner of webhook, errors of connection are not shown
+// as they tell about network of the server
+func deliveryError(err error) string {
+	switch {
+	case errors.Is(err, errUnexpectedStatus):
+		return err.Error()
+	case errors.Is(err, errForbiddenAddress):
+		return errForbiddenAddress.Error()
+	default:
+		return "request failed"
+	}
+}
@@ -0,0 +1,230 @@
+package webhook
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"raspyx/config"
+	"raspyx/internal/domain/models"
+	"raspyx/internal/domain/services"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryDeliveryRepository keeps deliveries in memory instead of postgres
+type memoryDeliveryRepository struct {
+	mu         sync.Mutex
+	deliveries []*models.WebhookDelivery
+	attempts   []*models.WebhookAttempt
+}
+
+func (r *memoryDeliveryRepository) Create(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *memoryDeliveryRepository) GetByWebhookUUID(_ context.Context, webhookUUID uuid.UUID, _ int) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deliveries []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookUUID == webhookUUID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memoryDeliveryRepository) ClaimDue(_ context.Context, limit int, lease time.Duration) ([]*models.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if len(due) < limit && delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(time.Now()) {
+			delivery.NextAttemptAt = time.Now().Add(lease)
+			claimed := *delivery
+			due = append(due, &claimed)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryDeliveryRepository) Update(_ context.Context, delivery *models.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, d := range r.deliveries {
+		if d.UUID == delivery.UUID {
+			updated := *delivery
+			r.deliveries[i] = &updated
+		}
+	}
+	return nil
+}
+
+func (r *memoryDeliveryRepository) CreateAttempt(_ context.Context, attempt *models.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func TestDispatcher_dispatch(t *testing.T) {
+	const secret = "0123456789abcdef"
+	payload := []byte(`{"event":"schedule.changed"}`)
+	svc := services.NewWebhookService()
+
+	// Stub fails first request and accepts the next ones
+	var (
+		mu       sync.Mutex
+		requests int
+	)
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if got, want := r.Header.Get("X-Raspyx-Signature"), svc.Sign(secret, body); got != want {
+			t.Errorf("signature = %v, want %v", got, want)
+		}
+		if got := r.Header.Get("X-Raspyx-Event"); got != models.EventScheduleChanged {
+			t.Errorf("event = %v, want %v", got, models.EventScheduleChanged)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer stub.Close()
+
+	repo := &memoryDeliveryRepository{}
+	delivery := &models.WebhookDelivery{
+		UUID:          uuid.New(),
+		WebhookUUID:   uuid.New(),
+		URL:           stub.URL,
+		Secret:        secret,
+		Event:         models.EventScheduleChanged,
+		Payload:       payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	_ = repo.Create(context.Background(), delivery)
+
+	d := NewDispatcher(time.Second, repo, *svc, slog.New(slog.NewTextHandler(io.Discard, nil)), config.Webhook{MaxAttempts: 3})
+	// Stub listens on loopback which is refused by client of dispatcher
+	d.client = stub.Client()
+
+	// First attempt fails and is postponed by backoff
+	if err := d.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch() error = %v", err)
+	}
+	got := repo.deliveries[0]
+	if got.Status != models.DeliveryPending || got.Attempts != 1 || got.LastStatusCode != http.StatusInternalServerError {
+		t.Fatalf("after failed attempt delivery = %+v", got)
+	}
+	if got.NextAttemptAt.Before(time.Now().Add(svc.Backoff(1) - time.Second)) {
+		t.Errorf("next attempt at %v is not postponed by backoff", got.NextAttemptAt)
+	}
+
+	// Retry is not sent before its time
+	if err := d.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch() error = %v", err)
+	}
+	mu.Lock()
+	if requests != 1 {
+		t.Fatalf("requests = %v, want 1", requests)
+	}
+	mu.Unlock()
+
+	// Retry is sent when its time has come
+	repo.deliveries[0].NextAttemptAt = time.Now()
+	if err := d.dispatch(context.Background()); err != nil {
+		t.Fatalf("dispatch() error = %v", err)
+	}
+	got = repo.deliveries[0]
+	if got.Status != models.DeliveryDelivered || got.Attempts != 2 || got.DeliveredAt == nil {
+		t.Fatalf("after successful attempt delivery = %+v", got)
+	}
+	if len(repo.attempts) != 2 || repo.attempts[0].Error == "" || repo.attempts[1].Error != "" {
+		t.Errorf("attempts are not recorded properly: %+v", repo.attempts)
+	}
+}
+
+func TestDispatcher_deliverGivesUp(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer stub.Close()
+
+	repo := &memoryDeliveryRepository{}
+	delivery := &models.WebhookDelivery{
+		UUID:     uuid.New(),
+		URL:      stub.URL,
+		Secret:   "0123456789abcdef",
+		Status:   models.DeliveryPending,
+		Attempts: 2,
+	}
+	_ = repo.Create(context.Background(), delivery)
+
+	d := NewDispatcher(time.Second, repo, *services.NewWebhookService(), slog.New(slog.NewTextHandler(io.Discard, nil)), config.Webhook{MaxAttempts: 3})
+	d.client = stub.Client()
+	if err := d.deliver(context.Background(), delivery); err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+
+	if got := repo.deliveries[0]; got.Status != models.DeliveryFailed || got.Attempts != 3 {
+		t.Errorf("delivery = %+v, want failed after 3 attempts", got)
+	}
+}
+
+func TestDispatcher_deliverRefusesInternalAddress(t *testing.T) {
+	var requested bool
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer stub.Close()
+
+	repo := &memoryDeliveryRepository{}
+	delivery := &models.WebhookDelivery{
+		UUID:   uuid.New(),
+		URL:    stub.URL,
+		Secret: "0123456789abcdef",
+		Status: models.DeliveryPending,
+	}
+	_ = repo.Create(context.Background(), delivery)
+
+	d := NewDispatcher(time.Second, repo, *services.NewWebhookService(), slog.New(slog.NewTextHandler(io.Discard, nil)), config.Webhook{MaxAttempts: 3})
+	if err := d.deliver(context.Background(), delivery); err != nil {
+		t.Fatalf("deliver() error = %v", err)
+	}
+
+	if requested {
+		t.Fatal("request to loopback address was sent")
+	}
+	if got := repo.deliveries[0]; got.LastError != errForbiddenAddress.Error() || got.LastStatusCode != 0 {
+		t.Errorf("delivery = %+v, want refused address without details", got)
+	}
+}
+
+func TestDispatcher_sendDoesNotFollowRedirects(t *testing.T) {
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer stub.Close()
+
+	d := NewDispatcher(time.Second, &memoryDeliveryRepository{}, *services.NewWebhookService(), slog.New(slog.NewTextHandler(io.Discard, nil)), config.Webhook{MaxAttempts: 3})
+	// Stub itself is allowed, target of redirect is not
+	d.client.Transport = stub.Client().Transport
+
+	statusCode, err := d.send(context.Background(), &models.WebhookDelivery{UUID: uuid.New(), URL: stub.URL})
+	if statusCode != http.StatusFound || err == nil {
+		t.Errorf("send() = %v, %v, want redirect status as error", statusCode, err)
+	}
+}
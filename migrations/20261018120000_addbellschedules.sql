@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS bell_schedules (
+    uuid UUID PRIMARY KEY,
+    location_uuid UUID REFERENCES locations(uuid) ON DELETE CASCADE,
+    start_date DATE,
+    end_date DATE,
+    pair_num INT NOT NULL,
+    start_time TIME NOT NULL,
+    end_time TIME NOT NULL,
+    UNIQUE NULLS NOT DISTINCT (location_uuid, start_date, end_date, pair_num)
+);
+
+INSERT INTO bell_schedules (uuid, pair_num, start_time, end_time)
+VALUES (gen_random_uuid(), 1, '09:00:00', '10:30:00'),
+       (gen_random_uuid(), 2, '10:40:00', '12:10:00'),
+       (gen_random_uuid(), 3, '12:20:00', '13:50:00'),
+       (gen_random_uuid(), 4, '14:30:00', '16:00:00'),
+       (gen_random_uuid(), 5, '16:10:00', '17:40:00'),
+       (gen_random_uuid(), 6, '17:50:00', '19:20:00'),
+       (gen_random_uuid(), 7, '19:30:00', '21:00:00');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bell_schedules;
+-- +goose StatementEnd
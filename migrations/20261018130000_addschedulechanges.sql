@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS schedule_changes (
+    uuid UUID PRIMARY KEY,
+    schedule_uuid UUID NOT NULL,
+    group_number VARCHAR NOT NULL,
+    action VARCHAR NOT NULL,
+    before JSONB,
+    after JSONB,
+    source VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS schedule_changes_group_number_created_at_idx
+    ON schedule_changes (group_number, created_at);
+CREATE INDEX IF NOT EXISTS schedule_changes_created_at_idx
+    ON schedule_changes (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS schedule_changes;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhooks (
+    uuid UUID PRIMARY KEY,
+    url VARCHAR NOT NULL,
+    secret VARCHAR NOT NULL,
+    kind VARCHAR NOT NULL,
+    resource VARCHAR NOT NULL,
+    owner VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_kind_resource_idx ON webhooks (kind, resource);
+CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    uuid UUID PRIMARY KEY,
+    webhook_uuid UUID NOT NULL REFERENCES webhooks(uuid) ON DELETE CASCADE,
+    event VARCHAR NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_status_code INT NOT NULL DEFAULT 0,
+    last_error VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_status_next_attempt_at_idx
+    ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_uuid_idx ON webhook_deliveries (webhook_uuid);
+
+CREATE TABLE IF NOT EXISTS webhook_attempts (
+    uuid UUID PRIMARY KEY,
+    delivery_uuid UUID NOT NULL REFERENCES webhook_deliveries(uuid) ON DELETE CASCADE,
+    status_code INT NOT NULL DEFAULT 0,
+    error VARCHAR NOT NULL DEFAULT '',
+    attempted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS webhook_attempts_delivery_uuid_idx ON webhook_attempts (delivery_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS webhook_attempts;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS parser_runs (
+    uuid UUID PRIMARY KEY,
+    trigger VARCHAR NOT NULL,
+    groups VARCHAR[] NOT NULL DEFAULT '{}',
+    status VARCHAR NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    groups_processed INTEGER NOT NULL DEFAULT 0,
+    skipped INTEGER NOT NULL DEFAULT 0,
+    added JSONB NOT NULL DEFAULT '{}',
+    errors JSONB NOT NULL DEFAULT '{}',
+    error VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS parser_runs_started_at_idx ON parser_runs (started_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS parser_runs;
+-- +goose StatementEnd
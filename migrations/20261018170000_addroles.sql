@@ -0,0 +1,89 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR PRIMARY KEY,
+    description VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR PRIMARY KEY,
+    description VARCHAR NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+INSERT INTO roles (name, description) VALUES
+    ('viewer', 'Registered user, manages own webhooks'),
+    ('editor', 'Edits schedule'),
+    ('moderator', 'Edits schedule and catalogs, sees users and parser runs'),
+    ('admin', 'Manages users and runs parser')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('schedule:read', 'Read schedules of every resource, conflicts and availability'),
+    ('schedule:write', 'Create, update and delete pairs and bell schedule'),
+    ('catalog:read', 'Read groups, teachers, rooms, subjects, types and locations'),
+    ('catalog:write', 'Create, update and delete groups, teachers, rooms, subjects, types and locations'),
+    ('users:read', 'Read users and roles'),
+    ('users:manage', 'Change roles of users and delete them'),
+    ('parser:read', 'Read parser runs'),
+    ('parser:run', 'Start parser runs')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('editor', 'schedule:read'),
+    ('editor', 'schedule:write'),
+    ('editor', 'catalog:read'),
+    ('moderator', 'schedule:read'),
+    ('moderator', 'schedule:write'),
+    ('moderator', 'catalog:read'),
+    ('moderator', 'catalog:write'),
+    ('moderator', 'users:read'),
+    ('moderator', 'parser:read'),
+    ('admin', 'schedule:read'),
+    ('admin', 'schedule:write'),
+    ('admin', 'catalog:read'),
+    ('admin', 'catalog:write'),
+    ('admin', 'users:read'),
+    ('admin', 'users:manage'),
+    ('admin', 'parser:read'),
+    ('admin', 'parser:run')
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role VARCHAR NOT NULL DEFAULT 'viewer' REFERENCES roles(name);
+
+-- Access levels: 0 - user, 50 - moderator, 99 - admin
+UPDATE users SET role = CASE
+    WHEN access_level >= 99 THEN 'admin'
+    WHEN access_level >= 50 THEN 'moderator'
+    ELSE 'viewer'
+END;
+
+ALTER TABLE users DROP COLUMN IF EXISTS access_level;
+
+CREATE INDEX IF NOT EXISTS idx_users_role ON users(role);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN IF NOT EXISTS access_level INT NOT NULL DEFAULT 0;
+
+-- Editors had no access level, they become users
+UPDATE users SET access_level = CASE role
+    WHEN 'admin' THEN 99
+    WHEN 'moderator' THEN 50
+    ELSE 0
+END;
+
+ALTER TABLE users ALTER COLUMN access_level DROP DEFAULT;
+DROP INDEX IF EXISTS idx_users_role;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS user_scopes (
+    uuid UUID PRIMARY KEY,
+    user_uuid UUID NOT NULL REFERENCES users(uuid) ON DELETE CASCADE,
+    group_uuid UUID REFERENCES groups(uuid) ON DELETE CASCADE,
+    location_uuid UUID REFERENCES locations(uuid) ON DELETE CASCADE,
+    group_prefix VARCHAR,
+    -- Scope is exactly one of group, location or prefix of group number
+    CHECK (num_nonnulls(group_uuid, location_uuid, group_prefix) = 1)
+);
+
+CREATE INDEX IF NOT EXISTS user_scopes_user_uuid_idx ON user_scopes (user_uuid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_scopes;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Expressions are the same as in search queries, so they can use indexes
+CREATE INDEX IF NOT EXISTS groups_number_trgm_idx ON groups USING GIN (replace(lower(number), 'ё', 'е') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS rooms_number_trgm_idx ON rooms USING GIN (replace(lower(number), 'ё', 'е') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS subjects_name_trgm_idx ON subjects USING GIN (replace(lower(name), 'ё', 'е') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS teachers_full_name_trgm_idx ON teachers
+    USING GIN (replace(lower(TRIM(second_name || ' ' || first_name || ' ' || COALESCE(middle_name, ''))), 'ё', 'е') gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS teachers_second_name_trgm_idx ON teachers USING GIN (replace(lower(second_name), 'ё', 'е') gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS teachers_second_name_trgm_idx;
+DROP INDEX IF EXISTS teachers_full_name_trgm_idx;
+DROP INDEX IF EXISTS subjects_name_trgm_idx;
+DROP INDEX IF EXISTS rooms_number_trgm_idx;
+DROP INDEX IF EXISTS groups_number_trgm_idx;
+DROP EXTENSION IF EXISTS pg_trgm;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Webhooks make requests from the server, so they are not available to self-registered viewers
+INSERT INTO permissions (name, description) VALUES
+    ('webhooks:manage', 'Create, read and delete own webhooks')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('editor', 'webhooks:manage'),
+    ('moderator', 'webhooks:manage'),
+    ('admin', 'webhooks:manage')
+ON CONFLICT DO NOTHING;
+
+UPDATE roles SET description = 'Registered user' WHERE name = 'viewer';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE roles SET description = 'Registered user, manages own webhooks' WHERE name = 'viewer';
+
+DELETE FROM permissions WHERE name = 'webhooks:manage';
+-- +goose StatementEnd
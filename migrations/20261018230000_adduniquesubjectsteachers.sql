@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Duplicates added by concurrent parser workers are merged into the first row of each name
+WITH keep AS (
+    SELECT uuid, first_value(uuid) OVER (PARTITION BY name ORDER BY uuid) AS keep_uuid
+    FROM subjects
+)
+UPDATE schedule
+SET subject_uuid = keep.keep_uuid
+FROM keep
+WHERE schedule.subject_uuid = keep.uuid AND keep.uuid <> keep.keep_uuid;
+
+DELETE FROM subjects
+WHERE uuid IN (
+    SELECT uuid FROM (
+        SELECT uuid, row_number() OVER (PARTITION BY name ORDER BY uuid) AS n
+        FROM subjects
+    ) ranked
+    WHERE n > 1
+);
+
+CREATE TEMPORARY TABLE teacher_duplicates ON COMMIT DROP AS
+SELECT uuid, keep_uuid FROM (
+    SELECT uuid, first_value(uuid) OVER (
+        PARTITION BY second_name, first_name, COALESCE(middle_name, '') ORDER BY uuid
+    ) AS keep_uuid
+    FROM teachers
+) ranked
+WHERE uuid <> keep_uuid;
+
+INSERT INTO teachers_to_schedule (teacher_uuid, schedule_uuid)
+SELECT teacher_duplicates.keep_uuid, teachers_to_schedule.schedule_uuid
+FROM teachers_to_schedule
+JOIN teacher_duplicates ON teacher_duplicates.uuid = teachers_to_schedule.teacher_uuid
+ON CONFLICT DO NOTHING;
+
+-- Links of duplicates are deleted by cascade
+DELETE FROM teachers WHERE uuid IN (SELECT uuid FROM teacher_duplicates);
+
+CREATE UNIQUE INDEX IF NOT EXISTS subjects_name_key ON subjects (name);
+CREATE UNIQUE INDEX IF NOT EXISTS teachers_full_name_key ON teachers (second_name, first_name, COALESCE(middle_name, ''));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS teachers_full_name_key;
+DROP INDEX IF EXISTS subjects_name_key;
+-- +goose StatementEnd